@@ -0,0 +1,212 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"demo-app/internal/analytics"
+	"demo-app/internal/export"
+	"demo-app/internal/reporting"
+)
+
+// Exit codes of the subcommands.
+const (
+	exitOK     = 0
+	exitError  = 1 // SDK, manifest or I/O failure
+	exitUsage  = 2
+	exitDenied = 3 // at least one call was denied by the license
+)
+
+const cliUsage = `usage: demo [options] [command]
+
+Without a command the interactive menu starts.
+
+Commands:
+  run <feature> [--count N]     run a feature: basic-analytics, advanced-analytics,
+                                pdf-export, excel-export, schedule-report
+  license show                  show feature status and limits
+  project create [--count N]    create projects (capacity demo)
+  api call [--count N]          call the demo API (TPS demo)
+  jobs [--n 15] [--hold 300ms]  run concurrent jobs (concurrency demo)
+  switch <tier|manifest> [--product-id ID] [--keep-stats]
+                                register again as another tier (basic, pro, ent)
+                                or manifest and show its license
+
+Every command accepts --json to print a JSON summary on stdout. Exit codes:
+0 ok, 1 error, 2 usage, 3 denied.
+`
+
+// cliFeatures maps run targets to the operations of the menu.
+var cliFeatures = map[string]func(io.Writer) opResult{
+	"basic-analytics":    runBasicAnalytics,
+	"advanced-analytics": runAdvancedAnalytics,
+	"pdf-export":         exportToPDF,
+	"excel-export":       exportToExcel,
+	"schedule-report":    scheduleReport,
+}
+
+// cliOutput is the --json summary of a subcommand.
+type cliOutput struct {
+	Command  string          `json:"command"`
+	Results  []opResult      `json:"results,omitempty"`
+	Features []featureStatus `json:"features,omitempty"`
+	Jobs     *jobsResult     `json:"jobs,omitempty"`
+	Config   *demoConfig     `json:"config,omitempty"` // configuration after a switch
+	Stats    *DemoStats      `json:"stats,omitempty"`  // demo stats after the command
+	Summary  map[string]int  `json:"summary"`          // outcome -> count
+	ExitCode int             `json:"exit_code"`
+}
+
+// connect sets up the SDK client for a subcommand, printing its progress to
+// w, and returns the func that closes it. Tests replace it to run without an
+// LCC server.
+var connect = func(w io.Writer, cfg *demoConfig) (func(), error) {
+	if err := setup(w, cfg); err != nil {
+		return nil, err
+	}
+	return func() {
+		if cli, _ := registered(); cli != nil {
+			cli.Close()
+		}
+	}, nil
+}
+
+// runCLI runs one subcommand, printing to stdout and stderr, and returns the
+// process exit code.
+func runCLI(cfg *demoConfig, args []string, stdout, stderr io.Writer) int {
+	if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		fmt.Fprint(stdout, cliUsage+"\n"+configUsage)
+		return exitOK
+	}
+
+	fs := flag.NewFlagSet("demo "+args[0], flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	asJSON := fs.Bool("json", false, "print a JSON summary")
+	count := fs.Int("count", 1, "number of calls")
+	jobs := fs.Int("n", 15, "number of concurrent jobs")
+	hold := fs.Duration("hold", 300*time.Millisecond, "how long each job holds its slot")
+	productID := fs.String("product-id", "", "product to switch to")
+	keepStats := fs.Bool("keep-stats", false, "keep the stats across a switch")
+
+	// Positional words come first: "run pdf-export --count 3"
+	var words []string
+	rest := args
+	for len(rest) > 0 && !strings.HasPrefix(rest[0], "-") {
+		words = append(words, rest[0])
+		rest = rest[1:]
+	}
+	if err := fs.Parse(rest); err != nil || fs.NArg() > 0 || *count < 1 || *jobs < 1 {
+		fmt.Fprintf(stderr, "demo %s: invalid arguments\n\n%s", strings.Join(args, " "), cliUsage)
+		return exitUsage
+	}
+
+	// With --json the operations' progress goes to stderr and stdout carries
+	// only the summary.
+	w := stdout
+	if *asJSON {
+		w = stderr
+	}
+
+	var op func(*cliOutput)
+	repeat := func(fn func(io.Writer) opResult) func(*cliOutput) {
+		return func(out *cliOutput) {
+			for i := 0; i < *count; i++ {
+				out.Results = append(out.Results, fn(w))
+			}
+		}
+	}
+	switch cmd := strings.Join(words, " "); {
+	case len(words) == 2 && words[0] == "run" && cliFeatures[words[1]] != nil:
+		op = repeat(cliFeatures[words[1]])
+	case cmd == "license show":
+		op = func(out *cliOutput) { out.Features = showLicenseInfo(w) }
+	case cmd == "project create":
+		op = repeat(createProjectDemo)
+	case cmd == "api call":
+		op = repeat(callDemoAPIDemo)
+	case cmd == "jobs":
+		op = func(out *cliOutput) {
+			res := simulateConcurrentJobs(w, *jobs, *hold)
+			out.Jobs = &res
+			out.Results = res.Results
+		}
+	case len(words) == 2 && words[0] == "switch":
+		op = func(out *cliOutput) {
+			next, err := switchTier(w, cfg, words[1], *productID, *keepStats)
+			if err != nil {
+				fmt.Fprintln(stderr, err)
+				out.Summary[outcomeError]++
+				return
+			}
+			out.Config = next
+			out.Features = showLicenseInfo(w)
+		}
+	default:
+		fmt.Fprintf(stderr, "demo: unknown command %q\n\n%s", cmd, cliUsage)
+		return exitUsage
+	}
+
+	// The gated business functions print their own progress
+	analytics.SetOutput(w)
+	export.SetOutput(w)
+	reporting.SetOutput(w)
+	defer func() {
+		analytics.SetOutput(nil)
+		export.SetOutput(nil)
+		reporting.SetOutput(nil)
+	}()
+
+	out := &cliOutput{Command: strings.Join(words, " "), Summary: map[string]int{}}
+	disconnect, err := connect(w, cfg)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		out.ExitCode = exitError
+		out.Summary[outcomeError] = 1
+		return writeCLIOutput(stdout, out, *asJSON)
+	}
+	defer disconnect()
+
+	op(out)
+	statsMu.Lock()
+	snapshot := stats
+	statsMu.Unlock()
+	out.Stats = &snapshot
+	for _, r := range out.Results {
+		out.Summary[r.Outcome]++
+	}
+	for _, f := range out.Features {
+		if f.Error != "" {
+			out.Summary[outcomeError]++
+		}
+	}
+	switch {
+	case out.Summary[outcomeError] > 0:
+		out.ExitCode = exitError
+	case out.Summary[outcomeDenied] > 0:
+		out.ExitCode = exitDenied
+	}
+	return writeCLIOutput(stdout, out, *asJSON)
+}
+
+func writeCLIOutput(w io.Writer, out *cliOutput, asJSON bool) int {
+	if asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(out)
+		return out.ExitCode
+	}
+	if len(out.Summary) > 0 {
+		parts := make([]string, 0, len(out.Summary))
+		for _, o := range []string{outcomeAllowed, outcomeFallback, outcomeWarned, outcomeDenied, outcomeError} {
+			if n := out.Summary[o]; n > 0 {
+				parts = append(parts, fmt.Sprintf("%d %s", n, o))
+			}
+		}
+		fmt.Fprintf(w, "\n%s: %s\n", out.Command, strings.Join(parts, ", "))
+	}
+	return out.ExitCode
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"demo-app/internal/dispatch"
+	"demo-app/internal/export"
+	"demo-app/internal/manifest"
+
+	"github.com/yourorg/lcc-sdk/pkg/client"
+	"github.com/yourorg/lcc-sdk/pkg/config"
+)
+
+func TestRunCLIUsage(t *testing.T) {
+	for _, args := range [][]string{
+		{"run", "nope"},
+		{"run"},
+		{"license"},
+		{"jobs", "--n", "0"},
+		{"project", "create", "--count", "x"},
+		{"api", "call", "extra", "--bogus"},
+		{"switch"},
+	} {
+		if code := runCLI(nil, args, io.Discard, io.Discard); code != exitUsage {
+			t.Errorf("runCLI(%q) = %d, want %d", args, code, exitUsage)
+		}
+	}
+	if code := runCLI(nil, []string{"help"}, io.Discard, io.Discard); code != exitOK {
+		t.Errorf("runCLI(help) = %d", code)
+	}
+}
+
+func TestRunCLIExitCodes(t *testing.T) {
+	pdf := manifest.Feature{
+		ID:        "pdf_export",
+		Intercept: &manifest.Target{Package: "demo-app/internal/export", Function: "GeneratePDF"},
+		OnDeny:    &manifest.OnDeny{Action: "error", Message: "PDF Export requires Professional license"},
+	}
+	// connectWith replaces the SDK client with a dispatcher whose checker
+	// answers every feature with check.
+	connectWith := func(check dispatch.CheckerFunc) func(io.Writer, *demoConfig) (func(), error) {
+		return func(io.Writer, *demoConfig) (func(), error) {
+			dispatcher = dispatch.New(&manifest.Manifest{Features: []manifest.Feature{pdf}}, check)
+			dispatcher.MustRegister("demo-app/internal/export", "GeneratePDF", export.GeneratePDF)
+			return func() {}, nil
+		}
+	}
+
+	tests := []struct {
+		name    string
+		connect func(io.Writer, *demoConfig) (func(), error)
+		code    int
+		summary map[string]int
+		results int
+	}{
+		{
+			name: "denied",
+			connect: connectWith(func(manifest.Feature) (bool, string, error) {
+				return false, "tier_too_low", nil
+			}),
+			code:    exitDenied,
+			summary: map[string]int{outcomeDenied: 2},
+			results: 2,
+		},
+		{
+			name: "unregistered target",
+			connect: func(io.Writer, *demoConfig) (func(), error) {
+				allow := func(manifest.Feature) (bool, string, error) { return true, "", nil }
+				dispatcher = dispatch.New(&manifest.Manifest{Features: []manifest.Feature{pdf}}, dispatch.CheckerFunc(allow))
+				return func() {}, nil
+			},
+			code:    exitError,
+			summary: map[string]int{outcomeError: 2},
+			results: 2,
+		},
+		{
+			name: "register error",
+			connect: func(io.Writer, *demoConfig) (func(), error) {
+				return nil, errors.New("failed to initialize LCC SDK: connection refused")
+			},
+			code:    exitError,
+			summary: map[string]int{outcomeError: 1},
+		},
+	}
+	defer func(orig func(io.Writer, *demoConfig) (func(), error)) { connect = orig }(connect)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			connect = tt.connect
+			statsMu.Lock()
+			stats = DemoStats{}
+			statsMu.Unlock()
+			var stdout, stderr bytes.Buffer
+			code := runCLI(&demoConfig{}, []string{"run", "pdf-export", "--count", "2", "--json"}, &stdout, &stderr)
+			if code != tt.code {
+				t.Errorf("exit code = %d, want %d", code, tt.code)
+			}
+
+			// --json leaves only the summary on stdout
+			var out cliOutput
+			if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+				t.Fatalf("stdout is not a JSON summary: %v\n%s", err, stdout.Bytes())
+			}
+			if out.Command != "run pdf-export" || out.ExitCode != tt.code || len(out.Results) != tt.results {
+				t.Errorf("summary = %+v", out)
+			}
+			if !reflect.DeepEqual(out.Summary, tt.summary) {
+				t.Errorf("summary counts = %v, want %v", out.Summary, tt.summary)
+			}
+			if tt.results > 0 && (out.Stats == nil || out.Stats.Features["pdf_export"].Attempts != tt.results) {
+				t.Errorf("stats = %+v, want %d pdf_export checks", out.Stats, tt.results)
+			}
+			if tt.code == exitDenied && out.Results[0].Message != pdf.OnDeny.Message {
+				t.Errorf("denied result = %+v, want the on_deny message", out.Results[0])
+			}
+		})
+	}
+}
+
+func TestSwitchTierRestoreFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lcc-features.yaml")
+	if err := os.WriteFile(path, []byte("sdk:\n  product_id: demo-app\nfeatures: []\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// Nothing listens on the LCC URL, so neither registration succeeds.
+	cur := &demoConfig{
+		Manifest:  path,
+		LCCURL:    "http://127.0.0.1:1",
+		ProductID: "demo-app",
+		Timeout:   time.Second,
+		Sources:   map[string]string{"lcc_url": sourceFlag, "product_id": sourceManifest},
+	}
+	cli, err := client.NewClient(&config.SDKConfig{LCCURL: cur.LCCURL, ProductID: cur.ProductID, Timeout: cur.Timeout})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientMu.Lock()
+	lccClient, activeConfig = cli, cur
+	clientMu.Unlock()
+	defer func() {
+		clientMu.Lock()
+		lccClient, activeConfig = nil, nil
+		clientMu.Unlock()
+	}()
+
+	var w bytes.Buffer
+	next, err := switchTier(&w, cur, "", "demo-app-2", true)
+	if err == nil || !strings.Contains(err.Error(), "restoring demo-app also failed") || next != cur {
+		t.Fatalf("switchTier = %v, %v; want the current config and a restore error", next, err)
+	}
+	if cli, _ := registered(); cli != nil {
+		t.Fatal("closed client still registered")
+	}
+
+	if _, _, err := checkFeature(manifest.Feature{ID: "pdf_export"}); !errors.Is(err, errNotRegistered) {
+		t.Errorf("checkFeature error = %v, want %v", err, errNotRegistered)
+	}
+	if r := createProjectDemo(&w); r.Outcome != outcomeError || r.Error != errNotRegistered.Error() {
+		t.Errorf("createProjectDemo = %+v, want a not registered error", r)
+	}
+	if fs := showLicenseInfo(&w); len(fs) == 0 || fs[0].Error != errNotRegistered.Error() {
+		t.Errorf("showLicenseInfo = %+v, want not registered errors", fs)
+	}
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"demo-app/internal/manifest"
+)
+
+// defaultManifest is the manifest used without --manifest or
+// LCC_DEMO_MANIFEST. The tier builds set it to the absolute path of
+// configs/lcc-features.<tier>.yaml with -ldflags "-X main.defaultManifest=...";
+// a relative default is looked up in the working directory.
+var defaultManifest = "lcc-features.yaml"
+
+// Environment variables overriding the manifest's sdk: section.
+const (
+	envManifest       = "LCC_DEMO_MANIFEST"
+	envLCCURL         = "LCC_URL"
+	envProductID      = "LCC_PRODUCT_ID"
+	envProductVersion = "LCC_PRODUCT_VERSION"
+	envTimeout        = "LCC_TIMEOUT"
+	envCacheTTL       = "LCC_CACHE_TTL"
+)
+
+// Where a setting came from, lowest precedence first.
+const (
+	sourceDefault  = "default"
+	sourceManifest = "manifest"
+	sourceEnv      = "env"
+	sourceFlag     = "flag"
+	sourceSwitch   = "switch" // set by a runtime tier switch
+)
+
+// demoConfig is the effective SDK configuration of the demo.
+type demoConfig struct {
+	Manifest       string            `json:"manifest"`
+	LCCURL         string            `json:"lcc_url"`
+	ProductID      string            `json:"product_id"`
+	ProductVersion string            `json:"product_version"`
+	Timeout        time.Duration     `json:"timeout"`
+	CacheTTL       time.Duration     `json:"cache_ttl"`
+	Sources        map[string]string `json:"sources"` // setting -> default, manifest, env, flag or switch
+
+	features    *manifest.Manifest
+	printConfig bool
+}
+
+const configUsage = `Global options (before the command):
+  --manifest PATH           feature manifest (env LCC_DEMO_MANIFEST)
+  --lcc-url URL             LCC server (env LCC_URL)
+  --product-id ID           product to register as (env LCC_PRODUCT_ID)
+  --product-version V       product version (env LCC_PRODUCT_VERSION)
+  --timeout 30s             SDK request timeout (env LCC_TIMEOUT)
+  --cache-ttl 10s           SDK cache TTL (env LCC_CACHE_TTL)
+  --print-config            print the effective configuration as JSON and exit
+
+Settings are taken from the manifest's sdk: section, then the environment,
+then the flags.
+`
+
+// loadConfig resolves the SDK configuration from the manifest's sdk:
+// section, LCC_* environment variables and the global flags in args, in
+// increasing precedence. It returns the arguments after the flags.
+func loadConfig(args []string, getenv func(string) string) (*demoConfig, []string, error) {
+	cfg := &demoConfig{
+		Manifest:       defaultManifest,
+		LCCURL:         "https://localhost:8088",
+		ProductID:      "demo-app",
+		ProductVersion: "1.0.0",
+		Timeout:        30 * time.Second,
+		CacheTTL:       10 * time.Second,
+		Sources:        map[string]string{},
+	}
+	for _, k := range []string{"manifest", "lcc_url", "product_id", "product_version", "timeout", "cache_ttl"} {
+		cfg.Sources[k] = sourceDefault
+	}
+
+	fs := flag.NewFlagSet("demo", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	manifestPath := fs.String("manifest", "", "")
+	lccURL := fs.String("lcc-url", "", "")
+	productID := fs.String("product-id", "", "")
+	productVersion := fs.String("product-version", "", "")
+	timeout := fs.Duration("timeout", 0, "")
+	cacheTTL := fs.Duration("cache-ttl", 0, "")
+	fs.BoolVar(&cfg.printConfig, "print-config", false, "")
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, err
+	}
+	set := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	str := func(key string, dst *string, env, flagName, flagVal string) {
+		if v := getenv(env); v != "" {
+			*dst, cfg.Sources[key] = v, sourceEnv
+		}
+		if set[flagName] {
+			*dst, cfg.Sources[key] = flagVal, sourceFlag
+		}
+	}
+	dur := func(key string, dst *time.Duration, env, flagName string, flagVal time.Duration) error {
+		if v := getenv(env); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				return fmt.Errorf("%s: %w", env, err)
+			}
+			*dst, cfg.Sources[key] = d, sourceEnv
+		}
+		if set[flagName] {
+			*dst, cfg.Sources[key] = flagVal, sourceFlag
+		}
+		if *dst <= 0 {
+			return fmt.Errorf("%s must be positive", key)
+		}
+		return nil
+	}
+
+	// The manifest path decides which sdk: section applies, so resolve it first
+	str("manifest", &cfg.Manifest, envManifest, "manifest", *manifestPath)
+	if err := cfg.useManifest(cfg.Manifest); err != nil {
+		if cfg.Sources["manifest"] == sourceDefault && errors.Is(err, os.ErrNotExist) {
+			return nil, nil, fmt.Errorf("%w; set --manifest or %s", err, envManifest)
+		}
+		return nil, nil, err
+	}
+
+	str("lcc_url", &cfg.LCCURL, envLCCURL, "lcc-url", *lccURL)
+	str("product_id", &cfg.ProductID, envProductID, "product-id", *productID)
+	str("product_version", &cfg.ProductVersion, envProductVersion, "product-version", *productVersion)
+	if err := dur("timeout", &cfg.Timeout, envTimeout, "timeout", *timeout); err != nil {
+		return nil, nil, err
+	}
+	if err := dur("cache_ttl", &cfg.CacheTTL, envCacheTTL, "cache-ttl", *cacheTTL); err != nil {
+		return nil, nil, err
+	}
+	if cfg.LCCURL == "" || cfg.ProductID == "" {
+		return nil, nil, fmt.Errorf("lcc_url and product_id must not be empty")
+	}
+	return cfg, fs.Args(), nil
+}
+
+// useManifest loads the manifest at path and takes its sdk: settings,
+// except those set by the environment or a flag.
+func (c *demoConfig) useManifest(path string) error {
+	m, err := manifest.Load(path)
+	if err != nil {
+		return fmt.Errorf("failed to load feature manifest: %w", err)
+	}
+	c.features = m
+	for _, s := range []struct {
+		key string
+		dst *string
+		v   string
+	}{
+		{"lcc_url", &c.LCCURL, m.SDK.LCCURL},
+		{"product_id", &c.ProductID, m.SDK.ProductID},
+		{"product_version", &c.ProductVersion, m.SDK.ProductVersion},
+	} {
+		if src := c.Sources[s.key]; s.v != "" && (src == sourceDefault || src == sourceManifest) {
+			*s.dst, c.Sources[s.key] = s.v, sourceManifest
+		}
+	}
+	return nil
+}
+
+// MarshalJSON writes the durations as strings, e.g. "30s".
+func (c *demoConfig) MarshalJSON() ([]byte, error) {
+	type plain demoConfig
+	return json.Marshal(struct {
+		*plain
+		Timeout  string `json:"timeout"`
+		CacheTTL string `json:"cache_ttl"`
+	}{(*plain)(c), c.Timeout.String(), c.CacheTTL.String()})
+}
+
+// print writes the effective configuration and where each setting came from.
+func (c *demoConfig) print(w io.Writer) {
+	fmt.Fprintln(w, "Configuration:")
+	for _, s := range []struct{ key, val string }{
+		{"manifest", c.Manifest},
+		{"lcc_url", c.LCCURL},
+		{"product_id", c.ProductID},
+		{"product_version", c.ProductVersion},
+		{"timeout", c.Timeout.String()},
+		{"cache_ttl", c.CacheTTL.String()},
+	} {
+		fmt.Fprintf(w, "  %-16s %s (%s)\n", s.key+":", s.val, c.Sources[s.key])
+	}
+}
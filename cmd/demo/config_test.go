@@ -0,0 +1,58 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lcc-features.yaml")
+	data := "sdk:\n  lcc_url: http://manifest:7086\n  product_id: demo-analytics-basic\n  product_version: 2.0.0\nfeatures: []\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	env := map[string]string{
+		envManifest:  path,
+		envProductID: "from-env",
+		envTimeout:   "5s",
+	}
+	getenv := func(k string) string { return env[k] }
+
+	cfg, rest, err := loadConfig([]string{"--product-id", "from-flag", "--cache-ttl", "1m", "run", "pdf-export"}, getenv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rest) != 2 || rest[0] != "run" {
+		t.Errorf("rest = %q", rest)
+	}
+	for _, c := range []struct{ key, got, want, source string }{
+		{"manifest", cfg.Manifest, path, sourceEnv},
+		{"lcc_url", cfg.LCCURL, "http://manifest:7086", sourceManifest},
+		{"product_id", cfg.ProductID, "from-flag", sourceFlag},
+		{"product_version", cfg.ProductVersion, "2.0.0", sourceManifest},
+		{"timeout", cfg.Timeout.String(), (5 * time.Second).String(), sourceEnv},
+		{"cache_ttl", cfg.CacheTTL.String(), time.Minute.String(), sourceFlag},
+	} {
+		if c.got != c.want || cfg.Sources[c.key] != c.source {
+			t.Errorf("%s = %s (%s), want %s (%s)", c.key, c.got, cfg.Sources[c.key], c.want, c.source)
+		}
+	}
+
+	env[envTimeout] = "soon"
+	if _, _, err := loadConfig(nil, getenv); err == nil {
+		t.Error("invalid LCC_TIMEOUT accepted")
+	}
+}
+
+func TestLoadConfigMissingDefaultManifest(t *testing.T) {
+	defer func(prev string) { defaultManifest = prev }(defaultManifest)
+	defaultManifest = filepath.Join(t.TempDir(), "lcc-features.basic.yaml")
+
+	_, _, err := loadConfig(nil, func(string) string { return "" })
+	if err == nil || !strings.Contains(err.Error(), "--manifest") || !strings.Contains(err.Error(), envManifest) {
+		t.Errorf("err = %v, want it to name --manifest and %s", err, envManifest)
+	}
+}
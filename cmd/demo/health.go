@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"demo-app/internal/health"
+)
+
+const (
+	readyTimeout    = 3 * time.Second // bounds the checks of one /readyz request
+	shutdownTimeout = 5 * time.Second // bounds in-flight status requests on exit
+)
+
+var (
+	statusServer atomic.Pointer[http.Server] // set by startStatusServer
+	shuttingDown atomic.Bool
+	shutdownOnce sync.Once
+)
+
+func checkShutdown(context.Context) health.Check {
+	if shuttingDown.Load() {
+		return health.Check{Name: "shutdown", Detail: "shutting down"}
+	}
+	return health.Check{Name: "shutdown", OK: true}
+}
+
+// checkClient passes while the demo is registered with LCC.
+func checkClient(context.Context) health.Check {
+	cli, cfg := registered()
+	if cli == nil || cfg == nil {
+		return health.Check{Name: "client", Detail: "not registered"}
+	}
+	return health.Check{Name: "client", OK: true, Detail: cfg.ProductID + " " + cli.GetInstanceID()}
+}
+
+// checkLCC passes while the LCC server answers.
+func checkLCC(ctx context.Context) health.Check {
+	_, cfg := registered()
+	if cfg == nil {
+		return health.Check{Name: "lcc", Detail: "not configured"}
+	}
+	return health.Reachable("lcc", cfg.LCCURL)(ctx)
+}
+
+// shutdown stops the status server and closes the LCC client. It runs
+// once, on exit from the menu or on SIGINT/SIGTERM.
+func shutdown() {
+	shutdownOnce.Do(func() {
+		shuttingDown.Store(true)
+		if srv := statusServer.Load(); srv != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+			defer cancel()
+			if err := srv.Shutdown(ctx); err != nil {
+				log.Printf("Status server shutdown: %v", err)
+			}
+		}
+		if cli, _ := registered(); cli != nil {
+			cli.Close()
+		}
+	})
+}
@@ -2,15 +2,27 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"html"
+	"io"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"sort"
+	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	"demo-app/internal/analytics"
+	"demo-app/internal/dispatch"
 	"demo-app/internal/export"
+	"demo-app/internal/health"
+	"demo-app/internal/manifest"
+	"demo-app/internal/metrics"
 	"demo-app/internal/reporting"
 
 	"github.com/yourorg/lcc-sdk/pkg/client"
@@ -19,6 +31,38 @@ import (
 
 var lccClient *client.Client
 
+// activeConfig is the configuration lccClient registered with.
+var activeConfig *demoConfig
+
+// clientMu guards lccClient and activeConfig. Only the menu (or CLI)
+// goroutine replaces them; the status server reads them through registered.
+var clientMu sync.RWMutex
+
+// registered returns the current client and its configuration.
+func registered() (*client.Client, *demoConfig) {
+	clientMu.RLock()
+	defer clientMu.RUnlock()
+	return lccClient, activeConfig
+}
+
+// errNotRegistered is returned for SDK calls while no client is registered,
+// e.g. after a switch failed to register both the new and the previous
+// configuration.
+var errNotRegistered = errors.New("not registered with LCC")
+
+// currentClient returns the registered client or errNotRegistered.
+func currentClient() (*client.Client, error) {
+	cli, _ := registered()
+	if cli == nil {
+		return nil, errNotRegistered
+	}
+	return cli, nil
+}
+
+// dispatcher runs the gated demo features through the manifest, honoring
+// fallback and on_deny.
+var dispatcher *dispatch.Dispatcher
+
 // Demo state for capacity/TPS/concurrency examples
 var (
 	projectCount int
@@ -31,45 +75,76 @@ var (
 )
 
 func main() {
+	cfg, args, err := loadConfig(os.Args[1:], os.Getenv)
+	switch {
+	case errors.Is(err, flag.ErrHelp):
+		fmt.Print(cliUsage + "\n" + configUsage)
+		return
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "demo: %v\n\n%s", err, configUsage)
+		os.Exit(exitUsage)
+	case cfg.printConfig:
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(cfg)
+		return
+	}
+
+	// Subcommands run one scripted action and exit; no arguments starts the menu
+	if len(args) > 0 {
+		os.Exit(runCLI(cfg, args, os.Stdout, os.Stderr))
+	}
+
 	fmt.Println("=== LCC Demo Application ===")
 
-	// Initialize LCC SDK
-	if err := initLCC(); err != nil {
-		log.Fatalf("Failed to initialize LCC SDK: %v", err)
+	if err := setup(os.Stdout, cfg); err != nil {
+		log.Fatal(err)
 	}
-	defer lccClient.Close()
+	defer shutdown()
 
 	fmt.Printf("Instance ID: %s\n\n", lccClient.GetInstanceID())
 
 	// Start status HTTP server in background
 	go startStatusServer()
 
+	// The menu blocks on stdin, so signals shut down from here
+	go func() {
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+		<-sig
+		fmt.Println()
+		shutdown()
+		os.Exit(0)
+	}()
+
 	// Demo menu
 	for {
-		showMenu()
+		showMenu(os.Stdout)
 		var choice int
 		fmt.Print("Select option: ")
 		fmt.Scanf("%d", &choice)
 
 		switch choice {
 		case 1:
-			runBasicAnalytics()
+			runBasicAnalytics(os.Stdout)
 		case 2:
-			runAdvancedAnalytics()
+			runAdvancedAnalytics(os.Stdout)
 		case 3:
-			exportToPDF()
+			exportToPDF(os.Stdout)
 		case 4:
-			exportToExcel()
+			exportToExcel(os.Stdout)
 		case 5:
-			scheduleReport()
+			scheduleReport(os.Stdout)
 		case 6:
-			showLicenseInfo()
+			showLicenseInfo(os.Stdout)
 		case 7:
-			createProjectDemo()
+			createProjectDemo(os.Stdout)
 		case 8:
-			callDemoAPIDemo()
+			callDemoAPIDemo(os.Stdout)
 		case 9:
-			simulateConcurrentJobsDemo()
+			simulateConcurrentJobsDemo(os.Stdout)
+		case 10:
+			cfg = switchTierDemo(cfg)
 		case 0:
 			fmt.Println("Goodbye!")
 			return
@@ -80,160 +155,245 @@ func main() {
 	}
 }
 
-func initLCC() error {
+// setup prints the effective configuration, registers with LCC and sets
+// up dispatching through the manifest.
+func setup(w io.Writer, cfg *demoConfig) error {
+	cfg.print(w)
+	if err := initLCC(w, cfg); err != nil {
+		return fmt.Errorf("failed to initialize LCC SDK: %w", err)
+	}
+	initDispatcher(cfg.features)
+
+	// Volume-type metering: advanced analytics reports how much data it processed
+	analytics.SetVolumeConsumer(recordVolume)
+	return nil
+}
+
+func initLCC(w io.Writer, c *demoConfig) error {
 	cfg := &config.SDKConfig{
-		LCCURL:         "https://localhost:8088",
-		ProductID:      "demo-app",
-		ProductVersion: "1.0.0",
-		Timeout:        30 * time.Second,
-		CacheTTL:       10 * time.Second,
+		LCCURL:         c.LCCURL,
+		ProductID:      c.ProductID,
+		ProductVersion: c.ProductVersion,
+		Timeout:        c.Timeout,
+		CacheTTL:       c.CacheTTL,
 	}
 
-	var err error
-	lccClient, err = client.NewClient(cfg)
+	cli, err := client.NewClient(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to create client: %w", err)
 	}
 
-	// Register with LCC
-	if err := lccClient.Register(); err != nil {
+	// Register with LCC; only a registered client replaces the current one
+	if err := cli.Register(); err != nil {
+		cli.Close()
 		return fmt.Errorf("failed to register: %w", err)
 	}
+	clientMu.Lock()
+	lccClient, activeConfig = cli, c
+	clientMu.Unlock()
 
-	fmt.Println("✓ Successfully registered with LCC")
+	fmt.Fprintf(w, "✓ Successfully registered with LCC as %s %s\n", c.ProductID, c.ProductVersion)
 	return nil
 }
 
-func showMenu() {
-	fmt.Println("-----------------------------------")
-	fmt.Println("1. Run Basic Analytics (Free)")
-	fmt.Println("2. Run Advanced Analytics (Professional)")
-	fmt.Println("3. Export to PDF (Professional, Quota: 100/day)")
-	fmt.Println("4. Export to Excel (Enterprise)")
-	fmt.Println("5. Schedule Report (Professional)")
-	fmt.Println("6. Show License Info")
-	fmt.Println("7. Create Project (State Capacity Demo)")
-	fmt.Println("8. Call Demo API (TPS Demo)")
-	fmt.Println("9. Simulate Concurrent Jobs (Concurrency Demo)")
-	fmt.Println("0. Exit")
-	fmt.Println("-----------------------------------")
-}
-
-func runBasicAnalytics() {
-	fmt.Println("\n[Basic Analytics]")
-
-	analytics.RunBasic()
-	fmt.Println("✓ Basic analytics completed")
+func initDispatcher(m *manifest.Manifest) {
+	dispatcher = dispatch.New(m, dispatch.CheckerFunc(checkFeature))
+	dispatcher.MustRegister("demo-app/internal/analytics", "RunAdvanced", analytics.RunAdvanced)
+	dispatcher.MustRegister("demo-app/internal/analytics", "RunBasic", analytics.RunBasic)
+	dispatcher.MustRegister("demo-app/internal/export", "GeneratePDF", export.GeneratePDF)
+	dispatcher.MustRegister("demo-app/internal/export", "GenerateExcel", export.GenerateExcel)
+	dispatcher.MustRegister("demo-app/internal/reporting", "Schedule", reporting.Schedule)
+	if missing := dispatcher.Unregistered(); len(missing) > 0 {
+		log.Printf("Warning: manifest targets without a registered function: %s", strings.Join(missing, ", "))
+	}
 }
 
-func runAdvancedAnalytics() {
-	fmt.Println("\n[Advanced Analytics]")
-
-	// Consumption-type control: each advanced analytics run costs 1 credit
-	allowed, remaining, reason, err := lccClient.Consume("advanced_analytics", 1, nil)
-	if err != nil {
-		fmt.Printf("✗ Failed to check feature: %v\n", err)
-		return
+// checkFeature asks LCC whether a feature may run. Features with a quota
+// consume one unit per call; the others are checked for enablement.
+func checkFeature(f manifest.Feature) (bool, string, error) {
+	cli, cfg := registered()
+	if cli == nil {
+		return false, "", errNotRegistered
 	}
-	if !allowed {
-		fmt.Printf("✗ Feature not available: %s\n", reason)
-		fmt.Println("  Please upgrade license or check quota")
-		return
+	if f.Quota != nil {
+		start := time.Now()
+		allowed, remaining, reason, err := cli.Consume(f.ID, 1, nil)
+		observeSDK("consume", f.ID, start, allowed, err)
+		if err == nil {
+			recordQuota(f.ID, remaining, f.Quota.Limit)
+			sdkMetrics.Set(metrics.QuotaRemaining, float64(remaining), "product", cfg.ProductID, "feature", f.ID)
+		}
+		return allowed, reason, err
 	}
+	start := time.Now()
+	status, err := cli.CheckFeature(f.ID)
+	observeSDK("check_feature", f.ID, start, err == nil && status.Enabled, err)
+	if err != nil {
+		return false, "", err
+	}
+	return status.Enabled, status.Reason, nil
+}
 
-	analytics.RunAdvanced()
-
-	statsMu.Lock()
-	stats.AdvancedCalls++
-	statsMu.Unlock()
+// Outcomes of a demo operation, as reported by the CLI.
+const (
+	outcomeAllowed  = "allowed"
+	outcomeFallback = dispatch.OutcomeFallback
+	outcomeWarned   = dispatch.OutcomeWarned
+	outcomeDenied   = dispatch.OutcomeDenied
+	outcomeError    = "error"
+)
 
-	fmt.Printf("✓ Advanced analytics completed (remaining approx: %d)\n", remaining)
+// opResult is the outcome of one demo operation.
+type opResult struct {
+	Outcome string  `json:"outcome"`
+	Reason  string  `json:"reason,omitempty"`
+	Message string  `json:"message,omitempty"` // on_deny message
+	Ran     string  `json:"ran,omitempty"`     // package.Function that ran
+	Current float64 `json:"current,omitempty"` // project count or TPS
+	Max     float64 `json:"max,omitempty"`
+	Error   string  `json:"error,omitempty"`
 }
 
-func exportToPDF() {
-	fmt.Println("\n[PDF Export]")
+// runFeature dispatches a gated feature through the manifest, prints how
+// it went and counts it in the stats.
+func runFeature(w io.Writer, featureID string, args ...any) opResult {
+	res, err := dispatcher.Call(featureID, args...)
+	r := opResult{Outcome: res.Outcome, Reason: res.Reason, Ran: res.Ran}
+	defer func() { recordCheck(featureID, r) }()
+	var denied *dispatch.DeniedError
+	switch {
+	case errors.As(err, &denied):
+		r.Outcome, r.Message = outcomeDenied, denied.Message
+		fmt.Fprintf(w, "✗ Feature not available: %s\n", denied.Reason)
+		if denied.Message != "" {
+			fmt.Fprintf(w, "  %s\n", denied.Message)
+		}
+		return r
+	case err != nil:
+		r.Outcome, r.Error = outcomeError, err.Error()
+		fmt.Fprintf(w, "✗ Failed to run feature: %v\n", err)
+		return r
+	case res.Outcome == dispatch.OutcomeTarget:
+		r.Outcome = outcomeAllowed
+	case res.Outcome == dispatch.OutcomeFallback:
+		fmt.Fprintf(w, "↓ %s not available (%s), ran fallback %s\n", featureID, res.Reason, res.Ran)
+		return r
+	case res.Outcome == dispatch.OutcomeWarned:
+		fmt.Fprintf(w, "⚠ %s not licensed (%s), continuing\n", featureID, res.Reason)
+	}
 
-	// Consumption-type control: PDF export quota is defined in license
-	allowed, remaining, reason, err := lccClient.Consume("pdf_export", 1, nil)
-	if err != nil {
-		fmt.Printf("✗ Failed to check feature: %v\n", err)
-		return
+	// The feature itself ran
+	statsMu.Lock()
+	switch featureID {
+	case "advanced_analytics":
+		stats.AdvancedCalls++
+	case "pdf_export":
+		stats.PDFExports++
 	}
-	if !allowed {
-		fmt.Printf("✗ Feature not available: %s\n", reason)
-		if reason == "insufficient_tier" {
-			fmt.Println("  Please upgrade to Professional tier")
-		} else if reason == "quota_exceeded" {
-			fmt.Println("  Daily quota exceeded, please try tomorrow")
+	statsMu.Unlock()
+
+	// Features with a quota were already counted by Consume
+	if f, _ := dispatcher.Feature(featureID); f.Quota == nil {
+		cli, err := currentClient()
+		if err == nil {
+			start := time.Now()
+			err = cli.ReportUsage(featureID, 1)
+			observeSDK("report_usage", featureID, start, true, err)
+		}
+		if err != nil {
+			log.Printf("Warning: Failed to report usage: %v", err)
 		}
-		return
 	}
+	return r
+}
 
-	export.GeneratePDF("report.pdf")
+func showMenu(w io.Writer) {
+	fmt.Fprintln(w, "-----------------------------------")
+	fmt.Fprintln(w, "1. Run Basic Analytics (Free)")
+	fmt.Fprintln(w, "2. Run Advanced Analytics (Professional)")
+	fmt.Fprintln(w, "3. Export to PDF (Professional, Quota: 100/day)")
+	fmt.Fprintln(w, "4. Export to Excel (Enterprise)")
+	fmt.Fprintln(w, "5. Schedule Report (Professional)")
+	fmt.Fprintln(w, "6. Show License Info")
+	fmt.Fprintln(w, "7. Create Project (State Capacity Demo)")
+	fmt.Fprintln(w, "8. Call Demo API (TPS Demo)")
+	fmt.Fprintln(w, "9. Simulate Concurrent Jobs (Concurrency Demo)")
+	fmt.Fprintln(w, "10. Switch Tier / Product")
+	fmt.Fprintln(w, "0. Exit")
+	fmt.Fprintln(w, "-----------------------------------")
+}
 
-	statsMu.Lock()
-	stats.PDFExports++
-	statsMu.Unlock()
+func runBasicAnalytics(w io.Writer) opResult {
+	fmt.Fprintln(w, "\n[Basic Analytics]")
 
-	fmt.Printf("✓ PDF exported successfully (remaining approx: %d)\n", remaining)
+	analytics.RunBasic()
+	fmt.Fprintln(w, "✓ Basic analytics completed")
+	return opResult{Outcome: outcomeAllowed, Ran: "demo-app/internal/analytics.RunBasic"}
 }
 
-func exportToExcel() {
-	fmt.Println("\n[Excel Export]")
-	
-	// Check if feature is enabled (Enterprise only)
-	status, err := lccClient.CheckFeature("excel_export")
-	if err != nil {
-		fmt.Printf("✗ Failed to check feature: %v\n", err)
-		return
+func runAdvancedAnalytics(w io.Writer) opResult {
+	fmt.Fprintln(w, "\n[Advanced Analytics]")
+
+	// Consumption-type control: each advanced analytics run costs 1 credit.
+	// When it is denied the manifest's fallback (RunBasic) runs instead.
+	r := runFeature(w, "advanced_analytics")
+	switch r.Outcome {
+	case outcomeAllowed, outcomeWarned:
+		fmt.Fprintln(w, "✓ Advanced analytics completed")
+	case outcomeDenied:
+		fmt.Fprintln(w, "  Please upgrade license or check quota")
 	}
+	return r
+}
 
-	if !status.Enabled {
-		fmt.Printf("✗ Feature not available: %s\n", status.Reason)
-		fmt.Println("  Please upgrade to Enterprise tier")
-		return
-	}
+func exportToPDF(w io.Writer) opResult {
+	fmt.Fprintln(w, "\n[PDF Export]")
 
-	// Feature is enabled, use it
-	export.GenerateExcel("report.xlsx")
-	
-	// Report usage
-	if err := lccClient.ReportUsage("excel_export", 1); err != nil {
-		log.Printf("Warning: Failed to report usage: %v", err)
+	// Consumption-type control: PDF export quota is defined in license
+	r := runFeature(w, "pdf_export", "report.pdf")
+	switch {
+	case r.Outcome == outcomeDenied && r.Reason == "quota_exceeded":
+		fmt.Fprintln(w, "  Daily quota exceeded, please try tomorrow")
+	case r.Outcome != outcomeDenied && r.Outcome != outcomeError:
+		fmt.Fprintln(w, "✓ PDF exported successfully")
 	}
-
-	fmt.Println("✓ Excel exported successfully")
+	return r
 }
 
-func scheduleReport() {
-	fmt.Println("\n[Schedule Report]")
-	
-	// Check if feature is enabled
-	status, err := lccClient.CheckFeature("scheduled_reports")
-	if err != nil {
-		fmt.Printf("✗ Failed to check feature: %v\n", err)
-		return
-	}
+func exportToExcel(w io.Writer) opResult {
+	fmt.Fprintln(w, "\n[Excel Export]")
 
-	if !status.Enabled {
-		fmt.Printf("✗ Feature not available: %s\n", status.Reason)
-		fmt.Println("  Please upgrade to Professional tier")
-		return
+	// Enterprise only; the manifest's on_deny decides what a denial does
+	r := runFeature(w, "excel_export", "report.xlsx")
+	if r.Outcome != outcomeDenied && r.Outcome != outcomeError {
+		fmt.Fprintln(w, "✓ Excel exported successfully")
 	}
+	return r
+}
 
-	// Feature is enabled, use it
-	reporting.Schedule("weekly", "Monday 9:00 AM")
-	
-	// Report usage
-	if err := lccClient.ReportUsage("scheduled_reports", 1); err != nil {
-		log.Printf("Warning: Failed to report usage: %v", err)
+func scheduleReport(w io.Writer) opResult {
+	fmt.Fprintln(w, "\n[Schedule Report]")
+
+	r := runFeature(w, "scheduled_reports", "weekly", "Monday 9:00 AM")
+	if r.Outcome != outcomeDenied && r.Outcome != outcomeError {
+		fmt.Fprintln(w, "✓ Report scheduled successfully")
 	}
+	return r
+}
 
-	fmt.Println("✓ Report scheduled successfully")
+// featureStatus is one row of the license information.
+type featureStatus struct {
+	ID             string  `json:"id"`
+	Enabled        bool    `json:"enabled"`
+	Reason         string  `json:"reason,omitempty"`
+	MaxCapacity    int     `json:"max_capacity,omitempty"`
+	MaxTPS         float64 `json:"max_tps,omitempty"`
+	MaxConcurrency int     `json:"max_concurrency,omitempty"`
+	Error          string  `json:"error,omitempty"`
 }
 
-func showLicenseInfo() {
-	fmt.Println("\n[License Information]")
+func showLicenseInfo(w io.Writer) []featureStatus {
+	fmt.Fprintln(w, "\n[License Information]")
 	
 	features := []string{
 		"advanced_analytics",
@@ -245,90 +405,134 @@ func showLicenseInfo() {
 		"concurrent.user",
 	}
 	
-	fmt.Println("Feature Status:")
-	fmt.Println("-----------------------------------")
+	fmt.Fprintln(w, "Feature Status:")
+	fmt.Fprintln(w, "-----------------------------------")
 	
+	out := make([]featureStatus, 0, len(features))
+	cli, cliErr := currentClient()
 	for _, featureID := range features {
-		status, err := lccClient.CheckFeature(featureID)
+		var status *client.FeatureStatus
+		err := cliErr
+		if err == nil {
+			start := time.Now()
+			status, err = cli.CheckFeature(featureID)
+			observeSDK("check_feature", featureID, start, err == nil && status.Enabled, err)
+		}
 		if err != nil {
-			fmt.Printf("  %s: ERROR (%v)\n", featureID, err)
+			fmt.Fprintf(w, "  %s: ERROR (%v)\n", featureID, err)
+			out = append(out, featureStatus{ID: featureID, Error: err.Error()})
 			continue
 		}
+		out = append(out, featureStatus{
+			ID:             featureID,
+			Enabled:        status.Enabled,
+			Reason:         status.Reason,
+			MaxCapacity:    status.MaxCapacity,
+			MaxTPS:         status.MaxTPS,
+			MaxConcurrency: status.MaxConcurrency,
+		})
 		
 		statusSymbol := "✗"
 		if status.Enabled {
 			statusSymbol = "✓"
 		}
 		
-		fmt.Printf("  %s %s", statusSymbol, featureID)
+		fmt.Fprintf(w, "  %s %s", statusSymbol, featureID)
 		if !status.Enabled {
-			fmt.Printf(" (%s)", status.Reason)
+			fmt.Fprintf(w, " (%s)", status.Reason)
 		}
 		// Show control limits when available
 		if status.MaxCapacity > 0 {
-			fmt.Printf(" [max_capacity=%d]", status.MaxCapacity)
+			fmt.Fprintf(w, " [max_capacity=%d]", status.MaxCapacity)
 		}
 		if status.MaxTPS > 0 {
-			fmt.Printf(" [max_tps=%.1f]", status.MaxTPS)
+			fmt.Fprintf(w, " [max_tps=%.1f]", status.MaxTPS)
 		}
 		if status.MaxConcurrency > 0 {
-			fmt.Printf(" [max_concurrency=%d]", status.MaxConcurrency)
+			fmt.Fprintf(w, " [max_concurrency=%d]", status.MaxConcurrency)
 		}
-		fmt.Println()
+		fmt.Fprintln(w)
 	}
-	fmt.Println("-----------------------------------")
+	fmt.Fprintln(w, "-----------------------------------")
+	return out
 }
 
 // --- State capacity demo ---
 
-func createProjectDemo() {
-	fmt.Println("\n[State Capacity Demo: Project Count]")
+func createProjectDemo(w io.Writer) opResult {
+	fmt.Fprintln(w, "\n[State Capacity Demo: Project Count]")
 
 	// Each call represents creating one more project
 	projectCount++
 
-	allowed, max, reason, err := lccClient.CheckCapacity("capacity.project.count", projectCount)
+	var allowed bool
+	var max int
+	var reason string
+	cli, err := currentClient()
+	if err == nil {
+		start := time.Now()
+		allowed, max, reason, err = cli.CheckCapacity("capacity.project.count", projectCount)
+		observeSDK("check_capacity", "capacity.project.count", start, allowed, err)
+	}
+	r := opResult{Outcome: outcomeAllowed, Reason: reason, Current: float64(projectCount), Max: float64(max)}
+	defer func() { recordCheck("capacity.project.count", r) }()
 	if err != nil {
-		fmt.Printf("✗ Failed to check capacity: %v\n", err)
+		fmt.Fprintf(w, "✗ Failed to check capacity: %v\n", err)
 		projectCount--
-		return
+		r.Outcome, r.Error = outcomeError, err.Error()
+		return r
 	}
 	if !allowed {
-		fmt.Printf("✗ Cannot create project %d: %s (max=%d)\n", projectCount, reason, max)
+		fmt.Fprintf(w, "✗ Cannot create project %d: %s (max=%d)\n", projectCount, reason, max)
 		projectCount--
-		return
+		r.Outcome = outcomeDenied
+		return r
 	}
 
 	statsMu.Lock()
 	stats.Projects = projectCount
 	statsMu.Unlock()
 
-	fmt.Printf("✓ Project %d created (max=%d)\n", projectCount, max)
+	fmt.Fprintf(w, "✓ Project %d created (max=%d)\n", projectCount, max)
+	return r
 }
 
 // --- TPS demo ---
 
-func callDemoAPIDemo() {
-	fmt.Println("\n[TPS Demo: api.v1.demo]")
+func callDemoAPIDemo(w io.Writer) opResult {
+	fmt.Fprintln(w, "\n[TPS Demo: api.v1.demo]")
 
 	currentTPS := recordRequestAndGetTPS()
-	fmt.Printf("  Current TPS (approx): %.1f\n", currentTPS)
+	fmt.Fprintf(w, "  Current TPS (approx): %.1f\n", currentTPS)
 
 	statsMu.Lock()
 	stats.LastTPS = currentTPS
 	statsMu.Unlock()
 
-	allowed, maxTPS, reason, err := lccClient.CheckTPS("api.v1.demo", currentTPS)
+	var allowed bool
+	var maxTPS float64
+	var reason string
+	cli, err := currentClient()
+	if err == nil {
+		start := time.Now()
+		allowed, maxTPS, reason, err = cli.CheckTPS("api.v1.demo", currentTPS)
+		observeSDK("check_tps", "api.v1.demo", start, allowed, err)
+	}
+	r := opResult{Outcome: outcomeAllowed, Reason: reason, Current: currentTPS, Max: maxTPS}
+	defer func() { recordCheck("api.v1.demo", r) }()
 	if err != nil {
-		fmt.Printf("✗ Failed to check TPS: %v\n", err)
-		return
+		fmt.Fprintf(w, "✗ Failed to check TPS: %v\n", err)
+		r.Outcome, r.Error = outcomeError, err.Error()
+		return r
 	}
 	if !allowed {
-		fmt.Printf("✗ TPS limit exceeded: current=%.1f, max=%.1f (%s)\n", currentTPS, maxTPS, reason)
-		return
+		fmt.Fprintf(w, "✗ TPS limit exceeded: current=%.1f, max=%.1f (%s)\n", currentTPS, maxTPS, reason)
+		r.Outcome = outcomeDenied
+		return r
 	}
 
-	fmt.Printf("✓ TPS within limit: current=%.1f, max=%.1f\n", currentTPS, maxTPS)
+	fmt.Fprintf(w, "✓ TPS within limit: current=%.1f, max=%.1f\n", currentTPS, maxTPS)
+	return r
 }
 
 // 记录一次“请求”并计算最近 1 秒内的近似 TPS
@@ -356,38 +560,76 @@ func recordRequestAndGetTPS() float64 {
 
 // --- Concurrency demo ---
 
-func simulateConcurrentJobsDemo() {
-	fmt.Println("\n[Concurrency Demo: concurrent.user]")
+// jobsResult summarizes a concurrency demo run.
+type jobsResult struct {
+	Jobs          int        `json:"jobs"`
+	Hold          string     `json:"hold"`
+	Completed     int        `json:"completed"`
+	Denied        int        `json:"denied"`
+	Errors        int        `json:"errors"`
+	MaxConcurrent int        `json:"max_concurrent"`
+	Results       []opResult `json:"results"` // indexed by job - 1
+}
+
+func simulateConcurrentJobsDemo(w io.Writer) jobsResult {
+	return simulateConcurrentJobs(w, 15, 300*time.Millisecond) // try to exceed MaxConcurrency=10 from demo license
+}
+
+// simulateConcurrentJobs starts jobs goroutines that each hold a concurrency
+// slot for hold.
+func simulateConcurrentJobs(w io.Writer, jobs int, hold time.Duration) jobsResult {
+	fmt.Fprintln(w, "\n[Concurrency Demo: concurrent.user]")
 
 	var wg sync.WaitGroup
-	jobs := 15 // try to exceed MaxConcurrency=10 from demo license
+	res := jobsResult{Jobs: jobs, Hold: hold.String(), Results: make([]opResult, jobs)}
+	cli, cliErr := currentClient()
+	var mu sync.Mutex
+	running := 0
 
 	for i := 0; i < jobs; i++ {
 		wg.Add(1)
 		go func(id int) {
 			defer wg.Done()
 
-			release, allowed, reason, err := lccClient.AcquireSlot("concurrent.user", map[string]any{
-				"job_id": id,
-			})
+			var release func()
+			var allowed bool
+			var reason string
+			err := cliErr
+			if err == nil {
+				start := time.Now()
+				release, allowed, reason, err = cli.AcquireSlot("concurrent.user", map[string]any{
+					"job_id": id,
+				})
+				observeSDK("acquire_slot", "concurrent.user", start, allowed, err)
+			}
 			if err != nil {
-				fmt.Printf("  Job %d: error acquiring slot: %v\n", id, err)
+				fmt.Fprintf(w, "  Job %d: error acquiring slot: %v\n", id, err)
+				res.Results[id-1] = opResult{Outcome: outcomeError, Error: err.Error()}
 				return
 			}
 			if !allowed {
-				fmt.Printf("  Job %d: denied (%s)\n", id, reason)
+				fmt.Fprintf(w, "  Job %d: denied (%s)\n", id, reason)
+				res.Results[id-1] = opResult{Outcome: outcomeDenied, Reason: reason}
 				return
 			}
 			defer release()
+			res.Results[id-1] = opResult{Outcome: outcomeAllowed}
 
 			statsMu.Lock()
 			stats.ConcurrentJobs++
 			statsMu.Unlock()
-
-			fmt.Printf("  Job %d: running...\n", id)
-			time.Sleep(300 * time.Millisecond)
-			fmt.Printf("  Job %d: done\n", id)
-
+			mu.Lock()
+			running++
+			res.MaxConcurrent = max(res.MaxConcurrent, running)
+			mu.Unlock()
+
+			fmt.Fprintf(w, "  Job %d: running...\n", id)
+			time.Sleep(hold)
+			fmt.Fprintf(w, "  Job %d: done\n", id)
+
+			mu.Lock()
+			running--
+			mu.Unlock()
 			statsMu.Lock()
 			stats.ConcurrentJobs--
 			statsMu.Unlock()
@@ -395,16 +637,27 @@ func simulateConcurrentJobsDemo() {
 	}
 
 	wg.Wait()
-	fmt.Println("  Concurrency demo finished")
+	for _, r := range res.Results {
+		recordCheck("concurrent.user", r)
+		switch r.Outcome {
+		case outcomeAllowed:
+			res.Completed++
+		case outcomeDenied:
+			res.Denied++
+		default:
+			res.Errors++
+		}
+	}
+	fmt.Fprintln(w, "  Concurrency demo finished")
+	return res
 }
 
-// DemoStats captures runtime metrics that the status UI exposes.
-type DemoStats struct {
-	AdvancedCalls  int     `json:"advanced_calls"`
-	PDFExports     int     `json:"pdf_exports"`
-	Projects       int     `json:"projects"`
-	LastTPS        float64 `json:"last_tps"`
-	ConcurrentJobs int     `json:"concurrent_jobs"`
+// recordVolume accumulates the data volume reported by analytics runs.
+func recordVolume(v analytics.Volume) {
+	statsMu.Lock()
+	stats.VolumeBytes += v.Bytes
+	stats.VolumeRecords += v.Records
+	statsMu.Unlock()
 }
 
 // startStatusServer exposes basic JSON/HTML status for the demo.
@@ -412,14 +665,19 @@ func startStatusServer() {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/status/json", handleStatusJSON)
 	mux.HandleFunc("/status", handleStatusHTML)
+	mux.Handle("/metrics", sdkMetrics.Handler(collectMetrics))
+	mux.Handle("/healthz", health.Live())
+	mux.Handle("/readyz", health.Ready(readyTimeout, checkShutdown, checkClient, checkLCC))
 
 	addr := os.Getenv("LCC_DEMO_STATUS_ADDR")
 	if addr == "" {
 		addr = ":8080"
 	}
 
+	srv := &http.Server{Addr: addr, Handler: mux}
+	statusServer.Store(srv)
 	log.Printf("Status server listening on http://localhost%s/status\n", addr)
-	if err := http.ListenAndServe(addr, mux); err != nil {
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		log.Printf("Status server error: %v", err)
 	}
 }
@@ -435,6 +693,7 @@ func handleStatusJSON(w http.ResponseWriter, r *http.Request) {
 func handleStatusHTML(w http.ResponseWriter, r *http.Request) {
 	statsMu.Lock()
 	s := stats
+	features, history := featureRows(), historyRows(10)
 	statsMu.Unlock()
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
@@ -448,6 +707,9 @@ func handleStatusHTML(w http.ResponseWriter, r *http.Request) {
     .card { background: #020617; border-radius: 8px; padding: 16px; margin-bottom: 12px; border: 1px solid #1f2937; }
     .label { color: #9ca3af; }
     .value { font-weight: bold; }
+    table { border-collapse: collapse; width: 100%%; }
+    th, td { text-align: left; padding: 4px 8px; border-bottom: 1px solid #1f2937; }
+    th { color: #9ca3af; font-weight: normal; }
   </style>
 </head>
 <body>
@@ -458,8 +720,52 @@ func handleStatusHTML(w http.ResponseWriter, r *http.Request) {
     <div><span class="label">Projects created:</span> <span class="value">%d</span></div>
     <div><span class="label">Last TPS (api.v1.demo):</span> <span class="value">%.1f</span></div>
     <div><span class="label">Concurrent jobs (demo):</span> <span class="value">%d</span></div>
+    <div><span class="label">Data processed (volume):</span> <span class="value">%d bytes / %d records</span></div>
+  </div>
+  <div class="card">
+    <table>
+      <tr><th>Feature</th><th>Attempts</th><th>Allowed</th><th>Denied (by reason)</th><th>Fallbacks</th><th>Errors</th><th>Last current / remaining / max</th></tr>
+%s    </table>
+  </div>
+  <div class="card">
+    <table>
+      <tr><th>Time</th><th>Feature</th><th>Outcome</th><th>Reason</th></tr>
+%s    </table>
   </div>
   <p>JSON endpoint: <code>/status/json</code></p>
 </body>
-</html>`, s.AdvancedCalls, s.PDFExports, s.Projects, s.LastTPS, s.ConcurrentJobs)
+</html>`, s.AdvancedCalls, s.PDFExports, s.Projects, s.LastTPS, s.ConcurrentJobs, s.VolumeBytes, s.VolumeRecords, features, history)
+}
+
+// featureRows renders the per-feature counters as table rows. statsMu must
+// be held.
+func featureRows() string {
+	var b strings.Builder
+	for _, id := range sortedFeatureIDs() {
+		f := stats.Features[id]
+		reasons := make([]string, 0, len(f.Denied))
+		for reason, n := range f.Denied {
+			reasons = append(reasons, fmt.Sprintf("%s: %d", html.EscapeString(reason), n))
+		}
+		sort.Strings(reasons)
+		last := "-"
+		if f.Last != nil {
+			last = fmt.Sprintf("%.1f / %.1f / %.1f", f.Last.Current, f.Last.Remaining, f.Last.Max)
+		}
+		fmt.Fprintf(&b, "      <tr><td>%s</td><td>%d</td><td>%d</td><td>%d %s</td><td>%d</td><td>%d</td><td>%s</td></tr>\n",
+			html.EscapeString(id), f.Attempts, f.Allowed, f.DeniedTotal(), strings.Join(reasons, ", "), f.Fallbacks, f.Errors, last)
+	}
+	return b.String()
+}
+
+// historyRows renders the last n history events, newest first. statsMu
+// must be held.
+func historyRows(n int) string {
+	var b strings.Builder
+	for i := len(stats.History) - 1; i >= 0 && i >= len(stats.History)-n; i-- {
+		e := stats.History[i]
+		fmt.Fprintf(&b, "      <tr><td>%s</td><td>%s</td><td>%s</td><td>%s</td></tr>\n",
+			e.Time.Format("15:04:05"), html.EscapeString(e.FeatureID), e.Outcome, html.EscapeString(e.Reason))
+	}
+	return b.String()
 }
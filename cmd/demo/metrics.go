@@ -0,0 +1,45 @@
+package main
+
+import (
+	"time"
+
+	"demo-app/internal/metrics"
+)
+
+// Demo metric families on top of the shared SDK ones.
+const (
+	demoChecks  = "lcc_demo_checks_total"
+	demoDenials = "lcc_demo_denials_total"
+)
+
+// sdkMetrics records the demo's SDK calls; the status server serves it on
+// /metrics.
+var sdkMetrics = func() *metrics.Registry {
+	r := metrics.NewSDKRegistry()
+	r.NewCounter(demoChecks, "Demo license checks by feature and outcome.")
+	r.NewCounter(demoDenials, "Demo license denials by feature and reason.")
+	return r
+}()
+
+// observeSDK counts an SDK call of the current product.
+func observeSDK(op, featureID string, start time.Time, allowed bool, err error) {
+	var product string
+	if _, cfg := registered(); cfg != nil {
+		product = cfg.ProductID
+	}
+	sdkMetrics.ObserveCall(product, op, featureID, start, allowed, err)
+}
+
+// collectMetrics refreshes the gauges. The demo counters are counted by
+// recordCheck, so they keep counting across a stats reset.
+func collectMetrics() {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+
+	sdkMetrics.Reset(metrics.RegisteredInstances)
+	sdkMetrics.Reset(metrics.ActiveSlots)
+	if cli, cfg := registered(); cli != nil {
+		sdkMetrics.Set(metrics.RegisteredInstances, 1, "product", cfg.ProductID)
+		sdkMetrics.Set(metrics.ActiveSlots, float64(stats.ConcurrentJobs), "product", cfg.ProductID)
+	}
+}
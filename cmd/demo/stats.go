@@ -0,0 +1,135 @@
+package main
+
+import (
+	"sort"
+	"time"
+)
+
+// statsHistoryLen is how many checks the stats history keeps.
+const statsHistoryLen = 100
+
+// DemoStats captures runtime metrics that the status UI exposes. The flat
+// counters predate Features and are kept for compatibility.
+type DemoStats struct {
+	AdvancedCalls  int     `json:"advanced_calls"`
+	PDFExports     int     `json:"pdf_exports"`
+	Projects       int     `json:"projects"`
+	LastTPS        float64 `json:"last_tps"`
+	ConcurrentJobs int     `json:"concurrent_jobs"`
+	VolumeBytes    int64   `json:"volume_bytes"`
+	VolumeRecords  int64   `json:"volume_records"`
+
+	Features map[string]*FeatureStats `json:"features,omitempty"`
+	History  []StatsEvent             `json:"history,omitempty"` // oldest first
+}
+
+// FeatureStats counts the license checks of one feature.
+type FeatureStats struct {
+	Attempts  int            `json:"attempts"`
+	Allowed   int            `json:"allowed"`
+	Denied    map[string]int `json:"denied,omitempty"` // reason -> count, including fallbacks and warnings
+	Fallbacks int            `json:"fallbacks"`
+	Errors    int            `json:"errors"`
+	Last      *LimitSample   `json:"last,omitempty"` // last limit values seen
+}
+
+// DeniedTotal is the number of denied checks across all reasons.
+func (f *FeatureStats) DeniedTotal() int {
+	n := 0
+	for _, c := range f.Denied {
+		n += c
+	}
+	return n
+}
+
+// LimitSample is the state of a quota, capacity or rate limit at one check.
+type LimitSample struct {
+	Time      time.Time `json:"time"`
+	Current   float64   `json:"current"`
+	Remaining float64   `json:"remaining"`
+	Max       float64   `json:"max"`
+}
+
+// StatsEvent is one check in the history.
+type StatsEvent struct {
+	Time      time.Time `json:"time"`
+	FeatureID string    `json:"feature_id"`
+	Outcome   string    `json:"outcome"`
+	Reason    string    `json:"reason,omitempty"`
+}
+
+// featureStats returns the counters of featureID. statsMu must be held.
+func featureStats(featureID string) *FeatureStats {
+	if stats.Features == nil {
+		stats.Features = make(map[string]*FeatureStats)
+	}
+	f := stats.Features[featureID]
+	if f == nil {
+		f = &FeatureStats{}
+		stats.Features[featureID] = f
+	}
+	return f
+}
+
+// recordCheck counts the outcome of one check of featureID, in the stats
+// and in the demo metrics. A result with a Max also becomes the feature's
+// last limit sample.
+func recordCheck(featureID string, r opResult) {
+	now := time.Now()
+	statsMu.Lock()
+	defer statsMu.Unlock()
+
+	f := featureStats(featureID)
+	f.Attempts++
+	sdkMetrics.Add(demoChecks, 1, "feature", featureID, "outcome", r.Outcome)
+	switch r.Outcome {
+	case outcomeAllowed:
+		f.Allowed++
+	case outcomeError:
+		f.Errors++
+	default:
+		if r.Outcome == outcomeFallback {
+			f.Fallbacks++
+		}
+		reason := r.Reason
+		if reason == "" {
+			reason = "unknown"
+		}
+		if f.Denied == nil {
+			f.Denied = make(map[string]int)
+		}
+		f.Denied[reason]++
+		sdkMetrics.Add(demoDenials, 1, "feature", featureID, "reason", reason)
+	}
+	if r.Max > 0 {
+		f.Last = &LimitSample{Time: now, Current: r.Current, Remaining: r.Max - r.Current, Max: r.Max}
+	}
+
+	stats.History = append(stats.History, StatsEvent{Time: now, FeatureID: featureID, Outcome: r.Outcome, Reason: r.Reason})
+	if n := len(stats.History) - statsHistoryLen; n > 0 {
+		stats.History = append(stats.History[:0:0], stats.History[n:]...)
+	}
+}
+
+// recordQuota keeps the quota left after a Consume as the feature's last
+// limit sample.
+func recordQuota(featureID string, remaining, limit int) {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+	featureStats(featureID).Last = &LimitSample{
+		Time:      time.Now(),
+		Current:   float64(limit - remaining),
+		Remaining: float64(remaining),
+		Max:       float64(limit),
+	}
+}
+
+// sortedFeatureIDs lists the features in the stats. statsMu must be held.
+func sortedFeatureIDs() []string {
+	ids := make([]string, 0, len(stats.Features))
+	for id := range stats.Features {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
@@ -108,3 +109,61 @@ func TestStatusServerHTML(t *testing.T) {
 		t.Fatalf("HTML does not contain expected value for AdvancedCalls: %s", body)
 	}
 }
+
+func TestRecordCheck(t *testing.T) {
+	statsMu.Lock()
+	stats = DemoStats{}
+	statsMu.Unlock()
+
+	recordCheck("pdf_export", opResult{Outcome: outcomeAllowed})
+	recordCheck("pdf_export", opResult{Outcome: outcomeDenied, Reason: "quota exceeded"})
+	recordCheck("pdf_export", opResult{Outcome: outcomeFallback, Reason: "quota exceeded"})
+	recordCheck("pdf_export", opResult{Outcome: outcomeDenied, Reason: "tier"})
+	recordCheck("pdf_export", opResult{Outcome: outcomeError, Error: "boom"})
+	recordCheck("capacity.project.count", opResult{Outcome: outcomeAllowed, Current: 3, Max: 5})
+	recordQuota("pdf_export", 7, 10)
+	for i := 0; i < statsHistoryLen; i++ {
+		recordCheck("api.v1.demo", opResult{Outcome: outcomeAllowed})
+	}
+
+	statsMu.Lock()
+	defer statsMu.Unlock()
+	f := stats.Features["pdf_export"]
+	if f.Attempts != 5 || f.Allowed != 1 || f.Fallbacks != 1 || f.Errors != 1 || f.DeniedTotal() != 3 || f.Denied["quota exceeded"] != 2 {
+		t.Errorf("pdf_export stats = %+v", f)
+	}
+	if f.Last == nil || f.Last.Remaining != 7 || f.Last.Current != 3 || f.Last.Max != 10 {
+		t.Errorf("pdf_export last = %+v", f.Last)
+	}
+	if l := stats.Features["capacity.project.count"].Last; l == nil || l.Remaining != 2 {
+		t.Errorf("capacity last = %+v", l)
+	}
+	if len(stats.History) != statsHistoryLen || stats.History[0].FeatureID != "api.v1.demo" {
+		t.Errorf("history has %d events, first %+v", len(stats.History), stats.History[0])
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	sdkMetrics.Reset(demoChecks)
+	sdkMetrics.Reset(demoDenials)
+	recordCheck("pdf_export", opResult{Outcome: outcomeAllowed})
+	recordCheck("pdf_export", opResult{Outcome: outcomeDenied, Reason: "quota exceeded"})
+	// A switch resets the stats; the counters keep counting
+	statsMu.Lock()
+	stats = DemoStats{}
+	statsMu.Unlock()
+	recordCheck("pdf_export", opResult{Outcome: outcomeAllowed})
+
+	rec := httptest.NewRecorder()
+	sdkMetrics.Handler(collectMetrics).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rec.Body.String()
+	for _, line := range []string{
+		`lcc_demo_checks_total{feature="pdf_export",outcome="allowed"} 2`,
+		`lcc_demo_checks_total{feature="pdf_export",outcome="denied"} 1`,
+		`lcc_demo_denials_total{feature="pdf_export",reason="quota exceeded"} 1`,
+	} {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("missing %s in:\n%s", line, body)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// tierManifests maps the tier names accepted by switch to the manifests
+// the tier builds use.
+var tierManifests = map[string]string{
+	"basic": "configs/lcc-features.basic.yaml",
+	"pro":   "configs/lcc-features.pro.yaml",
+	"ent":   "configs/lcc-features.ent.yaml",
+}
+
+// switchTier closes the current client and registers again with the
+// manifest target (a tier name or a manifest path; empty keeps the current
+// manifest) and, if set, productID. Without productID the manifest's
+// product_id applies again, even after an earlier switch to another ID.
+// Unless keepStats is set the demo stats and local demo state start over.
+// On failure the previous configuration is registered again; if that fails
+// too, no client is left registered and SDK calls report errNotRegistered.
+// Progress is printed to w.
+func switchTier(w io.Writer, cur *demoConfig, target, productID string, keepStats bool) (*demoConfig, error) {
+	next := *cur
+	next.Sources = make(map[string]string, len(cur.Sources))
+	for k, v := range cur.Sources {
+		next.Sources[k] = v
+	}
+
+	path := cur.Manifest
+	if p, ok := tierManifests[target]; ok {
+		path = p
+	} else if target != "" {
+		path = target
+	}
+	if path != cur.Manifest {
+		next.Manifest, next.Sources["manifest"] = path, sourceSwitch
+	}
+	if err := next.useManifest(path); err != nil {
+		return cur, err
+	}
+	switch {
+	case productID != "":
+		next.ProductID, next.Sources["product_id"] = productID, sourceSwitch
+	case next.Sources["product_id"] == sourceSwitch && next.features.SDK.ProductID != "":
+		// An earlier --product-id held for its switch only
+		next.ProductID, next.Sources["product_id"] = next.features.SDK.ProductID, sourceManifest
+	}
+
+	fmt.Fprintf(w, "\n[Switch] %s %s -> %s %s\n", cur.ProductID, cur.ProductVersion, next.ProductID, next.ProductVersion)
+	clientMu.Lock()
+	if lccClient != nil {
+		lccClient.Close()
+		lccClient = nil
+	}
+	clientMu.Unlock()
+	next.print(w)
+	if err := initLCC(w, &next); err != nil {
+		if rerr := initLCC(w, cur); rerr != nil {
+			return cur, fmt.Errorf("failed to switch: %w (restoring %s also failed: %v)", err, cur.ProductID, rerr)
+		}
+		return cur, fmt.Errorf("failed to switch, still registered as %s: %w", cur.ProductID, err)
+	}
+	initDispatcher(next.features)
+
+	if !keepStats {
+		statsMu.Lock()
+		stats = DemoStats{}
+		statsMu.Unlock()
+		projectCount = 0
+		requestHistoryMu.Lock()
+		requestHistory = nil
+		requestHistoryMu.Unlock()
+		fmt.Fprintln(w, "Stats reset")
+	}
+	return &next, nil
+}
+
+// switchTierDemo asks for the tier or manifest to switch to, switches and
+// shows the license of the new product.
+func switchTierDemo(cur *demoConfig) *demoConfig {
+	var target, productID, keep string
+	fmt.Printf("Tier (basic, pro, ent) or manifest path [%s]: ", cur.Manifest)
+	fmt.Scanln(&target)
+	fmt.Print("Product ID [from manifest]: ")
+	fmt.Scanln(&productID)
+	fmt.Print("Keep stats? [y/N]: ")
+	fmt.Scanln(&keep)
+
+	next, err := switchTier(os.Stdout, cur, target, productID, strings.EqualFold(keep, "y"))
+	if err != nil {
+		fmt.Printf("✗ %v\n", err)
+		return next
+	}
+	cli, _ := registered()
+	fmt.Printf("Instance ID: %s\n", cli.GetInstanceID())
+	showLicenseInfo(os.Stdout)
+	return next
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"crypto"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"demo-app/internal/license"
+	"demo-app/internal/web"
+)
+
+// Exit codes
+const (
+	exitOK      = 0
+	exitInvalid = 1
+	exitUsage   = 2
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(exitUsage)
+	}
+
+	switch os.Args[1] {
+	case "issue":
+		os.Exit(runIssue(os.Args[2:]))
+	case "verify":
+		os.Exit(runVerify(os.Args[2:]))
+	case "public-key":
+		os.Exit(runPublicKey())
+	default:
+		usage()
+		os.Exit(exitUsage)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage:")
+	fmt.Fprintln(os.Stderr, "  lcc-license issue  --tier <basic|professional|enterprise> --customer <name> [--days N] [--out file]")
+	fmt.Fprintln(os.Stderr, "  lcc-license verify --file <license.json> [--product <id>] [--pubkey <pem>] [--at <RFC3339>]")
+	fmt.Fprintln(os.Stderr, "  lcc-license public-key")
+}
+
+func runIssue(args []string) int {
+	fs := flag.NewFlagSet("issue", flag.ExitOnError)
+	tier := fs.String("tier", "", "tier to license (basic, professional, enterprise)")
+	customer := fs.String("customer", "", "customer the license is issued to")
+	days := fs.Int("days", 365, "validity in days")
+	out := fs.String("out", "", "write license to file instead of stdout")
+	_ = fs.Parse(args)
+
+	sl, err := web.IssueLicense(web.DefaultDataDir(), *tier, *customer, time.Duration(*days)*24*time.Hour)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "issue failed: %v\n", err)
+		return exitUsage
+	}
+
+	data, err := json.MarshalIndent(sl, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "encode failed: %v\n", err)
+		return exitUsage
+	}
+	if *out == "" {
+		fmt.Println(string(data))
+		return exitOK
+	}
+	if err := os.WriteFile(*out, append(data, '\n'), 0600); err != nil {
+		fmt.Fprintf(os.Stderr, "write failed: %v\n", err)
+		return exitUsage
+	}
+	fmt.Printf("✓ License %s written to %s (expires %s)\n", sl.License.LicenseID, *out, sl.License.ExpiresAt.Format(time.RFC3339))
+	return exitOK
+}
+
+func runVerify(args []string) int {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	file := fs.String("file", "", "license file to verify")
+	product := fs.String("product", "", "expected product ID (optional)")
+	pubkey := fs.String("pubkey", "", "PEM public key file (default: issuer key from ~/.lcc-demo/keys)")
+	at := fs.String("at", "", "verify as of this RFC3339 time (default: now)")
+	_ = fs.Parse(args)
+
+	if *file == "" {
+		fmt.Fprintln(os.Stderr, "--file is required")
+		return exitUsage
+	}
+	data, err := os.ReadFile(*file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "read failed: %v\n", err)
+		return exitUsage
+	}
+
+	now := time.Now()
+	if *at != "" {
+		now, err = time.Parse(time.RFC3339, *at)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid --at: %v\n", err)
+			return exitUsage
+		}
+	}
+
+	var pub crypto.PublicKey
+	if *pubkey != "" {
+		pemData, err := os.ReadFile(*pubkey)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "read public key: %v\n", err)
+			return exitUsage
+		}
+		pub, err = license.ParsePublicKeyPEM(pemData)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return exitUsage
+		}
+	} else {
+		pemStr, err := web.LicenseIssuerPublicKeyPEM(web.DefaultDataDir())
+		if errors.Is(err, web.ErrNoIssuerKey) {
+			fmt.Fprintf(os.Stderr, "%v: issue a license first or pass --pubkey\n", err)
+			return exitUsage
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return exitUsage
+		}
+		pub, err = license.ParsePublicKeyPEM([]byte(pemStr))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "issuer public key: %v\n", err)
+			return exitUsage
+		}
+	}
+
+	sl, err := license.Parse(data)
+	if err == nil {
+		err = license.Verify(sl, pub, license.VerifyOptions{ProductID: *product, Now: now})
+	}
+	if err != nil {
+		reason := license.ReasonOf(err)
+		if reason == "" {
+			fmt.Fprintf(os.Stderr, "verify failed: %v\n", err)
+			return exitUsage
+		}
+		fmt.Printf("✗ License rejected: %s\n", err)
+		return exitInvalid
+	}
+
+	doc := sl.License
+	fmt.Printf("✓ License %s is valid\n", doc.LicenseID)
+	fmt.Printf("  Product:  %s (%s)\n", doc.ProductID, doc.Tier)
+	fmt.Printf("  Customer: %s\n", doc.Customer)
+	fmt.Printf("  Expires:  %s\n", doc.ExpiresAt.Format(time.RFC3339))
+	return exitOK
+}
+
+func runPublicKey() int {
+	pemStr, err := web.LicenseIssuerPublicKeyPEM(web.DefaultDataDir())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return exitUsage
+	}
+	fmt.Print(pemStr)
+	return exitOK
+}
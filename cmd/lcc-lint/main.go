@@ -0,0 +1,113 @@
+// Command lcc-lint checks lcc-features manifests before they reach codegen.
+//
+//	lcc-lint [--json] [--module dir] [manifest.yaml ...]
+//
+// Without arguments it lints lcc-features.yaml and configs/lcc-features.*.yaml.
+// Findings are printed as file:line:col: severity: message (rule).
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"demo-app/internal/manifest"
+)
+
+// Exit codes
+const (
+	exitOK      = 0
+	exitInvalid = 1
+	exitUsage   = 2
+)
+
+type fileResult struct {
+	Path   string           `json:"path"`
+	Issues []manifest.Issue `json:"issues"`
+	Error  string           `json:"error,omitempty"`
+}
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	fs := flag.NewFlagSet("lcc-lint", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "print findings as JSON")
+	moduleDir := fs.String("module", ".", "directory of the Go module intercept targets live in")
+	noTargets := fs.Bool("no-targets", false, "skip resolving intercept/fallback targets")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: lcc-lint [--json] [--module dir] [--no-targets] [manifest.yaml ...]")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+
+	paths := fs.Args()
+	if len(paths) == 0 {
+		paths = defaultManifests()
+	}
+	if len(paths) == 0 {
+		fmt.Fprintln(os.Stderr, "no manifests found")
+		return exitUsage
+	}
+
+	var mod *manifest.Module
+	if !*noTargets {
+		var err error
+		if mod, err = manifest.FindModule(*moduleDir); err != nil {
+			fmt.Fprintf(os.Stderr, "module: %v (use --no-targets to skip target checks)\n", err)
+			return exitUsage
+		}
+	}
+	linter := manifest.NewLinter(mod)
+
+	code := exitOK
+	results := make([]fileResult, 0, len(paths))
+	for _, p := range paths {
+		res := fileResult{Path: p, Issues: []manifest.Issue{}}
+		issues, err := linter.LintFile(p)
+		if err != nil {
+			res.Error = err.Error()
+			code = exitUsage
+		} else {
+			res.Issues = append(res.Issues, issues...)
+			if manifest.HasErrors(issues) && code == exitOK {
+				code = exitInvalid
+			}
+		}
+		results = append(results, res)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(results)
+		return code
+	}
+	total := 0
+	for _, res := range results {
+		if res.Error != "" {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", res.Path, res.Error)
+			continue
+		}
+		for _, issue := range res.Issues {
+			fmt.Println(issue)
+		}
+		total += len(res.Issues)
+	}
+	if code == exitOK {
+		fmt.Fprintf(os.Stderr, "✓ %d manifest(s) OK (%d warning(s))\n", len(results), total)
+	}
+	return code
+}
+
+func defaultManifests() []string {
+	var out []string
+	if _, err := os.Stat("lcc-features.yaml"); err == nil {
+		out = append(out, "lcc-features.yaml")
+	}
+	matches, _ := filepath.Glob(filepath.Join("configs", "lcc-features.*.yaml"))
+	return append(out, matches...)
+}
@@ -0,0 +1,137 @@
+// Command lcc-scaffold generates a starter lcc-features manifest from the
+// exported functions of this module's packages.
+//
+//	lcc-scaffold [--dir internal] [--out lcc-features.yaml] [--dry-run] [--json]
+//
+// If the output file exists, new features are merged into it; features it
+// already declares (by ID or intercept target) are left untouched.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	iofs "io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"demo-app/internal/manifest"
+)
+
+// Exit codes
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+// defaultExclude lists the packages under internal/ that are infrastructure
+// rather than licensable features.
+const defaultExclude = "web,manifest,deps,semver,license,offline,dispatch,health,metrics"
+
+type result struct {
+	Out      string             `json:"out"`
+	Created  bool               `json:"created"`
+	Funcs    int                `json:"functions"`
+	Features []manifest.Feature `json:"features"`
+	manifest.MergeResult
+}
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	fs := flag.NewFlagSet("lcc-scaffold", flag.ExitOnError)
+	moduleDir := fs.String("module", ".", "directory of the Go module to scan")
+	dir := fs.String("dir", "internal", "directory to scan, relative to the module root")
+	exclude := fs.String("exclude", defaultExclude, "comma-separated package names to skip")
+	out := fs.String("out", "lcc-features.yaml", "manifest to create or merge into")
+	productID := fs.String("product", "", "sdk.product_id for a new manifest (default: module name)")
+	lccURL := fs.String("lcc-url", "http://localhost:7086", "sdk.lcc_url for a new manifest")
+	dryRun := fs.Bool("dry-run", false, "print the resulting manifest instead of writing it")
+	asJSON := fs.Bool("json", false, "print a JSON summary")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: lcc-scaffold [--module dir] [--dir internal] [--exclude list] [--out file] [--dry-run] [--json]")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	mod, err := manifest.FindModule(*moduleDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "module: %v\n", err)
+		return exitUsage
+	}
+	funcs, err := manifest.ScanFuncs(mod, *dir, strings.Split(*exclude, ","))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "scan: %v\n", err)
+		return exitError
+	}
+	suggested := manifest.Suggest(funcs)
+
+	res := result{Out: *out, Funcs: len(funcs), Features: suggested}
+	existing, err := os.ReadFile(*out)
+	var data []byte
+	switch {
+	case errors.Is(err, iofs.ErrNotExist):
+		res.Created = true
+		m := &manifest.Manifest{Features: suggested}
+		m.SDK.LCCURL = *lccURL
+		m.SDK.ProductID = *productID
+		if m.SDK.ProductID == "" {
+			m.SDK.ProductID = filepath.Base(mod.Path)
+		}
+		m.SDK.ProductVersion = "1.0.0"
+		data = manifest.Render(m)
+		for _, f := range suggested {
+			res.Added = append(res.Added, f.ID)
+		}
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "%s: %v\n", *out, err)
+		return exitError
+	default:
+		if data, res.MergeResult, err = manifest.Merge(existing, suggested); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", *out, err)
+			return exitError
+		}
+	}
+
+	if *dryRun {
+		if !*asJSON {
+			os.Stdout.Write(data)
+		}
+	} else if len(res.Added) > 0 {
+		if err := os.WriteFile(*out, data, 0o644); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", *out, err)
+			return exitError
+		}
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(res)
+		return exitOK
+	}
+	for _, id := range res.Added {
+		fmt.Fprintf(os.Stderr, "+ %s\n", id)
+	}
+	for _, id := range res.Skipped {
+		fmt.Fprintf(os.Stderr, "= %s (already in %s)\n", id, *out)
+	}
+	verb := "merged into"
+	if res.Created {
+		verb = "created"
+	}
+	if *dryRun {
+		verb = "would be " + verb
+	}
+	fmt.Fprintf(os.Stderr, "✓ %d function(s) scanned, %d feature(s) %s %s\n", len(funcs), len(res.Added), verb, *out)
+	return exitOK
+}
@@ -1,131 +1,227 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"net/http"
 	"os"
 	"os/exec"
+	"sort"
+	"strings"
 	"time"
 )
 
-// DemoStats mirrors the JSON schema exposed by the demo status server.
+// Exit codes of the demo subcommands.
+const (
+	demoExitOK     = 0
+	demoExitError  = 1
+	demoExitUsage  = 2
+	demoExitDenied = 3
+)
+
+// CLIOutput mirrors the --json summary of a demo subcommand.
+type CLIOutput struct {
+	Command  string         `json:"command"`
+	Results  []OpResult     `json:"results,omitempty"`
+	Stats    *DemoStats     `json:"stats,omitempty"`
+	Summary  map[string]int `json:"summary"`
+	ExitCode int            `json:"exit_code"`
+}
+
+// DemoStats mirrors the demo stats, as served on /status/json and in the
+// --json summary.
 type DemoStats struct {
 	AdvancedCalls  int     `json:"advanced_calls"`
 	PDFExports     int     `json:"pdf_exports"`
 	Projects       int     `json:"projects"`
 	LastTPS        float64 `json:"last_tps"`
 	ConcurrentJobs int     `json:"concurrent_jobs"`
+	VolumeBytes    int64   `json:"volume_bytes"`
+	VolumeRecords  int64   `json:"volume_records"`
+
+	Features map[string]*FeatureStats `json:"features,omitempty"`
+	History  []StatsEvent             `json:"history,omitempty"`
 }
 
-func main() {
-	addr := ":19080"
-	statusURL := "http://127.0.0.1" + addr + "/status/json"
+// FeatureStats mirrors the per-feature counters of the demo stats.
+type FeatureStats struct {
+	Attempts  int            `json:"attempts"`
+	Allowed   int            `json:"allowed"`
+	Denied    map[string]int `json:"denied,omitempty"`
+	Fallbacks int            `json:"fallbacks"`
+	Errors    int            `json:"errors"`
+	Last      *LimitSample   `json:"last,omitempty"`
+}
+
+// LimitSample mirrors the last limit values of a feature.
+type LimitSample struct {
+	Time      time.Time `json:"time"`
+	Current   float64   `json:"current"`
+	Remaining float64   `json:"remaining"`
+	Max       float64   `json:"max"`
+}
+
+// StatsEvent mirrors one entry of the stats history.
+type StatsEvent struct {
+	Time      time.Time `json:"time"`
+	FeatureID string    `json:"feature_id"`
+	Outcome   string    `json:"outcome"`
+	Reason    string    `json:"reason,omitempty"`
+}
+
+// OpResult mirrors the outcome of one demo operation.
+type OpResult struct {
+	Outcome string `json:"outcome"`
+	Reason  string `json:"reason,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// scenarios exercise the four limitation models in five subcommands,
+// consumption twice. feature is the feature whose stats must show the checks.
+var scenarios = []struct {
+	name    string
+	args    []string
+	feature string
+}{
+	{"consumption (advanced analytics)", []string{"run", "advanced-analytics"}, "advanced_analytics"},
+	{"consumption (PDF export)", []string{"run", "pdf-export"}, "pdf_export"},
+	{"capacity (projects)", []string{"project", "create"}, "capacity.project.count"},
+	{"TPS (demo API)", []string{"api", "call", "--count", "3"}, "api.v1.demo"},
+	{"concurrency (jobs)", []string{"jobs"}, "concurrent.user"},
+}
 
+func main() {
 	if _, err := os.Stat("./bin/demo-app"); err != nil {
 		fmt.Println("demo binary ./bin/demo-app not found; please run 'make build' first")
 		os.Exit(1)
 	}
 
-	cmd := exec.Command("./bin/demo-app")
-	cmd.Env = append(os.Environ(), "LCC_DEMO_STATUS_ADDR="+addr)
-
-	stdin, err := cmd.StdinPipe()
-	if err != nil {
-		fmt.Printf("failed to get stdin pipe: %v\n", err)
-		os.Exit(1)
+	fmt.Println("Running demo-app subcommands for regression run...")
+	failed := 0
+	for _, sc := range scenarios {
+		out, err := runDemo(sc.args)
+		if err == nil {
+			err = checkStats(out, sc.feature)
+		}
+		if err != nil {
+			fmt.Printf("  FAIL %s: %v\n", sc.name, err)
+			failed++
+			continue
+		}
+		fmt.Printf("  ok   %s: %s\n", sc.name, summary(out))
+		fmt.Printf("       %s: %s\n", sc.feature, featureSummary(out.Stats.Features[sc.feature]))
 	}
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
 
-	fmt.Println("Starting demo-app for regression run...")
-	if err := cmd.Start(); err != nil {
-		fmt.Printf("failed to start demo-app: %v\n", err)
+	if failed > 0 {
+		fmt.Printf("Regression FAILED: %d of %d scenarios\n", failed, len(scenarios))
+		fmt.Println("make sure LCC server is running and demo-app can register")
 		os.Exit(1)
 	}
+	fmt.Printf("Regression PASSED: all %d scenarios checked (consumption, capacity, TPS, concurrency)\n", len(scenarios))
+}
 
-	defer func() {
-		_ = cmd.Process.Kill()
-		_ = cmd.Wait()
-	}()
+// runDemo runs one demo subcommand with --json. A denied call still counts
+// as a license check; errors and usage failures do not.
+func runDemo(args []string) (*CLIOutput, error) {
+	var stdout bytes.Buffer
+	cmd := exec.Command("./bin/demo-app", append(args, "--json")...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
 
-	if err := waitForStatus(statusURL, 10*time.Second); err != nil {
-		fmt.Printf("status server not reachable: %v\n", err)
-		fmt.Println("make sure LCC server is running and demo-app can start successfully")
-		os.Exit(1)
+	code := demoExitOK
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return nil, err
+		}
+		code = exitErr.ExitCode()
 	}
-
-	sendChoice := func(choice int) {
-		_, _ = fmt.Fprintf(stdin, "%d\n", choice)
-		// Give the demo some time to execute the scenario
-		time.Sleep(400 * time.Millisecond)
+	if code == demoExitUsage {
+		return nil, fmt.Errorf("demo-app %s: usage error", strings.Join(args, " "))
 	}
 
-	// Drive a few key scenarios via the menu:
-	// 2: advanced analytics (consumption)
-	// 3: PDF export (consumption)
-	// 7: create project (capacity)
-	// 8: call demo API (TPS)
-	// 9: simulate concurrent jobs (concurrency)
-	sendChoice(2)
-	sendChoice(3)
-	sendChoice(7)
-	sendChoice(8)
-	sendChoice(9)
-
-	if err := waitForMetrics(statusURL, 8*time.Second); err != nil {
-		fmt.Printf("Regression FAILED: %v\n", err)
-		os.Exit(1)
+	var out CLIOutput
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		return nil, fmt.Errorf("invalid --json summary (exit %d): %w", code, err)
 	}
-
-	fmt.Println("Regression PASSED: all four limitation signals observed via status JSON")
+	switch {
+	case out.ExitCode != code:
+		return &out, fmt.Errorf("summary exit_code %d, process exited %d", out.ExitCode, code)
+	case code == demoExitError:
+		return &out, fmt.Errorf("exit %d: %s", code, summary(&out))
+	case code != demoExitOK && code != demoExitDenied:
+		return &out, fmt.Errorf("unexpected exit code %d", code)
+	case len(out.Results) == 0:
+		return &out, errors.New("no license checks reported")
+	}
+	return &out, nil
 }
 
-func waitForStatus(url string, timeout time.Duration) error {
-	deadline := time.Now().Add(timeout)
-	for time.Now().Before(deadline) {
-		resp, err := http.Get(url)
-		if err == nil {
-			resp.Body.Close()
-			if resp.StatusCode == http.StatusOK {
-				return nil
-			}
+// summary formats the outcome counts, e.g. "2 allowed, 1 denied".
+func summary(out *CLIOutput) string {
+	var parts []string
+	for _, o := range []string{"allowed", "fallback", "warned", "denied", "error"} {
+		if n := out.Summary[o]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, o))
 		}
-		time.Sleep(200 * time.Millisecond)
 	}
-	return errors.New("status endpoint did not become ready in time")
+	if len(parts) == 0 {
+		return "no calls"
+	}
+	return strings.Join(parts, ", ")
 }
 
-func waitForMetrics(url string, timeout time.Duration) error {
-	deadline := time.Now().Add(timeout)
-	seenConcurrent := false
-
-	for time.Now().Before(deadline) {
-		resp, err := http.Get(url)
-		if err != nil {
-			time.Sleep(200 * time.Millisecond)
-			continue
-		}
-
-		var s DemoStats
-		if err := json.NewDecoder(resp.Body).Decode(&s); err != nil {
-			resp.Body.Close()
-			time.Sleep(200 * time.Millisecond)
-			continue
-		}
-		resp.Body.Close()
-
-		if s.ConcurrentJobs > 0 {
-			seenConcurrent = true
-		}
-
-		if s.AdvancedCalls >= 1 && s.PDFExports >= 1 && s.Projects >= 1 && s.LastTPS > 0 && seenConcurrent {
-			return nil
+// checkStats verifies that the demo stats of a run counted every check of
+// featureID: one attempt and one history entry per result.
+func checkStats(out *CLIOutput, featureID string) error {
+	if out.Stats == nil {
+		return errors.New("no stats in the --json summary")
+	}
+	f := out.Stats.Features[featureID]
+	if f == nil || f.Attempts == 0 {
+		return fmt.Errorf("stats report no checks of %s", featureID)
+	}
+	if f.Attempts != len(out.Results) {
+		return fmt.Errorf("stats report %d checks of %s, summary %d", f.Attempts, featureID, len(out.Results))
+	}
+	denied := 0
+	for _, n := range f.Denied {
+		denied += n
+	}
+	if got := f.Allowed + denied + f.Errors; got != f.Attempts {
+		return fmt.Errorf("%s: %d allowed, %d denied and %d errors do not add up to %d attempts",
+			featureID, f.Allowed, denied, f.Errors, f.Attempts)
+	}
+	events := 0
+	for _, e := range out.Stats.History {
+		if e.FeatureID == featureID {
+			events++
 		}
-
-		time.Sleep(200 * time.Millisecond)
 	}
+	if events != f.Attempts {
+		return fmt.Errorf("history has %d checks of %s, want %d", events, featureID, f.Attempts)
+	}
+	return nil
+}
 
-	return fmt.Errorf("expected metrics not observed before timeout")
+// featureSummary formats the counters of a feature, e.g.
+// "3 attempts, 1 allowed, denied tier_insufficient=2, last 4/5".
+func featureSummary(f *FeatureStats) string {
+	parts := []string{fmt.Sprintf("%d attempts", f.Attempts), fmt.Sprintf("%d allowed", f.Allowed)}
+	reasons := make([]string, 0, len(f.Denied))
+	for reason := range f.Denied {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+	for _, reason := range reasons {
+		parts = append(parts, fmt.Sprintf("denied %s=%d", reason, f.Denied[reason]))
+	}
+	if f.Errors > 0 {
+		parts = append(parts, fmt.Sprintf("%d errors", f.Errors))
+	}
+	if f.Last != nil {
+		parts = append(parts, fmt.Sprintf("last %g/%g", f.Last.Current, f.Last.Max))
+	}
+	return strings.Join(parts, ", ")
 }
@@ -0,0 +1,311 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"demo-app/internal/web"
+)
+
+// Environment variables of the web server settings.
+const (
+	envListen       = "LCC_WEB_LISTEN"
+	envDataDir      = "LCC_WEB_DATA_DIR"
+	envConfig       = "LCC_WEB_CONFIG"
+	envLCCURL       = "LCC_URL"
+	envManifestPoll = "LCC_WEB_MANIFEST_POLL"
+	envDrainWait    = "LCC_WEB_DRAIN_WAIT"
+	envStaticDir    = "LCC_WEB_STATIC_DIR"
+	envTLSCert      = "LCC_WEB_TLS_CERT"
+	envTLSKey       = "LCC_WEB_TLS_KEY"
+	envLogLevel     = "LCC_WEB_LOG_LEVEL"
+	envOffline      = "LCC_WEB_OFFLINE"
+)
+
+// Where a setting came from, lowest precedence first.
+const (
+	sourceDefault = "default"
+	sourceFile    = "file"
+	sourceEnv     = "env"
+	sourceFlag    = "flag"
+)
+
+// logLevels are the accepted log levels.
+var logLevels = []string{"info", "quiet"}
+
+// webConfig is the effective configuration of the web server.
+type webConfig struct {
+	Listen        string            `json:"listen"`
+	DataDir       string            `json:"data_dir"`
+	ConfigFile    string            `json:"config_file"`
+	LCCURL        string            `json:"lcc_url"`
+	ManifestPaths []string          `json:"manifest_paths"`
+	ManifestPoll  time.Duration     `json:"manifest_poll"`
+	DrainWait     time.Duration     `json:"drain_wait"` // failing readiness before the HTTP shutdown
+	StaticDir     string            `json:"static_dir"` // "" serves the embedded UI
+	TLSCert       string            `json:"tls_cert,omitempty"`
+	TLSKey        string            `json:"tls_key,omitempty"`
+	LogLevel      string            `json:"log_level"`
+	Offline       bool              `json:"offline"`
+	Sources       map[string]string `json:"sources"` // setting -> default, file, env or flag
+
+	printConfig bool
+}
+
+// fileConfig is the config file: the server's persisted config.json, which
+// the web UI also writes lcc_url and manifest_paths to.
+type fileConfig struct {
+	Listen        string   `json:"listen"`
+	LCCURL        string   `json:"lcc_url"`
+	ManifestPaths []string `json:"manifest_paths"`
+	ManifestPoll  string   `json:"manifest_poll"`
+	DrainWait     string   `json:"drain_wait"`
+	StaticDir     string   `json:"static_dir"`
+	TLSCert       string   `json:"tls_cert"`
+	TLSKey        string   `json:"tls_key"`
+	LogLevel      string   `json:"log_level"`
+	Offline       *bool    `json:"offline"`
+}
+
+// loadConfig resolves the configuration from defaults, the config file,
+// the environment and the flags in args, in increasing precedence. The
+// data directory and the config file path cannot come from the file.
+func loadConfig(args []string, getenv func(string) string) (*webConfig, error) {
+	cfg := &webConfig{
+		Listen:        ":9144",
+		DataDir:       web.DefaultDataDir(),
+		LCCURL:        "http://localhost:7086",
+		ManifestPaths: web.DefaultManifestPaths,
+		ManifestPoll:  web.DefaultManifestPoll,
+		DrainWait:     defaultDrainWait,
+		LogLevel:      "info",
+		Sources:       map[string]string{},
+	}
+	for _, k := range []string{"listen", "data_dir", "config_file", "lcc_url", "manifest_paths", "manifest_poll", "drain_wait", "static_dir", "tls_cert", "tls_key", "log_level", "offline"} {
+		cfg.Sources[k] = sourceDefault
+	}
+
+	set := flag.NewFlagSet("web", flag.ContinueOnError)
+	set.SetOutput(io.Discard)
+	for _, name := range []string{"listen", "data-dir", "config", "lcc-url", "manifests", "manifest-poll", "drain-wait", "static-dir", "tls-cert", "tls-key", "log-level"} {
+		set.String(name, "", "")
+	}
+	set.Bool("offline", false, "")
+	set.BoolVar(&cfg.printConfig, "print-config", false, "")
+	if err := set.Parse(args); err != nil {
+		return nil, err
+	}
+	if set.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected argument %q", set.Arg(0))
+	}
+	flags := map[string]string{}
+	set.Visit(func(f *flag.Flag) { flags[f.Name] = f.Value.String() })
+
+	// override applies the env variable, then the flag, to a setting
+	override := func(key, env, flagName string, apply func(string) error) error {
+		if v := getenv(env); v != "" {
+			if err := apply(v); err != nil {
+				return fmt.Errorf("%s: %w", env, err)
+			}
+			cfg.Sources[key] = sourceEnv
+		}
+		if v, ok := flags[flagName]; ok {
+			if err := apply(v); err != nil {
+				return fmt.Errorf("--%s: %w", flagName, err)
+			}
+			cfg.Sources[key] = sourceFlag
+		}
+		return nil
+	}
+	str := func(dst *string) func(string) error {
+		return func(v string) error { *dst = v; return nil }
+	}
+
+	if err := override("data_dir", envDataDir, "data-dir", str(&cfg.DataDir)); err != nil {
+		return nil, err
+	}
+	cfg.ConfigFile = filepath.Join(cfg.DataDir, web.ConfigFileName)
+	if err := override("config_file", envConfig, "config", str(&cfg.ConfigFile)); err != nil {
+		return nil, err
+	}
+	if err := cfg.applyFile(); err != nil {
+		return nil, err
+	}
+
+	duration := func(dst *time.Duration) func(string) error {
+		return func(v string) error {
+			d, err := time.ParseDuration(v)
+			if err == nil && d < 0 {
+				err = fmt.Errorf("negative duration %s", v)
+			}
+			*dst = d
+			return err
+		}
+	}
+	parseOffline := func(v string) error {
+		b, err := strconv.ParseBool(v)
+		cfg.Offline = b
+		return err
+	}
+	parsePaths := func(v string) error {
+		cfg.ManifestPaths = web.SplitManifestPath(v)
+		return nil
+	}
+	for _, o := range []struct {
+		key, env, flag string
+		apply          func(string) error
+	}{
+		{"listen", envListen, "listen", str(&cfg.Listen)},
+		{"lcc_url", envLCCURL, "lcc-url", str(&cfg.LCCURL)},
+		{"manifest_paths", web.EnvManifestPath, "manifests", parsePaths},
+		{"manifest_poll", envManifestPoll, "manifest-poll", duration(&cfg.ManifestPoll)},
+		{"drain_wait", envDrainWait, "drain-wait", duration(&cfg.DrainWait)},
+		{"static_dir", envStaticDir, "static-dir", str(&cfg.StaticDir)},
+		{"tls_cert", envTLSCert, "tls-cert", str(&cfg.TLSCert)},
+		{"tls_key", envTLSKey, "tls-key", str(&cfg.TLSKey)},
+		{"log_level", envLogLevel, "log-level", str(&cfg.LogLevel)},
+		{"offline", envOffline, "offline", parseOffline},
+	} {
+		if err := override(o.key, o.env, o.flag, o.apply); err != nil {
+			return nil, err
+		}
+	}
+
+	if (cfg.TLSCert == "") != (cfg.TLSKey == "") {
+		return nil, fmt.Errorf("tls_cert and tls_key must be set together")
+	}
+	if !contains(logLevels, cfg.LogLevel) {
+		return nil, fmt.Errorf("invalid log_level %q (want one of %s)", cfg.LogLevel, strings.Join(logLevels, ", "))
+	}
+	return cfg, nil
+}
+
+// applyFile takes the settings of the config file. A missing file is not
+// an error.
+func (c *webConfig) applyFile() error {
+	data, err := os.ReadFile(c.ConfigFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var f fileConfig
+	if err := json.Unmarshal(data, &f); err != nil {
+		return fmt.Errorf("%s: %w", c.ConfigFile, err)
+	}
+	for _, s := range []struct {
+		key string
+		dst *string
+		v   string
+	}{
+		{"listen", &c.Listen, f.Listen},
+		{"lcc_url", &c.LCCURL, f.LCCURL},
+		{"static_dir", &c.StaticDir, f.StaticDir},
+		{"tls_cert", &c.TLSCert, f.TLSCert},
+		{"tls_key", &c.TLSKey, f.TLSKey},
+		{"log_level", &c.LogLevel, f.LogLevel},
+	} {
+		if s.v != "" {
+			*s.dst, c.Sources[s.key] = s.v, sourceFile
+		}
+	}
+	if len(f.ManifestPaths) > 0 {
+		c.ManifestPaths, c.Sources["manifest_paths"] = f.ManifestPaths, sourceFile
+	}
+	for _, s := range []struct {
+		key string
+		dst *time.Duration
+		v   string
+	}{
+		{"manifest_poll", &c.ManifestPoll, f.ManifestPoll},
+		{"drain_wait", &c.DrainWait, f.DrainWait},
+	} {
+		if s.v == "" {
+			continue
+		}
+		d, err := time.ParseDuration(s.v)
+		if err != nil || d < 0 {
+			return fmt.Errorf("%s: invalid %s %q", c.ConfigFile, s.key, s.v)
+		}
+		*s.dst, c.Sources[s.key] = d, sourceFile
+	}
+	if f.Offline != nil {
+		c.Offline, c.Sources["offline"] = *f.Offline, sourceFile
+	}
+	return nil
+}
+
+// serverOptions turns the configuration into web.Server options. The server
+// reads lcc_url and manifest_paths from the config file itself, so only
+// the higher-precedence values are passed on.
+func (c *webConfig) serverOptions() []web.ServerOption {
+	opts := []web.ServerOption{
+		web.WithDataDir(c.DataDir),
+		web.WithConfigFile(c.ConfigFile),
+		web.WithManifestPolling(c.ManifestPoll),
+		web.WithStaticDir(c.StaticDir),
+	}
+	if src := c.Sources["lcc_url"]; src == sourceEnv || src == sourceFlag {
+		opts = append(opts, web.WithLCCURL(c.LCCURL))
+	}
+	if c.Sources["manifest_paths"] == sourceFlag {
+		opts = append(opts, web.WithManifestPaths(c.ManifestPaths))
+	}
+	if c.Offline {
+		opts = append(opts, web.WithOfflineEvaluator())
+	}
+	return opts
+}
+
+// MarshalJSON writes manifest_poll and drain_wait as strings, e.g. "2s".
+func (c *webConfig) MarshalJSON() ([]byte, error) {
+	type plain webConfig
+	return json.Marshal(struct {
+		*plain
+		ManifestPoll string `json:"manifest_poll"`
+		DrainWait    string `json:"drain_wait"`
+	}{(*plain)(c), c.ManifestPoll.String(), c.DrainWait.String()})
+}
+
+const configUsage = `usage: web [options]
+
+Options (env variable in parentheses):
+  --listen ADDR          listen address, default :9144 (LCC_WEB_LISTEN)
+  --data-dir DIR         config file and keys, default ~/.lcc-demo (LCC_WEB_DATA_DIR)
+  --config FILE          config file, default <data-dir>/config.json (LCC_WEB_CONFIG)
+  --lcc-url URL          LCC server, default http://localhost:7086 (LCC_URL)
+  --manifests PATHS      comma-separated manifest paths or globs (LCC_MANIFEST_PATH)
+  --manifest-poll 2s     manifest change polling, 0 disables (LCC_WEB_MANIFEST_POLL)
+  --drain-wait 5s        how long /readyz fails before the shutdown, 0 disables
+                         (LCC_WEB_DRAIN_WAIT)
+  --static-dir DIR       serve the UI from DIR, e.g. internal/web/static, instead
+                         of the embedded assets (LCC_WEB_STATIC_DIR)
+  --tls-cert FILE        serve HTTPS with this certificate (LCC_WEB_TLS_CERT)
+  --tls-key FILE         and this key (LCC_WEB_TLS_KEY)
+  --log-level LEVEL      info, or quiet to drop the server log (LCC_WEB_LOG_LEVEL)
+  --offline              evaluate tier licenses in-process (LCC_WEB_OFFLINE)
+  --print-config         print the effective configuration as JSON and exit
+
+Flags override the environment, which overrides the config file. The config
+file takes the same settings as JSON keys (listen, lcc_url, manifest_paths,
+manifest_poll, drain_wait, static_dir, tls_cert, tls_key, log_level, offline).
+`
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	file := `{"lcc_url": "http://file:7086", "listen": ":1000", "manifest_poll": "5s", "drain_wait": "1s", "log_level": "quiet", "offline": true}`
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(file), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	env := map[string]string{
+		envDataDir: dir,
+		envListen:  ":2000",
+		envLCCURL:  "http://env:7086",
+	}
+	getenv := func(k string) string { return env[k] }
+
+	cfg, err := loadConfig([]string{"--lcc-url", "http://flag:7086", "--offline=false", "--drain-wait", "0"}, getenv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		key  string
+		got  any
+		want any
+		src  string
+	}{
+		{"data_dir", cfg.DataDir, dir, sourceEnv},
+		{"config_file", cfg.ConfigFile, filepath.Join(dir, "config.json"), sourceDefault},
+		{"listen", cfg.Listen, ":2000", sourceEnv},
+		{"lcc_url", cfg.LCCURL, "http://flag:7086", sourceFlag},
+		{"manifest_poll", cfg.ManifestPoll, 5 * time.Second, sourceFile},
+		{"drain_wait", cfg.DrainWait, time.Duration(0), sourceFlag},
+		{"log_level", cfg.LogLevel, "quiet", sourceFile},
+		{"offline", cfg.Offline, false, sourceFlag},
+		{"static_dir", cfg.StaticDir, "", sourceDefault},
+	} {
+		if c.got != c.want || cfg.Sources[c.key] != c.src {
+			t.Errorf("%s = %v (%s), want %v (%s)", c.key, c.got, cfg.Sources[c.key], c.want, c.src)
+		}
+	}
+
+	for _, args := range [][]string{
+		{"--tls-cert", "cert.pem"},
+		{"--log-level", "loud"},
+		{"--log-level", "debug"},
+		{"--manifest-poll", "-1s"},
+		{"--drain-wait", "soon"},
+		{"extra"},
+	} {
+		if _, err := loadConfig(args, getenv); err == nil {
+			t.Errorf("loadConfig(%q) accepted", args)
+		}
+	}
+}
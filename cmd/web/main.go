@@ -1,19 +1,98 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"demo-app/internal/web"
 )
 
+// shutdownTimeout bounds how long in-flight requests may take after a
+// signal.
+const shutdownTimeout = 10 * time.Second
+
+// defaultDrainWait is how long the server keeps serving with /readyz failing
+// after a signal, so that load balancers stop sending requests before it
+// shuts down.
+const defaultDrainWait = 5 * time.Second
+
 func main() {
+	cfg, err := loadConfig(os.Args[1:], os.Getenv)
+	switch {
+	case errors.Is(err, flag.ErrHelp):
+		fmt.Print(configUsage)
+		return
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "web: %v\n\n%s", err, configUsage)
+		os.Exit(2)
+	case cfg.printConfig:
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(cfg)
+		return
+	}
+	setLogLevel(cfg.LogLevel)
+	if cfg.Offline {
+		log.Printf("Offline mode: simulations evaluate tier licenses in-process")
+	}
+
 	// Start minimal Web UI + API server
-	srv := web.NewServer()
+	srv := web.NewServer(cfg.serverOptions()...)
+	defer srv.Close()
+
+	httpSrv := &http.Server{Addr: cfg.Listen, Handler: srv.Router()}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errc := make(chan error, 1)
+	scheme := "http"
+	if cfg.TLSCert != "" {
+		scheme = "https"
+		go func() { errc <- httpSrv.ListenAndServeTLS(cfg.TLSCert, cfg.TLSKey) }()
+	} else {
+		go func() { errc <- httpSrv.ListenAndServe() }()
+	}
+	log.Printf("LCC Demo Web UI listening on %s://localhost%s\n", scheme, cfg.Listen)
+
+	select {
+	case err := <-errc:
+		if !errors.Is(err, http.ErrServerClosed) {
+			srv.Close()
+			fmt.Fprintf(os.Stderr, "web server error: %v\n", err)
+			os.Exit(1)
+		}
+	case <-ctx.Done():
+		stop() // a second signal exits at once
+		log.Printf("Shutting down...")
+		srv.Drain()
+		if cfg.DrainWait > 0 {
+			log.Printf("Draining for %s", cfg.DrainWait)
+			time.Sleep(cfg.DrainWait)
+		}
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := httpSrv.Shutdown(shutdownCtx); err != nil {
+			log.Printf("web server shutdown: %v", err)
+		}
+	}
+}
 
-	addr := ":9144" // default web ui port
-	log.Printf("LCC Demo Web UI listening on http://localhost%s\n", addr)
-	if err := http.ListenAndServe(addr, srv.Router()); err != nil {
-		log.Fatalf("web server error: %v", err)
+// setLogLevel applies the log level. The server logs through the log
+// package without levels, so there are only two: info logs everything and
+// quiet nothing. Startup and shutdown errors still go to stderr.
+func setLogLevel(level string) {
+	if level == "quiet" {
+		log.SetOutput(io.Discard)
 	}
 }
@@ -2,24 +2,84 @@ package analytics
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"math/rand"
+	"sync"
 	"time"
 )
 
+// bytesPerRecord is the average size of a processed analytics event.
+const bytesPerRecord = 512
+
+// Volume is the amount of data processed by an analytics run.
+type Volume struct {
+	Bytes   int64
+	Records int64
+}
+
+// VolumeConsumer receives the data volume of each advanced analytics run,
+// e.g. to meter it against a "volume" license limit.
+type VolumeConsumer func(Volume)
+
+var (
+	consumerMu     sync.Mutex
+	volumeConsumer VolumeConsumer
+)
+
+// SetVolumeConsumer installs the hook called after each RunAdvanced; nil removes it.
+func SetVolumeConsumer(fn VolumeConsumer) {
+	consumerMu.Lock()
+	defer consumerMu.Unlock()
+	volumeConsumer = fn
+}
+
+func reportVolume(v Volume) {
+	consumerMu.Lock()
+	fn := volumeConsumer
+	consumerMu.Unlock()
+	if fn != nil {
+		fn(v)
+	}
+}
+
+var (
+	outMu sync.Mutex
+	out   io.Writer = os.Stdout
+)
+
+// SetOutput sets where the functions print their progress; nil restores stdout.
+func SetOutput(w io.Writer) {
+	outMu.Lock()
+	defer outMu.Unlock()
+	if w == nil {
+		w = os.Stdout
+	}
+	out = w
+}
+
+func output() io.Writer {
+	outMu.Lock()
+	defer outMu.Unlock()
+	return out
+}
+
 func RunBasic() {
-	fmt.Println("  Running basic analytics...")
+	w := output()
+	fmt.Fprintln(w, "  Running basic analytics...")
 	time.Sleep(500 * time.Millisecond)
 	
 	// Simulate basic analytics
 	pageViews := rand.Intn(1000) + 100
 	users := rand.Intn(100) + 10
 	
-	fmt.Printf("  Page Views: %d\n", pageViews)
-	fmt.Printf("  Total Users: %d\n", users)
+	fmt.Fprintf(w, "  Page Views: %d\n", pageViews)
+	fmt.Fprintf(w, "  Total Users: %d\n", users)
 }
 
 func RunAdvanced() {
-	fmt.Println("  Running advanced analytics with ML insights...")
+	w := output()
+	fmt.Fprintln(w, "  Running advanced analytics with ML insights...")
 	time.Sleep(1 * time.Second)
 	
 	// Simulate advanced analytics
@@ -28,10 +88,13 @@ func RunAdvanced() {
 	conversionRate := float64(rand.Intn(500)+50) / 100.0
 	churnPrediction := float64(rand.Intn(300)+50) / 100.0
 	
-	fmt.Printf("  Page Views: %d\n", pageViews)
-	fmt.Printf("  Total Users: %d\n", users)
-	fmt.Printf("  Conversion Rate: %.2f%%\n", conversionRate)
-	fmt.Printf("  Predicted Churn: %.2f%%\n", churnPrediction)
-	fmt.Println("  User Segments: Active, At-Risk, Churned")
-	fmt.Println("  Recommendations: Focus on at-risk users")
+	fmt.Fprintf(w, "  Page Views: %d\n", pageViews)
+	fmt.Fprintf(w, "  Total Users: %d\n", users)
+	fmt.Fprintf(w, "  Conversion Rate: %.2f%%\n", conversionRate)
+	fmt.Fprintf(w, "  Predicted Churn: %.2f%%\n", churnPrediction)
+	fmt.Fprintln(w, "  User Segments: Active, At-Risk, Churned")
+	fmt.Fprintln(w, "  Recommendations: Focus on at-risk users")
+
+	// Every page view is one processed event.
+	reportVolume(Volume{Records: int64(pageViews), Bytes: int64(pageViews) * bytesPerRecord})
 }
@@ -0,0 +1,228 @@
+// Package deps validates and evaluates "requires" / "conflicts_with"
+// relations between features, shared by tier definitions and manifests.
+package deps
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ReasonDependencyMissing is reported for an enabled feature whose required
+// features are not available.
+const ReasonDependencyMissing = "dependency_missing"
+
+// Relation kinds.
+const (
+	KindRequires      = "requires"
+	KindConflictsWith = "conflicts_with"
+)
+
+// Node is a feature with its declared relations.
+type Node struct {
+	ID            string
+	Requires      []string
+	ConflictsWith []string
+}
+
+// Edge is one declared relation.
+type Edge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Kind string `json:"kind"`
+}
+
+// Graph is the dependency graph of a feature set.
+type Graph struct {
+	Nodes  []string `json:"nodes"`
+	Edges  []Edge   `json:"edges"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+// Error is a validation problem with one feature's relations.
+type Error struct {
+	FeatureID string
+	Msg       string
+}
+
+func (e *Error) Error() string { return fmt.Sprintf("feature %s: %s", e.FeatureID, e.Msg) }
+
+// Validate checks that relations reference known features, that no feature
+// both requires and conflicts with another (directly or through its
+// requirements), and that requirements are acyclic.
+func Validate(nodes []Node) []error {
+	byID := make(map[string]Node, len(nodes))
+	for _, n := range nodes {
+		byID[n.ID] = n
+	}
+
+	var errs []error
+	for _, n := range nodes {
+		for _, kind := range []string{KindRequires, KindConflictsWith} {
+			refs := n.Requires
+			if kind == KindConflictsWith {
+				refs = n.ConflictsWith
+			}
+			for _, ref := range refs {
+				if ref == n.ID {
+					errs = append(errs, &Error{n.ID, kind + " itself"})
+				} else if _, ok := byID[ref]; !ok {
+					errs = append(errs, &Error{n.ID, fmt.Sprintf("%s unknown feature %q", kind, ref)})
+				}
+			}
+		}
+	}
+
+	for _, n := range nodes {
+		if cycle := findCycle(n.ID, byID); cycle != nil {
+			// Report each cycle once, from its smallest member.
+			if n.ID == minOf(cycle) {
+				errs = append(errs, &Error{n.ID, fmt.Sprintf("requirement cycle %v", cycle)})
+			}
+			continue
+		}
+		closure := requiredClosure(n.ID, byID)
+		for _, c := range n.ConflictsWith {
+			if contains(closure, c) {
+				errs = append(errs, &Error{n.ID, fmt.Sprintf("both requires and conflicts with %s", c)})
+			}
+		}
+		for _, m := range closure {
+			for _, c := range byID[m].ConflictsWith {
+				if c == n.ID || contains(closure, c) {
+					errs = append(errs, &Error{n.ID, fmt.Sprintf("requires %s, which conflicts with %s", m, c)})
+				}
+			}
+		}
+	}
+	return errs
+}
+
+// ValidateEnabled reports conflicting features that are enabled together.
+func ValidateEnabled(nodes []Node, enabled func(id string) bool) []error {
+	var errs []error
+	for _, n := range nodes {
+		if !enabled(n.ID) {
+			continue
+		}
+		for _, c := range n.ConflictsWith {
+			if enabled(c) && n.ID < c {
+				errs = append(errs, &Error{n.ID, fmt.Sprintf("enabled together with conflicting feature %s", c)})
+			}
+		}
+	}
+	return errs
+}
+
+// Missing returns the features id requires, directly or transitively, that
+// are not available.
+func Missing(id string, nodes []Node, available func(id string) bool) []string {
+	byID := make(map[string]Node, len(nodes))
+	for _, n := range nodes {
+		byID[n.ID] = n
+	}
+	var missing []string
+	for _, req := range requiredClosure(id, byID) {
+		if !available(req) {
+			missing = append(missing, req)
+		}
+	}
+	return missing
+}
+
+// Build returns the graph of nodes, including any validation errors.
+func Build(nodes []Node) Graph {
+	g := Graph{Nodes: make([]string, 0, len(nodes)), Edges: []Edge{}}
+	for _, n := range nodes {
+		g.Nodes = append(g.Nodes, n.ID)
+		for _, r := range n.Requires {
+			g.Edges = append(g.Edges, Edge{From: n.ID, To: r, Kind: KindRequires})
+		}
+		for _, c := range n.ConflictsWith {
+			g.Edges = append(g.Edges, Edge{From: n.ID, To: c, Kind: KindConflictsWith})
+		}
+	}
+	sort.Strings(g.Nodes)
+	sort.Slice(g.Edges, func(i, j int) bool {
+		a, b := g.Edges[i], g.Edges[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.To < b.To
+	})
+	for _, err := range Validate(nodes) {
+		g.Errors = append(g.Errors, err.Error())
+	}
+	return g
+}
+
+// requiredClosure lists everything id requires, transitively, in
+// breadth-first order. Unknown features and cycles are tolerated.
+func requiredClosure(id string, byID map[string]Node) []string {
+	seen := map[string]bool{id: true}
+	var out []string
+	queue := append([]string(nil), byID[id].Requires...)
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		if seen[cur] {
+			continue
+		}
+		seen[cur] = true
+		out = append(out, cur)
+		queue = append(queue, byID[cur].Requires...)
+	}
+	return out
+}
+
+// findCycle returns the members of a requirement cycle through id, or nil.
+func findCycle(id string, byID map[string]Node) []string {
+	var path []string
+	onPath := map[string]bool{}
+	var visit func(cur string) []string
+	visit = func(cur string) []string {
+		if onPath[cur] {
+			if cur != id {
+				return nil // a cycle not through id; reported from its own members
+			}
+			return append([]string(nil), path...)
+		}
+		if len(path) > len(byID) {
+			return nil
+		}
+		onPath[cur] = true
+		path = append(path, cur)
+		defer func() {
+			onPath[cur] = false
+			path = path[:len(path)-1]
+		}()
+		for _, next := range byID[cur].Requires {
+			if c := visit(next); c != nil {
+				return c
+			}
+		}
+		return nil
+	}
+	return visit(id)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func minOf(list []string) string {
+	m := list[0]
+	for _, v := range list[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
@@ -0,0 +1,46 @@
+package deps
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	valid := []Node{
+		{ID: "basic_reports"},
+		{ID: "custom_dashboard", Requires: []string{"basic_reports"}},
+		{ID: "cloud_export", ConflictsWith: []string{"onprem_export"}},
+		{ID: "onprem_export", ConflictsWith: []string{"cloud_export"}},
+	}
+	if errs := Validate(valid); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	cases := []struct {
+		name  string
+		nodes []Node
+		want  string
+	}{
+		{"unknown", []Node{{ID: "a", Requires: []string{"b"}}}, `requires unknown feature "b"`},
+		{"self", []Node{{ID: "a", ConflictsWith: []string{"a"}}}, "conflicts_with itself"},
+		{"cycle", []Node{{ID: "a", Requires: []string{"b"}}, {ID: "b", Requires: []string{"a"}}}, "requirement cycle"},
+		{"contradiction", []Node{{ID: "a", Requires: []string{"b"}, ConflictsWith: []string{"b"}}, {ID: "b"}}, "both requires and conflicts with b"},
+		{"transitive", []Node{{ID: "a", Requires: []string{"b", "c"}}, {ID: "b", ConflictsWith: []string{"c"}}, {ID: "c"}}, "requires b, which conflicts with c"},
+	}
+	for _, tc := range cases {
+		errs := Validate(tc.nodes)
+		if len(errs) == 0 || !strings.Contains(errs[0].Error(), tc.want) {
+			t.Errorf("%s: errors = %v, want %q", tc.name, errs, tc.want)
+		}
+	}
+
+	enabled := map[string]bool{"cloud_export": true, "onprem_export": true}
+	if errs := ValidateEnabled(valid, func(id string) bool { return enabled[id] }); len(errs) != 1 {
+		t.Fatalf("conflicting enabled features not reported once: %v", errs)
+	}
+
+	chain := []Node{{ID: "a", Requires: []string{"b"}}, {ID: "b", Requires: []string{"c"}}, {ID: "c"}}
+	if got := Missing("a", chain, func(id string) bool { return id == "b" }); len(got) != 1 || got[0] != "c" {
+		t.Fatalf("Missing = %v, want [c]", got)
+	}
+}
@@ -0,0 +1,275 @@
+// Package dispatch routes guarded calls through a manifest at runtime, the
+// way codegen wrappers do at build time: the license decides whether the
+// intercepted function, its fallback or the on_deny action runs.
+package dispatch
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"sort"
+	"sync"
+
+	"demo-app/internal/manifest"
+)
+
+// Outcomes of a guarded call.
+const (
+	OutcomeTarget   = "target"   // allowed; the intercepted function ran
+	OutcomeFallback = "fallback" // denied; the fallback ran
+	OutcomeWarned   = "warned"   // denied with on_deny: warn; the target ran anyway
+	OutcomeDenied   = "denied"   // denied; nothing ran
+)
+
+// Checker decides whether a feature may run.
+type Checker interface {
+	Check(feature manifest.Feature) (allowed bool, reason string, err error)
+}
+
+// CheckerFunc adapts a function to Checker.
+type CheckerFunc func(feature manifest.Feature) (bool, string, error)
+
+// Check calls f.
+func (f CheckerFunc) Check(feature manifest.Feature) (bool, string, error) { return f(feature) }
+
+// DeniedError is returned when a feature is denied and has neither a
+// fallback nor on_deny: warn.
+type DeniedError struct {
+	FeatureID string
+	Reason    string
+	Message   string // on_deny message
+}
+
+func (e *DeniedError) Error() string {
+	if e.Message != "" {
+		return e.Message
+	}
+	return fmt.Sprintf("feature %s denied: %s", e.FeatureID, e.Reason)
+}
+
+// Result describes a guarded call.
+type Result struct {
+	FeatureID string
+	Outcome   string
+	Reason    string // why the feature was denied; empty when allowed
+	Ran       string // package.Function that ran, if any
+	Values    []any  // results of the function that ran, without a trailing error
+}
+
+// Dispatcher holds the manifest features and the registered functions.
+type Dispatcher struct {
+	check Checker
+	warn  func(format string, args ...any)
+
+	mu       sync.RWMutex
+	features map[string]manifest.Feature
+	funcs    map[string]reflect.Value // package.Function -> func
+}
+
+// New returns a dispatcher for the features of m.
+func New(m *manifest.Manifest, check Checker) *Dispatcher {
+	d := &Dispatcher{
+		check:    check,
+		warn:     log.Printf,
+		features: make(map[string]manifest.Feature),
+		funcs:    make(map[string]reflect.Value),
+	}
+	for _, f := range m.Features {
+		d.features[f.ID] = f
+	}
+	return d
+}
+
+// SetWarn replaces the logger used for on_deny: warn (log.Printf by default).
+func (d *Dispatcher) SetWarn(fn func(format string, args ...any)) {
+	d.warn = fn
+}
+
+// Register makes fn callable as pkg.name, e.g.
+// Register("demo-app/internal/analytics", "RunBasic", analytics.RunBasic).
+func (d *Dispatcher) Register(pkg, name string, fn any) error {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func || v.IsNil() {
+		return fmt.Errorf("%s.%s: not a function", pkg, name)
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.funcs[key(manifest.Target{Package: pkg, Function: name})] = v
+	return nil
+}
+
+// MustRegister is like Register but panics on error.
+func (d *Dispatcher) MustRegister(pkg, name string, fn any) {
+	if err := d.Register(pkg, name, fn); err != nil {
+		panic(err)
+	}
+}
+
+// Feature returns the manifest entry of featureID.
+func (d *Dispatcher) Feature(featureID string) (manifest.Feature, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	f, ok := d.features[featureID]
+	return f, ok
+}
+
+// Unregistered lists the intercept and fallback targets in the manifest
+// that have no registered function.
+func (d *Dispatcher) Unregistered() []string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	var out []string
+	seen := map[string]bool{}
+	for _, f := range d.features {
+		for _, t := range []*manifest.Target{f.Intercept, f.Fallback} {
+			if t == nil {
+				continue
+			}
+			if k := key(*t); !seen[k] && !d.funcs[k].IsValid() {
+				seen[k] = true
+				out = append(out, k)
+			}
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Call checks featureID and runs the intercepted function with args if it
+// is allowed. A denied call runs the fallback with the same args, or warns
+// and runs the target (on_deny: warn), or returns a *DeniedError. Checker
+// errors deny the call. An error returned by the function that ran is
+// returned as is.
+func (d *Dispatcher) Call(featureID string, args ...any) (Result, error) {
+	res := Result{FeatureID: featureID}
+	d.mu.RLock()
+	f, ok := d.features[featureID]
+	d.mu.RUnlock()
+	if !ok {
+		return res, fmt.Errorf("feature %s is not in the manifest", featureID)
+	}
+	if f.Intercept == nil {
+		return res, fmt.Errorf("feature %s has no intercept target", featureID)
+	}
+	target, err := d.lookup(*f.Intercept)
+	if err != nil {
+		return res, err
+	}
+
+	allowed, reason, err := d.check.Check(f)
+	if err != nil {
+		allowed, reason = false, err.Error()
+	}
+	if allowed {
+		res.Outcome = OutcomeTarget
+		return d.run(res, *f.Intercept, target, args)
+	}
+	res.Reason = reason
+
+	switch {
+	case f.Fallback != nil:
+		fallback, err := d.lookup(*f.Fallback)
+		if err != nil {
+			return res, err
+		}
+		res.Outcome = OutcomeFallback
+		return d.run(res, *f.Fallback, fallback, args)
+	case f.OnDeny != nil && f.OnDeny.Action == "warn":
+		d.warn("lcc: %s denied (%s), continuing: %s", featureID, reason, f.OnDeny.Message)
+		res.Outcome = OutcomeWarned
+		return d.run(res, *f.Intercept, target, args)
+	}
+	res.Outcome = OutcomeDenied
+	denied := &DeniedError{FeatureID: featureID, Reason: reason}
+	if f.OnDeny != nil {
+		denied.Message = f.OnDeny.Message
+	}
+	return res, denied
+}
+
+func (d *Dispatcher) lookup(t manifest.Target) (reflect.Value, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	fn, ok := d.funcs[key(t)]
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("%s is not registered", key(t))
+	}
+	return fn, nil
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+func (d *Dispatcher) run(res Result, t manifest.Target, fn reflect.Value, args []any) (Result, error) {
+	ft := fn.Type()
+	in, err := callArgs(ft, args)
+	if err != nil {
+		return res, fmt.Errorf("%s: %w", key(t), err)
+	}
+	res.Ran = key(t)
+	var out []reflect.Value
+	if ft.IsVariadic() {
+		out = fn.CallSlice(in)
+	} else {
+		out = fn.Call(in)
+	}
+
+	var callErr error
+	if n := len(out); n > 0 && ft.Out(n-1) == errorType {
+		if e := out[n-1].Interface(); e != nil {
+			callErr = e.(error)
+		}
+		out = out[:n-1]
+	}
+	for _, v := range out {
+		res.Values = append(res.Values, v.Interface())
+	}
+	return res, callErr
+}
+
+// callArgs converts args to the parameters of ft. Variadic arguments are
+// packed into a slice for CallSlice.
+func callArgs(ft reflect.Type, args []any) ([]reflect.Value, error) {
+	fixed := ft.NumIn()
+	if ft.IsVariadic() {
+		fixed--
+	}
+	if len(args) < fixed || (!ft.IsVariadic() && len(args) > fixed) {
+		return nil, fmt.Errorf("called with %d argument(s), want %d", len(args), fixed)
+	}
+	conv := func(a any, t reflect.Type, i int) (reflect.Value, error) {
+		if a == nil {
+			return reflect.Zero(t), nil
+		}
+		v := reflect.ValueOf(a)
+		if !v.Type().AssignableTo(t) {
+			return reflect.Value{}, fmt.Errorf("argument %d is %s, want %s", i, v.Type(), t)
+		}
+		return v, nil
+	}
+
+	in := make([]reflect.Value, 0, ft.NumIn())
+	for i := 0; i < fixed; i++ {
+		v, err := conv(args[i], ft.In(i), i)
+		if err != nil {
+			return nil, err
+		}
+		in = append(in, v)
+	}
+	if ft.IsVariadic() {
+		st := ft.In(fixed)
+		rest := reflect.MakeSlice(st, 0, len(args)-fixed)
+		for i := fixed; i < len(args); i++ {
+			v, err := conv(args[i], st.Elem(), i)
+			if err != nil {
+				return nil, err
+			}
+			rest = reflect.Append(rest, v)
+		}
+		in = append(in, rest)
+	}
+	return in, nil
+}
+
+func key(t manifest.Target) string {
+	return t.Package + "." + t.Function
+}
@@ -0,0 +1,88 @@
+package dispatch
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"demo-app/internal/manifest"
+)
+
+const pkg = "example.com/app/analytics"
+
+func TestCall(t *testing.T) {
+	m := &manifest.Manifest{Features: []manifest.Feature{
+		{ID: "advanced", Intercept: &manifest.Target{Package: pkg, Function: "RunAdvanced"}, Fallback: &manifest.Target{Package: pkg, Function: "RunBasic"}},
+		{ID: "pdf", Intercept: &manifest.Target{Package: pkg, Function: "Export"}, OnDeny: &manifest.OnDeny{Action: "error", Message: "PDF needs Pro"}},
+		{ID: "beta", Intercept: &manifest.Target{Package: pkg, Function: "Export"}, OnDeny: &manifest.OnDeny{Action: "warn"}},
+		{ID: "sum", Intercept: &manifest.Target{Package: pkg, Function: "Sum"}},
+	}}
+	allowed := map[string]bool{}
+	d := New(m, CheckerFunc(func(f manifest.Feature) (bool, string, error) {
+		if allowed[f.ID] {
+			return true, "ok", nil
+		}
+		return false, "insufficient_tier", nil
+	}))
+	var warned string
+	d.SetWarn(func(format string, args ...any) { warned = fmt.Sprintf(format, args...) })
+
+	var ran []string
+	d.MustRegister(pkg, "RunAdvanced", func(n int) string { ran = append(ran, "advanced"); return "advanced" })
+	d.MustRegister(pkg, "RunBasic", func(n int) string { ran = append(ran, "basic"); return "basic" })
+	d.MustRegister(pkg, "Export", func(name string) error {
+		ran = append(ran, "export")
+		if name == "" {
+			return errors.New("no name")
+		}
+		return nil
+	})
+	if got := d.Unregistered(); len(got) != 1 || got[0] != pkg+".Sum" {
+		t.Fatalf("Unregistered = %v", got)
+	}
+	d.MustRegister(pkg, "Sum", func(xs ...int) int {
+		total := 0
+		for _, x := range xs {
+			total += x
+		}
+		return total
+	})
+
+	res, err := d.Call("advanced", 3)
+	if err != nil || res.Outcome != OutcomeFallback || res.Reason != "insufficient_tier" || res.Values[0] != "basic" {
+		t.Errorf("denied advanced = %+v, %v", res, err)
+	}
+	allowed["advanced"] = true
+	if res, err := d.Call("advanced", 3); err != nil || res.Outcome != OutcomeTarget || res.Ran != pkg+".RunAdvanced" {
+		t.Errorf("allowed advanced = %+v, %v", res, err)
+	}
+
+	res, err = d.Call("pdf", "a.pdf")
+	var denied *DeniedError
+	if !errors.As(err, &denied) || denied.Message != "PDF needs Pro" || res.Outcome != OutcomeDenied {
+		t.Errorf("denied pdf = %+v, %v", res, err)
+	}
+
+	if res, err := d.Call("beta", "a.pdf"); err != nil || res.Outcome != OutcomeWarned || warned == "" {
+		t.Errorf("warned beta = %+v, %v (log %q)", res, err, warned)
+	}
+	allowed["beta"] = true
+	if _, err := d.Call("beta", ""); err == nil || err.Error() != "no name" {
+		t.Errorf("target error = %v", err)
+	}
+
+	allowed["sum"] = true
+	if res, err := d.Call("sum", 1, 2, 3); err != nil || res.Values[0] != 6 {
+		t.Errorf("variadic sum = %+v, %v", res, err)
+	}
+
+	if want := []string{"basic", "advanced", "export", "export"}; fmt.Sprint(ran) != fmt.Sprint(want) {
+		t.Errorf("ran %v, want %v", ran, want)
+	}
+	if _, err := d.Call("advanced", "three"); err == nil {
+		t.Error("Call accepted an argument of the wrong type")
+	}
+	if _, err := d.Call("missing"); err == nil {
+		t.Error("Call accepted an unknown feature")
+	}
+}
@@ -2,26 +2,52 @@ package export
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"sync"
 	"time"
 )
 
+var (
+	outMu sync.Mutex
+	out   io.Writer = os.Stdout
+)
+
+// SetOutput sets where the functions print their progress; nil restores stdout.
+func SetOutput(w io.Writer) {
+	outMu.Lock()
+	defer outMu.Unlock()
+	if w == nil {
+		w = os.Stdout
+	}
+	out = w
+}
+
+func output() io.Writer {
+	outMu.Lock()
+	defer outMu.Unlock()
+	return out
+}
+
 func GeneratePDF(filename string) {
-	fmt.Printf("  Generating PDF report: %s...\n", filename)
+	w := output()
+	fmt.Fprintf(w, "  Generating PDF report: %s...\n", filename)
 	time.Sleep(800 * time.Millisecond)
 	
-	fmt.Println("  - Adding header and footer")
-	fmt.Println("  - Rendering charts and graphs")
-	fmt.Println("  - Applying custom styling")
-	fmt.Printf("  - Saved to: %s\n", filename)
+	fmt.Fprintln(w, "  - Adding header and footer")
+	fmt.Fprintln(w, "  - Rendering charts and graphs")
+	fmt.Fprintln(w, "  - Applying custom styling")
+	fmt.Fprintf(w, "  - Saved to: %s\n", filename)
 }
 
 func GenerateExcel(filename string) {
-	fmt.Printf("  Generating Excel report: %s...\n", filename)
+	w := output()
+	fmt.Fprintf(w, "  Generating Excel report: %s...\n", filename)
 	time.Sleep(600 * time.Millisecond)
 	
-	fmt.Println("  - Creating worksheets")
-	fmt.Println("  - Populating data tables")
-	fmt.Println("  - Adding formulas and charts")
-	fmt.Println("  - Formatting cells")
-	fmt.Printf("  - Saved to: %s\n", filename)
+	fmt.Fprintln(w, "  - Creating worksheets")
+	fmt.Fprintln(w, "  - Populating data tables")
+	fmt.Fprintln(w, "  - Adding formulas and charts")
+	fmt.Fprintln(w, "  - Formatting cells")
+	fmt.Fprintf(w, "  - Saved to: %s\n", filename)
 }
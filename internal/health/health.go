@@ -0,0 +1,85 @@
+// Package health serves liveness and readiness probes.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Check is the result of one readiness check.
+type Check struct {
+	Name   string `json:"name"`
+	OK     bool   `json:"ok"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Report is the body of /healthz and /readyz.
+type Report struct {
+	Status string  `json:"status"` // "ok" or "unavailable"
+	Checks []Check `json:"checks,omitempty"`
+}
+
+// Checker runs one readiness check.
+type Checker func(ctx context.Context) Check
+
+// Live handles /healthz: it answers 200 as long as the process serves
+// requests.
+func Live() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		write(w, r, http.StatusOK, Report{Status: "ok"})
+	})
+}
+
+// Ready handles /readyz: it runs checks with timeout and answers 200 when
+// all of them pass, 503 otherwise.
+func Ready(timeout time.Duration, checks ...Checker) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+		rep, code := Report{Status: "ok"}, http.StatusOK
+		for _, check := range checks {
+			c := check(ctx)
+			if !c.OK {
+				rep.Status, code = "unavailable", http.StatusServiceUnavailable
+			}
+			rep.Checks = append(rep.Checks, c)
+		}
+		write(w, r, code, rep)
+	})
+}
+
+// Reachable checks that an HTTP server answers at url. Any response below
+// 500 counts: the server is up even if it does not serve url itself.
+func Reachable(name, url string) Checker {
+	return func(ctx context.Context) Check {
+		c := Check{Name: name}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			c.Detail = err.Error()
+			return c
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			c.Detail = err.Error()
+			return c
+		}
+		resp.Body.Close()
+		c.OK = resp.StatusCode < http.StatusInternalServerError
+		c.Detail = fmt.Sprintf("%s: %s", url, resp.Status)
+		return c
+	}
+}
+
+func write(w http.ResponseWriter, r *http.Request, code int, rep Report) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(rep)
+}
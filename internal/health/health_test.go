@@ -0,0 +1,43 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestReady(t *testing.T) {
+	up := httptest.NewServer(http.NotFoundHandler())
+	defer up.Close()
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer down.Close()
+	static := func(ok bool) Checker {
+		return func(context.Context) Check { return Check{Name: "static", OK: ok} }
+	}
+
+	for _, tc := range []struct {
+		name   string
+		checks []Checker
+		code   int
+	}{
+		{"none", nil, http.StatusOK},
+		{"reachable 404", []Checker{Reachable("lcc", up.URL), static(true)}, http.StatusOK},
+		{"server error", []Checker{Reachable("lcc", down.URL)}, http.StatusServiceUnavailable},
+		{"failed check", []Checker{Reachable("lcc", up.URL), static(false)}, http.StatusServiceUnavailable},
+	} {
+		rec := httptest.NewRecorder()
+		Ready(time.Second, tc.checks...).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		var rep Report
+		if err := json.NewDecoder(rec.Body).Decode(&rep); err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if rec.Code != tc.code || len(rep.Checks) != len(tc.checks) || (rep.Status == "ok") != (tc.code == http.StatusOK) {
+			t.Errorf("%s: %d %+v, want %d", tc.name, rec.Code, rep, tc.code)
+		}
+	}
+}
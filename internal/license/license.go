@@ -0,0 +1,258 @@
+// Package license builds, signs and verifies license documents for the demo tiers.
+package license
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Document is the canonical license payload that gets signed.
+// Field order is fixed and maps are marshalled with sorted keys, so
+// json.Marshal of a Document is already canonical.
+type Document struct {
+	LicenseID   string                 `json:"license_id"`
+	ProductID   string                 `json:"product_id"`
+	ProductName string                 `json:"product_name,omitempty"`
+	Tier        string                 `json:"tier"`
+	Customer    string                 `json:"customer"`
+	Version     string                 `json:"version"`
+	IssuedAt    time.Time              `json:"issued_at"`
+	ExpiresAt   time.Time              `json:"expires_at"`
+	Features    map[string]Feature     `json:"features"`
+	Limits      map[string]interface{} `json:"limits"`
+}
+
+// Feature is the per-feature entitlement carried by a license. Trial, if
+// set, lets a disabled feature be tried out; it is signed with the rest.
+type Feature struct {
+	Enabled bool   `json:"enabled"`
+	Trial   *Trial `json:"trial,omitempty"`
+}
+
+// SignedLicense is the on-disk / on-wire license file.
+type SignedLicense struct {
+	License   Document `json:"license"`
+	Algorithm string   `json:"algorithm"`
+	KeyID     string   `json:"key_id,omitempty"`
+	Signature string   `json:"signature"`
+}
+
+// Verification failure reasons.
+const (
+	ReasonMalformed        = "malformed"
+	ReasonInvalidSignature = "invalid_signature"
+	ReasonExpired          = "expired"
+	ReasonNotYetValid      = "not_yet_valid"
+	ReasonProductMismatch  = "product_mismatch"
+)
+
+// VerifyError explains why a license was rejected.
+type VerifyError struct {
+	Reason string
+	Detail string
+}
+
+func (e *VerifyError) Error() string {
+	if e.Detail == "" {
+		return e.Reason
+	}
+	return e.Reason + ": " + e.Detail
+}
+
+// ReasonOf returns the VerifyError reason of err, or "" if err is not one.
+func ReasonOf(err error) string {
+	var ve *VerifyError
+	if errors.As(err, &ve) {
+		return ve.Reason
+	}
+	return ""
+}
+
+// NewLicenseID returns a random identifier for a freshly issued license.
+func NewLicenseID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("lic-%d", time.Now().UnixNano())
+	}
+	return "lic-" + hex.EncodeToString(b)
+}
+
+// Canonical returns the exact bytes that are signed for doc.
+func Canonical(doc Document) ([]byte, error) {
+	return json.Marshal(doc)
+}
+
+// Sign signs doc with signer and returns the license file contents.
+func Sign(doc Document, signer crypto.Signer, keyID string) (*SignedLicense, error) {
+	payload, err := Canonical(doc)
+	if err != nil {
+		return nil, fmt.Errorf("canonicalize: %w", err)
+	}
+
+	var alg string
+	var sig []byte
+	switch signer.Public().(type) {
+	case *rsa.PublicKey:
+		alg = "RS256"
+		sum := sha256.Sum256(payload)
+		sig, err = signer.Sign(rand.Reader, sum[:], crypto.SHA256)
+	case *ecdsa.PublicKey:
+		alg = "ES256"
+		sum := sha256.Sum256(payload)
+		sig, err = signer.Sign(rand.Reader, sum[:], crypto.SHA256)
+	case ed25519.PublicKey:
+		alg = "EdDSA"
+		sig, err = signer.Sign(rand.Reader, payload, crypto.Hash(0))
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", signer.Public())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("sign: %w", err)
+	}
+
+	return &SignedLicense{
+		License:   doc,
+		Algorithm: alg,
+		KeyID:     keyID,
+		Signature: base64.StdEncoding.EncodeToString(sig),
+	}, nil
+}
+
+// VerifyOptions controls the checks applied by Verify.
+type VerifyOptions struct {
+	// ProductID, when set, must match the license product.
+	ProductID string
+	// Now is the reference time for expiry checks; zero means time.Now().
+	Now time.Time
+}
+
+// Verify checks the signature, validity window and product of sl.
+// Failures are returned as *VerifyError.
+func Verify(sl *SignedLicense, pub crypto.PublicKey, opts VerifyOptions) error {
+	if sl == nil || sl.Signature == "" {
+		return &VerifyError{Reason: ReasonMalformed, Detail: "missing signature"}
+	}
+	sig, err := base64.StdEncoding.DecodeString(sl.Signature)
+	if err != nil {
+		return &VerifyError{Reason: ReasonMalformed, Detail: "signature is not base64"}
+	}
+	payload, err := Canonical(sl.License)
+	if err != nil {
+		return &VerifyError{Reason: ReasonMalformed, Detail: err.Error()}
+	}
+
+	if err := verifySignature(pub, sl.Algorithm, payload, sig); err != nil {
+		return err
+	}
+
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	doc := sl.License
+	if !doc.IssuedAt.IsZero() && now.Before(doc.IssuedAt) {
+		return &VerifyError{Reason: ReasonNotYetValid, Detail: "issued at " + doc.IssuedAt.Format(time.RFC3339)}
+	}
+	if !doc.ExpiresAt.IsZero() && !now.Before(doc.ExpiresAt) {
+		return &VerifyError{Reason: ReasonExpired, Detail: "expired at " + doc.ExpiresAt.Format(time.RFC3339)}
+	}
+	if opts.ProductID != "" && doc.ProductID != opts.ProductID {
+		return &VerifyError{Reason: ReasonProductMismatch, Detail: fmt.Sprintf("license is for %q, expected %q", doc.ProductID, opts.ProductID)}
+	}
+	return nil
+}
+
+func verifySignature(pub crypto.PublicKey, alg string, payload, sig []byte) error {
+	bad := &VerifyError{Reason: ReasonInvalidSignature}
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		if alg != "RS256" {
+			return &VerifyError{Reason: ReasonInvalidSignature, Detail: "algorithm mismatch"}
+		}
+		sum := sha256.Sum256(payload)
+		if rsa.VerifyPKCS1v15(k, crypto.SHA256, sum[:], sig) != nil {
+			return bad
+		}
+	case *ecdsa.PublicKey:
+		if alg != "ES256" {
+			return &VerifyError{Reason: ReasonInvalidSignature, Detail: "algorithm mismatch"}
+		}
+		sum := sha256.Sum256(payload)
+		if !ecdsa.VerifyASN1(k, sum[:], sig) {
+			return bad
+		}
+	case ed25519.PublicKey:
+		if alg != "EdDSA" {
+			return &VerifyError{Reason: ReasonInvalidSignature, Detail: "algorithm mismatch"}
+		}
+		if !ed25519.Verify(k, payload, sig) {
+			return bad
+		}
+	default:
+		return fmt.Errorf("unsupported public key type %T", pub)
+	}
+	return nil
+}
+
+// Parse decodes a license file.
+func Parse(data []byte) (*SignedLicense, error) {
+	var sl SignedLicense
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&sl); err != nil {
+		return nil, &VerifyError{Reason: ReasonMalformed, Detail: err.Error()}
+	}
+	return &sl, nil
+}
+
+// ParsePrivateKeyPEM parses a PKCS#1, PKCS#8 or SEC1 private key.
+func ParsePrivateKeyPEM(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found")
+	}
+	if k, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return k, nil
+	}
+	if k, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return k, nil
+	}
+	k, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse private key: %w", err)
+	}
+	signer, ok := k.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", k)
+	}
+	return signer, nil
+}
+
+// ParsePublicKeyPEM parses a PKIX or PKCS#1 public key.
+func ParsePublicKeyPEM(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found")
+	}
+	if k, err := x509.ParsePKIXPublicKey(block.Bytes); err == nil {
+		return k, nil
+	}
+	k, err := x509.ParsePKCS1PublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse public key: %w", err)
+	}
+	return k, nil
+}
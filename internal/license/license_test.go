@@ -0,0 +1,172 @@
+package license
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func testDocument(now time.Time) Document {
+	return Document{
+		LicenseID: "lic-test",
+		ProductID: "data-insight-pro",
+		Tier:      "professional",
+		Customer:  "acme",
+		Version:   "1.0.0",
+		IssuedAt:  now.Add(-time.Hour),
+		ExpiresAt: now.Add(24 * time.Hour),
+		Features: map[string]Feature{
+			"pdf_export":   {Enabled: true},
+			"excel_export": {Enabled: false},
+		},
+		Limits: map[string]interface{}{
+			"max_tps": 100.0,
+			"quota":   map[string]interface{}{"max": 50000, "window": "monthly"},
+		},
+	}
+}
+
+func TestSignVerifyRoundTrip(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	sl, err := Sign(testDocument(now), key, "issuer")
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+
+	// Round-trip through the file format to make sure canonicalization is stable.
+	data, err := json.MarshalIndent(sl, "", "  ")
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	parsed, err := Parse(data)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	if err := Verify(parsed, &key.PublicKey, VerifyOptions{ProductID: "data-insight-pro", Now: now}); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+}
+
+func TestVerifyRejections(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	otherPub, _, _ := ed25519.GenerateKey(rand.Reader)
+
+	sign := func(doc Document) *SignedLicense {
+		sl, err := Sign(doc, priv, "issuer")
+		if err != nil {
+			t.Fatalf("sign: %v", err)
+		}
+		return sl
+	}
+
+	tampered := sign(testDocument(now))
+	tampered.License.Features["excel_export"] = Feature{Enabled: true}
+
+	expiredDoc := testDocument(now)
+	expiredDoc.ExpiresAt = now.Add(-time.Minute)
+
+	futureDoc := testDocument(now)
+	futureDoc.IssuedAt = now.Add(time.Hour)
+
+	cases := []struct {
+		name   string
+		sl     *SignedLicense
+		key    ed25519.PublicKey
+		opts   VerifyOptions
+		reason string
+	}{
+		{"tampered", tampered, pub, VerifyOptions{Now: now}, ReasonInvalidSignature},
+		{"wrong key", sign(testDocument(now)), otherPub, VerifyOptions{Now: now}, ReasonInvalidSignature},
+		{"expired", sign(expiredDoc), pub, VerifyOptions{Now: now}, ReasonExpired},
+		{"not yet valid", sign(futureDoc), pub, VerifyOptions{Now: now}, ReasonNotYetValid},
+		{"product mismatch", sign(testDocument(now)), pub, VerifyOptions{Now: now, ProductID: "data-insight-basic"}, ReasonProductMismatch},
+		{"missing signature", &SignedLicense{License: testDocument(now)}, pub, VerifyOptions{Now: now}, ReasonMalformed},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Verify(tc.sl, tc.key, tc.opts)
+			if got := ReasonOf(err); got != tc.reason {
+				t.Fatalf("reason = %q (err=%v), want %q", got, err, tc.reason)
+			}
+		})
+	}
+}
+
+func TestLifecycleStates(t *testing.T) {
+	expires := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	cases := []struct {
+		now  time.Time
+		want State
+	}{
+		{expires.Add(-60 * day), StateActive},
+		{expires.Add(-10 * day), StateExpiringSoon},
+		{expires.Add(3 * day), StateGrace},
+		{expires.Add(15 * day), StateExpired},
+	}
+	for _, tc := range cases {
+		lc := DefaultPolicy.Evaluate(expires, tc.now)
+		if lc.State != tc.want {
+			t.Errorf("at %s: state = %s, want %s", tc.now.Format("2006-01-02"), lc.State, tc.want)
+		}
+		if lc.State != StateActive && lc.Warning == "" {
+			t.Errorf("at %s: state %s carries no warning", tc.now.Format("2006-01-02"), lc.State)
+		}
+	}
+
+	if got := Renew(expires, expires.Add(-10*day), 365*day); !got.Equal(expires.Add(365 * day)) {
+		t.Errorf("early renewal = %s, want extension from expiry", got)
+	}
+	late := expires.Add(20 * day)
+	if got := Renew(expires, late, 365*day); !got.Equal(late.Add(365 * day)) {
+		t.Errorf("late renewal = %s, want extension from now", got)
+	}
+}
+
+func TestTrialEvaluate(t *testing.T) {
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	trial := Trial{DurationDays: 14, UsageCap: 3}
+
+	cases := []struct {
+		name   string
+		now    time.Time
+		used   int
+		active bool
+		reason string
+		days   int
+	}{
+		{"first day", start.Add(time.Hour), 0, true, ReasonTrial, 14},
+		{"last day", start.Add(13*day + time.Hour), 1, true, ReasonTrial, 1},
+		{"ended", start.Add(14 * day), 1, false, ReasonTrialExpired, 0},
+		{"cap reached", start.Add(2 * day), 3, false, ReasonTrialUsageExceeded, 12},
+		{"before start", start.Add(-time.Hour), 0, false, ReasonTrialNotStarted, 15},
+	}
+	for _, tc := range cases {
+		st := trial.Evaluate(start, tc.used, tc.now)
+		if st.Active != tc.active || st.Reason != tc.reason || st.DaysRemaining != tc.days {
+			t.Errorf("%s: got active=%v reason=%s days=%d, want %v %s %d",
+				tc.name, st.Active, st.Reason, st.DaysRemaining, tc.active, tc.reason, tc.days)
+		}
+	}
+
+	fixed := Trial{Start: start, DurationDays: 7}
+	if st := fixed.Evaluate(start.Add(30*day), 0, start.Add(day)); !st.Active || !st.StartedAt.Equal(start) {
+		t.Errorf("fixed start not honored: %+v", st)
+	}
+}
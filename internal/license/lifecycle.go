@@ -0,0 +1,83 @@
+package license
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// State is the lifetime state of a license at a point in time.
+type State string
+
+const (
+	// StateActive: valid and not close to expiry.
+	StateActive State = "active"
+	// StateExpiringSoon: valid, but within the renewal warning window.
+	StateExpiringSoon State = "expiring_soon"
+	// StateGrace: past expiry; features keep working but with a warning.
+	StateGrace State = "grace_period"
+	// StateExpired: past the grace period; features are denied.
+	StateExpired State = "expired"
+)
+
+// ReasonLicenseExpired is the denial reason once the grace period is over.
+const ReasonLicenseExpired = "license_expired"
+
+// Policy defines the renewal warning window and grace period.
+type Policy struct {
+	WarningWindow time.Duration
+	GracePeriod   time.Duration
+}
+
+// DefaultPolicy warns 30 days ahead and allows a 14 day grace period.
+var DefaultPolicy = Policy{
+	WarningWindow: 30 * 24 * time.Hour,
+	GracePeriod:   14 * 24 * time.Hour,
+}
+
+// Lifecycle describes where a license is in its lifetime.
+type Lifecycle struct {
+	State         State     `json:"state"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	GraceEndsAt   time.Time `json:"grace_ends_at"`
+	DaysRemaining int       `json:"days_remaining"`
+	Warning       string    `json:"warning,omitempty"`
+}
+
+// Allowed reports whether features may still be used in this state.
+func (l Lifecycle) Allowed() bool { return l.State != StateExpired }
+
+// Evaluate returns the lifecycle of a license expiring at expiresAt as seen at now.
+func (p Policy) Evaluate(expiresAt, now time.Time) Lifecycle {
+	graceEnds := expiresAt.Add(p.GracePeriod)
+	lc := Lifecycle{
+		ExpiresAt:     expiresAt,
+		GraceEndsAt:   graceEnds,
+		DaysRemaining: int(math.Ceil(expiresAt.Sub(now).Hours() / 24)),
+	}
+
+	switch {
+	case now.Before(expiresAt.Add(-p.WarningWindow)):
+		lc.State = StateActive
+	case now.Before(expiresAt):
+		lc.State = StateExpiringSoon
+		lc.Warning = fmt.Sprintf("license expires in %d day(s); please renew", lc.DaysRemaining)
+	case now.Before(graceEnds):
+		lc.State = StateGrace
+		graceDays := int(math.Ceil(graceEnds.Sub(now).Hours() / 24))
+		lc.Warning = fmt.Sprintf("license expired on %s; grace period ends in %d day(s)", expiresAt.Format("2006-01-02"), graceDays)
+	default:
+		lc.State = StateExpired
+		lc.Warning = fmt.Sprintf("license expired on %s; features are disabled until renewed", expiresAt.Format("2006-01-02"))
+	}
+	return lc
+}
+
+// Renew extends a license by term. Renewing early extends from the current
+// expiry; renewing after expiry starts the new term at now.
+func Renew(expiresAt, now time.Time, term time.Duration) time.Time {
+	if expiresAt.After(now) {
+		return expiresAt.Add(term)
+	}
+	return now.Add(term)
+}
@@ -0,0 +1,66 @@
+package license
+
+import (
+	"math"
+	"time"
+)
+
+// Trial denial reasons.
+const (
+	ReasonTrialNotStarted    = "trial_not_started"
+	ReasonTrialExpired       = "trial_expired"
+	ReasonTrialUsageExceeded = "trial_usage_exceeded"
+)
+
+// ReasonTrial is reported for features enabled only through an active trial.
+const ReasonTrial = "trial"
+
+// Trial is a time-boxed entitlement to an otherwise disabled feature.
+// A zero Start means the trial starts on first use.
+type Trial struct {
+	Start        time.Time `json:"start,omitempty"`
+	DurationDays int       `json:"duration_days"`
+	UsageCap     int       `json:"usage_cap,omitempty"` // 0 = unlimited uses during the trial
+}
+
+// TrialStatus is the state of a trial at a point in time.
+type TrialStatus struct {
+	Active        bool      `json:"active"`
+	Reason        string    `json:"reason"`
+	StartedAt     time.Time `json:"started_at"`
+	EndsAt        time.Time `json:"ends_at"`
+	DaysRemaining int       `json:"days_remaining"`
+	Used          int       `json:"used"`
+	UsageCap      int       `json:"usage_cap,omitempty"`
+	UsesRemaining int       `json:"uses_remaining,omitempty"`
+}
+
+// Evaluate returns the trial status at now for a trial started at start with
+// used uses so far. start is ignored when the trial has a fixed Start.
+func (t Trial) Evaluate(start time.Time, used int, now time.Time) TrialStatus {
+	if !t.Start.IsZero() {
+		start = t.Start
+	}
+	ends := start.Add(time.Duration(t.DurationDays) * 24 * time.Hour)
+	st := TrialStatus{StartedAt: start, EndsAt: ends, Used: used, UsageCap: t.UsageCap}
+
+	if remaining := ends.Sub(now); remaining > 0 {
+		st.DaysRemaining = int(math.Ceil(remaining.Hours() / 24))
+	}
+	if t.UsageCap > 0 && used < t.UsageCap {
+		st.UsesRemaining = t.UsageCap - used
+	}
+
+	switch {
+	case now.Before(start):
+		st.Reason = ReasonTrialNotStarted
+	case !now.Before(ends):
+		st.Reason = ReasonTrialExpired
+	case t.UsageCap > 0 && used >= t.UsageCap:
+		st.Reason = ReasonTrialUsageExceeded
+	default:
+		st.Active = true
+		st.Reason = ReasonTrial
+	}
+	return st
+}
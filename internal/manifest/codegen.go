@@ -0,0 +1,365 @@
+package manifest
+
+import (
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Product-level limit checks a generated wrapper can inject. A feature with
+// a quota always gets the quota check.
+const (
+	LimitQuota       = "quota"
+	LimitTPS         = "tps"
+	LimitCapacity    = "capacity"
+	LimitConcurrency = "concurrency"
+)
+
+// GeneratedSuffix is appended to the intercepted function's name to name
+// its wrapper.
+const GeneratedSuffix = "__generated"
+
+// Signature is the Go signature of an intercepted function.
+type Signature struct {
+	Recv    string   `json:"recv,omitempty"` // receiver type of a method, e.g. "*Scheduler"
+	Params  []Param  `json:"params"`
+	Results []string `json:"results"`
+}
+
+// Param is one parameter; a variadic parameter's type starts with "...".
+type Param struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// GenOptions adds product-level limit checks to a wrapper. The helpers name
+// developer functions in the intercepted package.
+type GenOptions struct {
+	Limits          []string
+	QuotaConsumer   string // func(context.Context, ...any) int; the amount is 1 without it
+	TPSProvider     string // func() float64; the SDK measures TPS without it
+	CapacityCounter string // func() int; required for the capacity check
+}
+
+// LookupSignature finds the intercept target t in mod with go/parser.
+// Methods are named Type.Method, as in the linter.
+func LookupSignature(mod *Module, t Target) (Signature, error) {
+	dir, err := mod.PackageDir(t.Package)
+	if err != nil {
+		return Signature{}, err
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "*.go"))
+	sort.Strings(files)
+
+	fset := token.NewFileSet()
+	for _, p := range files {
+		if strings.HasSuffix(p, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, p, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return Signature{}, err
+		}
+		for _, decl := range f.Decls {
+			fd, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+			name, recv := fd.Name.Name, ""
+			if fd.Recv != nil && len(fd.Recv.List) > 0 {
+				name = receiverType(fd.Recv.List[0].Type) + "." + name
+				recv = types.ExprString(fd.Recv.List[0].Type)
+			}
+			if name == t.Function {
+				return signatureOf(recv, fd.Type), nil
+			}
+		}
+	}
+	return Signature{}, fmt.Errorf("function %s not found in package %s", t.Function, t.Package)
+}
+
+func signatureOf(recv string, ft *ast.FuncType) Signature {
+	sig := Signature{Recv: recv, Params: []Param{}, Results: []string{}}
+	for _, field := range ft.Params.List {
+		typ := types.ExprString(field.Type)
+		if len(field.Names) == 0 {
+			sig.Params = append(sig.Params, Param{Type: typ})
+		}
+		for _, n := range field.Names {
+			sig.Params = append(sig.Params, Param{Name: n.Name, Type: typ})
+		}
+	}
+	if ft.Results != nil {
+		for _, field := range ft.Results.List {
+			for range max(len(field.Names), 1) {
+				sig.Results = append(sig.Results, types.ExprString(field.Type))
+			}
+		}
+	}
+	return sig
+}
+
+// Generate renders the wrapper the zero-intrusion codegen emits for f: the
+// feature gate (skipped when f has no ID), the quota and any opts.Limits
+// checks, then the original call. A denied call runs the fallback, warns
+// and continues (on_deny: warn), or fails (on_deny: error, the default).
+// The result is gofmt'd and checked with go/parser.
+func Generate(f Feature, sig Signature, opts GenOptions) ([]byte, error) {
+	if f.Intercept == nil || f.Intercept.Package == "" || f.Intercept.Function == "" {
+		return nil, fmt.Errorf("feature %s has no intercept target", f.ID)
+	}
+	if oneOf(LimitCapacity, opts.Limits) && opts.CapacityCounter == "" {
+		return nil, fmt.Errorf("the capacity check needs a CapacityCounter")
+	}
+
+	g := &wrapperGen{f: f, sig: sig, imports: map[string]bool{}}
+	g.sig.Params = append([]Param(nil), sig.Params...)
+	g.pkg = path.Base(f.Intercept.Package)
+	g.fn = f.Intercept.Function
+	if i := strings.LastIndex(g.fn, "."); i >= 0 {
+		g.fn = g.fn[i+1:]
+	}
+	g.args = make([]string, len(sig.Params))
+	for i, p := range sig.Params {
+		name := p.Name
+		if name == "" || name == "_" {
+			name = fmt.Sprintf("p%d", i)
+		}
+		g.sig.Params[i].Name = name
+		g.args[i] = name
+		if strings.HasPrefix(p.Type, "...") {
+			g.args[i] += "..."
+		}
+	}
+	body := g.body(opts)
+
+	var b strings.Builder
+	b.WriteString("// Code generated by lcc-codegen from lcc-features.yaml. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", g.pkg)
+	if len(g.imports) == 1 {
+		for p := range g.imports {
+			fmt.Fprintf(&b, "import %q\n\n", p)
+		}
+	} else if len(g.imports) > 1 {
+		paths := make([]string, 0, len(g.imports))
+		for p := range g.imports {
+			paths = append(paths, p)
+		}
+		sort.Strings(paths)
+		b.WriteString("import (\n")
+		for _, p := range paths {
+			fmt.Fprintf(&b, "\t%q\n", p)
+		}
+		b.WriteString(")\n\n")
+	}
+	b.WriteString(g.doc())
+	b.WriteString("func ")
+	if sig.Recv != "" {
+		fmt.Fprintf(&b, "(r %s) ", sig.Recv)
+	}
+	fmt.Fprintf(&b, "%s%s(%s)%s {\n", g.fn, GeneratedSuffix, g.params(), g.results())
+	b.WriteString(body)
+	b.WriteString("}\n")
+
+	src := []byte(b.String())
+	if _, err := parser.ParseFile(token.NewFileSet(), "lcc_gen.go", src, parser.ParseComments); err != nil {
+		return nil, fmt.Errorf("generated code does not parse: %w", err)
+	}
+	return format.Source(src)
+}
+
+type wrapperGen struct {
+	f       Feature
+	sig     Signature
+	pkg     string
+	fn      string
+	args    []string
+	imports map[string]bool
+}
+
+func (g *wrapperGen) doc() string {
+	var facts []string
+	if g.f.ID != "" {
+		facts = append(facts, fmt.Sprintf("feature %q", g.f.ID))
+		if g.f.Tier != "" {
+			facts = append(facts, "tier "+g.f.Tier)
+		}
+	}
+	if q := g.f.Quota; q != nil {
+		facts = append(facts, fmt.Sprintf("quota %d/%s", q.Limit, q.Period))
+	}
+	doc := fmt.Sprintf("// %s%s replaces calls to %s", g.fn, GeneratedSuffix, g.fn)
+	if len(facts) > 0 {
+		doc += " (" + strings.Join(facts, ", ") + ")"
+	}
+	return doc + ".\n"
+}
+
+func (g *wrapperGen) params() string {
+	parts := make([]string, len(g.sig.Params))
+	for i, p := range g.sig.Params {
+		parts[i] = p.Name + " " + p.Type
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (g *wrapperGen) results() string {
+	switch len(g.sig.Results) {
+	case 0:
+		return ""
+	case 1:
+		return " " + g.sig.Results[0]
+	}
+	return " (" + strings.Join(g.sig.Results, ", ") + ")"
+}
+
+func (g *wrapperGen) returnsError() bool {
+	n := len(g.sig.Results)
+	return n > 0 && g.sig.Results[n-1] == "error"
+}
+
+// call renders a call of fn (intercept or fallback) with the wrapper's args.
+func (g *wrapperGen) call(t Target) string {
+	name := t.Function
+	switch {
+	case g.sig.Recv != "" && strings.Contains(name, "."):
+		name = "r." + name[strings.LastIndex(name, ".")+1:]
+	case t.Package != g.f.Intercept.Package:
+		alias := path.Base(t.Package)
+		g.imports[t.Package] = true
+		name = alias + "." + name
+	}
+	return name + "(" + strings.Join(g.args, ", ") + ")"
+}
+
+// tail returns from the wrapper with the results of call.
+func (g *wrapperGen) tail(call string) string {
+	if len(g.sig.Results) == 0 {
+		return "\t\t" + call + "\n\t\treturn\n"
+	}
+	return "\t\treturn " + call + "\n"
+}
+
+// deny renders the statements run when a check fails; warn reports whether
+// execution continues to the original call. msg overrides the error message.
+func (g *wrapperGen) deny(what, msg string) (stmts string, warn bool) {
+	if fb := g.f.Fallback; fb != nil && fb.Function != "" {
+		return fmt.Sprintf("\t\t// %s: degrade to the fallback\n", what) + g.tail(g.call(*fb)), false
+	}
+	if msg == "" {
+		msg = what
+		if g.f.ID != "" {
+			msg = g.f.ID + ": " + what
+		}
+	}
+	if d := g.f.OnDeny; d != nil && d.Action == "warn" {
+		g.imports["log"] = true
+		return fmt.Sprintf("\t\tlog.Printf(\"lcc: %%s\", %q) // on_deny: warn\n", msg), true
+	}
+
+	zeros := make([]string, 0, len(g.sig.Results))
+	for _, t := range g.sig.Results {
+		zeros = append(zeros, zeroValue(t))
+	}
+	if g.returnsError() {
+		g.imports["errors"] = true
+		zeros[len(zeros)-1] = fmt.Sprintf("errors.New(%q)", msg)
+		return "\t\treturn " + strings.Join(zeros, ", ") + "\n", false
+	}
+	g.imports["log"] = true
+	s := fmt.Sprintf("\t\tlog.Printf(\"lcc: %%s\", %q) // %s has no error result\n", msg, g.fn)
+	if len(zeros) == 0 {
+		return s + "\t\treturn\n", false
+	}
+	return s + "\t\treturn " + strings.Join(zeros, ", ") + "\n", false
+}
+
+// guard renders "if <cond> { <deny> }".
+func (g *wrapperGen) guard(b *strings.Builder, init, cond, what, msg string) {
+	stmts, _ := g.deny(what, msg)
+	fmt.Fprintf(b, "\tif %s; %s {\n%s\t}\n", init, cond, stmts)
+}
+
+func (g *wrapperGen) body(opts GenOptions) string {
+	var b strings.Builder
+	if g.f.ID != "" {
+		b.WriteString("\t// Auto-injected feature gate\n")
+		msg := ""
+		if g.f.OnDeny != nil {
+			msg = g.f.OnDeny.Message
+		}
+		g.guard(&b, fmt.Sprintf("status, err := __lcc.CheckFeature(%q)", g.f.ID), "err != nil || !status.Enabled", "not licensed", msg)
+	}
+	if oneOf(LimitConcurrency, opts.Limits) {
+		b.WriteString("\t// Auto-injected concurrency control\n")
+		stmts, warn := g.deny("concurrency limit reached", "")
+		fmt.Fprintf(&b, "\trelease, allowed, err := __lcc.AcquireSlot()\n\tif err != nil || !allowed {\n%s", stmts)
+		if warn {
+			// Without a slot there is nothing to release.
+			b.WriteString("\t} else {\n\t\tdefer release()\n\t}\n")
+		} else {
+			b.WriteString("\t}\n\tdefer release()\n")
+		}
+	}
+	if g.f.Quota != nil || oneOf(LimitQuota, opts.Limits) {
+		b.WriteString("\t// Auto-injected quota consumption\n")
+		amount := "1"
+		if opts.QuotaConsumer != "" {
+			g.imports["context"] = true
+			amount = fmt.Sprintf("%s(context.Background()%s)", opts.QuotaConsumer, prefixed(", ", g.args))
+		}
+		g.guard(&b, fmt.Sprintf("allowed, _, err := __lcc.Consume(%s)", amount), "err != nil || !allowed", "quota exceeded", "")
+	}
+	if oneOf(LimitTPS, opts.Limits) {
+		b.WriteString("\t// Auto-injected TPS check\n")
+		tps := "" // measured by the SDK
+		if opts.TPSProvider != "" {
+			tps = opts.TPSProvider + "()"
+		}
+		g.guard(&b, fmt.Sprintf("allowed, _, err := __lcc.CheckTPS(%s)", tps), "err != nil || !allowed", "rate limit exceeded", "")
+	}
+	if oneOf(LimitCapacity, opts.Limits) {
+		b.WriteString("\t// Auto-injected capacity check\n")
+		g.guard(&b, fmt.Sprintf("allowed, _, err := __lcc.CheckCapacity(%s())", opts.CapacityCounter), "err != nil || !allowed", "capacity exceeded", "")
+	}
+
+	b.WriteString("\n\t// Original business logic\n")
+	if len(g.sig.Results) == 0 {
+		b.WriteString("\t" + g.call(*g.f.Intercept) + "\n")
+	} else {
+		b.WriteString("\treturn " + g.call(*g.f.Intercept) + "\n")
+	}
+	return b.String()
+}
+
+func prefixed(sep string, args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	return sep + strings.Join(args, ", ")
+}
+
+// zeroValue returns a Go expression for the zero value of type t.
+func zeroValue(t string) string {
+	switch {
+	case t == "string":
+		return `""`
+	case t == "bool":
+		return "false"
+	case t == "error" || t == "any" || strings.HasPrefix(t, "*") || strings.HasPrefix(t, "[]") ||
+		strings.HasPrefix(t, "map[") || strings.HasPrefix(t, "chan ") || strings.HasPrefix(t, "func(") ||
+		strings.HasPrefix(t, "interface{"):
+		return "nil"
+	case strings.HasPrefix(t, "int") || strings.HasPrefix(t, "uint") || strings.HasPrefix(t, "float") ||
+		strings.HasPrefix(t, "complex") || t == "byte" || t == "rune":
+		return "0"
+	}
+	return "*new(" + t + ")"
+}
@@ -0,0 +1,84 @@
+package manifest
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"go.mod":                   "module example.com/app\n\ngo 1.24\n",
+		"internal/export/pdf.go":   "package export\n\nfunc GeneratePDF(name string, pages ...int) ([]byte, error) { return nil, nil }\n",
+		"internal/export/basic.go": "package export\n\nfunc GenerateText(name string, pages ...int) ([]byte, error) { return nil, nil }\n",
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mod, err := FindModule(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := Target{Package: "example.com/app/internal/export", Function: "GeneratePDF"}
+	sig, err := LookupSignature(mod, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sig.Params) != 2 || sig.Params[1].Type != "...int" || strings.Join(sig.Results, ",") != "[]byte,error" {
+		t.Fatalf("signature = %+v", sig)
+	}
+	if _, err := LookupSignature(mod, Target{Package: target.Package, Function: "Missing"}); err == nil {
+		t.Error("LookupSignature found a missing function")
+	}
+
+	tests := []struct {
+		name string
+		f    Feature
+		want []string
+	}{
+		{"on_deny error", Feature{ID: "pdf_export", OnDeny: &OnDeny{Action: "error", Message: "PDF needs Pro"}, Quota: &Quota{Limit: 5, Period: "daily"}}, []string{
+			`__lcc.CheckFeature("pdf_export")`,
+			`return nil, errors.New("PDF needs Pro")`,
+			`__lcc.Consume(1)`,
+			`return nil, errors.New("pdf_export: quota exceeded")`,
+			"return GeneratePDF(name, pages...)",
+		}},
+		{"fallback", Feature{ID: "pdf_export", Fallback: &Target{Package: target.Package, Function: "GenerateText"}}, []string{
+			"return GenerateText(name, pages...)",
+		}},
+		{"warn", Feature{ID: "pdf_export", OnDeny: &OnDeny{Action: "warn", Message: "unlicensed"}}, []string{
+			`log.Printf("lcc: %s", "unlicensed")`,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.f.Intercept = &target
+			code, err := Generate(tt.f, sig, GenOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := parser.ParseFile(token.NewFileSet(), "lcc_gen.go", code, 0); err != nil {
+				t.Fatalf("does not parse: %v\n%s", err, code)
+			}
+			src := string(code)
+			for _, w := range append(tt.want, "package export", "func GeneratePDF__generated(name string, pages ...int) ([]byte, error)") {
+				if !strings.Contains(src, w) {
+					t.Errorf("missing %q in\n%s", w, src)
+				}
+			}
+		})
+	}
+
+	if _, err := Generate(Feature{ID: "x"}, sig, GenOptions{}); err == nil {
+		t.Error("Generate accepted a feature without intercept")
+	}
+}
@@ -0,0 +1,367 @@
+package manifest
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"demo-app/internal/deps"
+)
+
+// Tiers are the tier values a feature may declare.
+var Tiers = []string{"basic", "professional", "enterprise"}
+
+// QuotaPeriods are the quota periods the evaluators can roll.
+var QuotaPeriods = []string{"daily", "monthly"}
+
+// OnDenyActions are the supported on_deny actions.
+var OnDenyActions = []string{"error", "warn"}
+
+// Issue severities.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Issue is one lint finding, positioned in the manifest file.
+type Issue struct {
+	File      string `json:"file"`
+	Line      int    `json:"line"`
+	Column    int    `json:"column"`
+	Severity  string `json:"severity"`
+	Rule      string `json:"rule"`
+	FeatureID string `json:"feature_id,omitempty"`
+	Message   string `json:"message"`
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s: %s (%s)", i.File, i.Line, i.Column, i.Severity, i.Message, i.Rule)
+}
+
+// HasErrors reports whether any issue is an error rather than a warning.
+func HasErrors(issues []Issue) bool {
+	for _, i := range issues {
+		if i.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Module is a Go module on disk whose packages intercept targets name.
+type Module struct {
+	Path string // module path from go.mod
+	Dir  string // directory holding go.mod
+}
+
+// FindModule looks for go.mod in dir and its parents.
+func FindModule(dir string) (*Module, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if path, err := modulePath(filepath.Join(dir, "go.mod")); err == nil {
+			return &Module{Path: path, Dir: dir}, nil
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, fmt.Errorf("no go.mod found")
+		}
+		dir = parent
+	}
+}
+
+// PackageDir returns the directory of an in-module package. Import paths
+// outside the module, including ones like mod/../.. that would leave its
+// directory, are an error.
+func (m *Module) PackageDir(importPath string) (string, error) {
+	if importPath != m.Path && !strings.HasPrefix(importPath, m.Path+"/") {
+		return "", fmt.Errorf("package %s is outside module %s", importPath, m.Path)
+	}
+	rel := path.Clean(strings.TrimPrefix(strings.TrimPrefix(importPath, m.Path), "/"))
+	if rel == ".." || strings.HasPrefix(rel, "../") || path.IsAbs(rel) || filepath.IsAbs(filepath.FromSlash(rel)) {
+		return "", fmt.Errorf("package %s leaves module %s", importPath, m.Path)
+	}
+	return filepath.Join(m.Dir, filepath.FromSlash(rel)), nil
+}
+
+func modulePath(gomod string) (string, error) {
+	f, err := os.Open(gomod)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(sc.Text()), "module "); ok {
+			return strings.Trim(strings.TrimSpace(rest), `"`), nil
+		}
+	}
+	return "", fmt.Errorf("%s: no module directive", gomod)
+}
+
+// Linter checks manifests. Intercept and fallback targets are resolved
+// against Module with go/parser; a nil Module skips those checks.
+type Linter struct {
+	Module *Module
+
+	funcs map[string]map[string]bool // import path -> declared funcs (nil: no such package)
+}
+
+// NewLinter returns a linter resolving targets in mod.
+func NewLinter(mod *Module) *Linter {
+	return &Linter{Module: mod, funcs: make(map[string]map[string]bool)}
+}
+
+// LintFile reads and lints the manifest at path.
+func (l *Linter) LintFile(path string) ([]Issue, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return l.Lint(path, data), nil
+}
+
+// Lint checks a manifest for duplicate feature IDs, unknown tiers, invalid
+// quotas, gated features without on_deny or fallback, intercept targets
+// that do not exist, and invalid relations or version ranges. file is only
+// used to label positions.
+func (l *Linter) Lint(file string, data []byte) []Issue {
+	var issues []Issue
+	report := func(n *yaml.Node, severity, rule, featureID, format string, args ...any) {
+		issues = append(issues, Issue{
+			File: file, Line: n.Line, Column: n.Column, Severity: severity,
+			Rule: rule, FeatureID: featureID, Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		line := 1
+		_, _ = fmt.Sscanf(err.Error(), "yaml: line %d:", &line)
+		report(&yaml.Node{Line: line, Column: 1}, SeverityError, "syntax", "", "%v", err)
+		return issues
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		report(&yaml.Node{Line: 1, Column: 1}, SeverityError, "syntax", "", "manifest is not a mapping")
+		return issues
+	}
+	root := doc.Content[0]
+
+	if sdk := field(root, "sdk"); sdk == nil {
+		report(root, SeverityError, "missing_sdk", "", "missing sdk section")
+	} else if pid := field(sdk, "product_id"); pid == nil || pid.Value == "" {
+		report(sdk, SeverityError, "missing_product_id", "", "sdk.product_id is required")
+	}
+
+	features := field(root, "features")
+	if features == nil || features.Kind != yaml.SequenceNode {
+		report(root, SeverityError, "missing_features", "", "missing features list")
+		return issues
+	}
+
+	var m Manifest
+	firstSeen := map[string]*yaml.Node{}
+	for _, item := range features.Content {
+		var f Feature
+		if err := item.Decode(&f); err != nil {
+			report(item, SeverityError, "syntax", "", "invalid feature: %v", err)
+			continue
+		}
+		m.Features = append(m.Features, f)
+
+		if f.ID == "" {
+			report(item, SeverityError, "missing_id", "", "feature has no id")
+		} else if first, ok := firstSeen[f.ID]; ok {
+			report(field(item, "id"), SeverityError, "duplicate_id", f.ID, "duplicate feature id %q (first defined at line %d)", f.ID, first.Line)
+		} else {
+			firstSeen[f.ID] = item
+		}
+
+		if f.Tier == "" {
+			report(item, SeverityError, "unknown_tier", f.ID, "feature has no tier (want one of %s)", strings.Join(Tiers, ", "))
+		} else if !oneOf(f.Tier, Tiers) {
+			report(field(item, "tier"), SeverityError, "unknown_tier", f.ID, "unknown tier %q (want one of %s)", f.Tier, strings.Join(Tiers, ", "))
+		}
+
+		if q := field(item, "quota"); q != nil && f.Quota != nil {
+			if p := field(q, "period"); p == nil {
+				report(q, SeverityError, "invalid_quota_period", f.ID, "quota has no period (want one of %s)", strings.Join(QuotaPeriods, ", "))
+			} else if !oneOf(f.Quota.Period, QuotaPeriods) {
+				report(p, SeverityError, "invalid_quota_period", f.ID, "invalid quota period %q (want one of %s)", f.Quota.Period, strings.Join(QuotaPeriods, ", "))
+			}
+			if f.Quota.Limit <= 0 {
+				report(nonNil(field(q, "limit"), q), SeverityError, "invalid_quota_limit", f.ID, "quota limit must be positive")
+			}
+		}
+
+		if f.Intercept != nil && f.Tier != "basic" && f.Fallback == nil && f.OnDeny == nil {
+			report(item, SeverityError, "missing_on_deny", f.ID, "gated feature needs on_deny or fallback")
+		}
+		if f.OnDeny != nil && !oneOf(f.OnDeny.Action, OnDenyActions) {
+			report(nonNil(field(field(item, "on_deny"), "action"), field(item, "on_deny")), SeverityError, "invalid_on_deny_action",
+				f.ID, "invalid on_deny action %q (want one of %s)", f.OnDeny.Action, strings.Join(OnDenyActions, ", "))
+		}
+
+		for _, key := range []string{"intercept", "fallback"} {
+			if n := field(item, key); n != nil {
+				l.checkTarget(n, key, f.ID, report)
+			}
+		}
+
+		if err := f.Versions().Validate(); err != nil {
+			report(nonNil(field(item, "min_version"), field(item, "max_version"), item), SeverityError, "invalid_version_range", f.ID, "%v", err)
+		}
+	}
+
+	for _, err := range deps.Validate(m.DependencyNodes()) {
+		pos, id := features, ""
+		var de *deps.Error
+		if errors.As(err, &de) {
+			id = de.FeatureID
+			if n, ok := firstSeen[id]; ok {
+				pos = n
+			}
+		}
+		report(pos, SeverityError, "invalid_relation", id, "%v", err)
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Line != issues[j].Line {
+			return issues[i].Line < issues[j].Line
+		}
+		return issues[i].Column < issues[j].Column
+	})
+	return issues
+}
+
+// checkTarget resolves an intercept or fallback target in the module.
+func (l *Linter) checkTarget(n *yaml.Node, key, featureID string, report func(*yaml.Node, string, string, string, string, ...any)) {
+	var t Target
+	if err := n.Decode(&t); err != nil || t.Package == "" || t.Function == "" {
+		report(n, SeverityError, "invalid_"+key, featureID, "%s needs package and function", key)
+		return
+	}
+	if l.Module == nil {
+		return
+	}
+	pkgNode := nonNil(field(n, "package"), n)
+	fnNode := nonNil(field(n, "function"), n)
+	if t.Package != l.Module.Path && !strings.HasPrefix(t.Package, l.Module.Path+"/") {
+		report(pkgNode, SeverityWarning, "external_"+key, featureID, "%s package %s is outside module %s; not checked", key, t.Package, l.Module.Path)
+		return
+	}
+	funcs, err := l.packageFuncs(t.Package)
+	if err != nil {
+		report(pkgNode, SeverityError, "unknown_"+key+"_package", featureID, "%s package %s: %v", key, t.Package, err)
+		return
+	}
+	if !funcs[t.Function] {
+		report(fnNode, SeverityError, "unknown_"+key+"_function", featureID, "%s function %s not found in package %s", key, t.Function, t.Package)
+	}
+}
+
+// packageFuncs parses the non-test files of an in-module package and returns
+// its functions, with methods keyed as Type.Method.
+func (l *Linter) packageFuncs(importPath string) (map[string]bool, error) {
+	if funcs, ok := l.funcs[importPath]; ok {
+		if funcs == nil {
+			return nil, fmt.Errorf("no Go files in module")
+		}
+		return funcs, nil
+	}
+	dir, err := l.Module.PackageDir(importPath)
+	if err != nil {
+		return nil, err
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "*.go"))
+
+	var funcs map[string]bool
+	fset := token.NewFileSet()
+	for _, path := range files {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		if funcs == nil {
+			funcs = make(map[string]bool)
+		}
+		for _, decl := range f.Decls {
+			fd, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+			name := fd.Name.Name
+			if fd.Recv != nil && len(fd.Recv.List) > 0 {
+				name = receiverType(fd.Recv.List[0].Type) + "." + name
+			}
+			funcs[name] = true
+		}
+	}
+	l.funcs[importPath] = funcs
+	if funcs == nil {
+		return nil, fmt.Errorf("no Go files in module")
+	}
+	return funcs, nil
+}
+
+func receiverType(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return receiverType(t.X)
+	case *ast.IndexExpr:
+		return receiverType(t.X)
+	case *ast.IndexListExpr:
+		return receiverType(t.X)
+	case *ast.Ident:
+		return t.Name
+	}
+	return ""
+}
+
+// field returns the value node of key in mapping n, or nil.
+func field(n *yaml.Node, key string) *yaml.Node {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func nonNil(nodes ...*yaml.Node) *yaml.Node {
+	for _, n := range nodes {
+		if n != nil {
+			return n
+		}
+	}
+	return &yaml.Node{Line: 1, Column: 1}
+}
+
+func oneOf(s string, list []string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
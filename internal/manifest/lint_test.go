@@ -0,0 +1,103 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("go.mod", "module example.com/app\n\ngo 1.24\n")
+	write("internal/export/export.go", "package export\n\nfunc GeneratePDF() {}\n")
+
+	mod, err := FindModule(filepath.Join(dir, "internal"))
+	if err != nil || mod.Path != "example.com/app" {
+		t.Fatalf("FindModule = %+v, %v", mod, err)
+	}
+
+	const data = `sdk:
+  product_id: app
+features:
+  - id: pdf_export
+    tier: professional
+    intercept:
+      package: "example.com/app/internal/export"
+      function: "GeneratePDF"
+    on_deny:
+      action: error
+  - id: pdf_export
+    tier: gold
+    intercept:
+      package: "example.com/app/internal/export"
+      function: "GenerateWord"
+    quota:
+      limit: 10
+      period: hourly
+`
+	var got []string
+	for _, issue := range NewLinter(mod).Lint("m.yaml", []byte(data)) {
+		got = append(got, issue.String())
+	}
+	for _, w := range []string{
+		`m.yaml:11:5: error: gated feature needs on_deny or fallback (missing_on_deny)`,
+		`m.yaml:11:9: error: duplicate feature id "pdf_export" (first defined at line 4) (duplicate_id)`,
+		`m.yaml:12:11: error: unknown tier "gold"`,
+		`m.yaml:15:17: error: intercept function GenerateWord not found in package example.com/app/internal/export (unknown_intercept_function)`,
+		`m.yaml:18:15: error: invalid quota period "hourly"`,
+	} {
+		found := false
+		for _, g := range got {
+			if strings.HasPrefix(g, w) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("missing %q in:\n%s", w, strings.Join(got, "\n"))
+		}
+	}
+	if len(got) != 5 {
+		t.Errorf("got %d issues, want 5:\n%s", len(got), strings.Join(got, "\n"))
+	}
+}
+
+func TestPackagesOutsideModule(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"app/go.mod":      "module example.com/app\n\ngo 1.24\n",
+		"outside/evil.go": "package outside\n\nfunc Evil() {}\n",
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mod, err := FindModule(filepath.Join(dir, "app"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, pkg := range []string{"example.com/app/../outside", "example.com/app/inner/../../outside", "example.com/app//outside"} {
+		data := "sdk:\n  product_id: app\nfeatures:\n  - id: f\n    tier: basic\n    intercept:\n      package: \"" + pkg + "\"\n      function: Evil\n    on_deny:\n      action: error\n"
+		issues := NewLinter(mod).Lint("m.yaml", []byte(data))
+		if len(issues) != 1 || issues[0].Rule != "unknown_intercept_package" {
+			t.Errorf("package %s: issues = %v, want unknown_intercept_package", pkg, issues)
+		}
+		if _, err := LookupSignature(mod, Target{Package: pkg, Function: "Evil"}); err == nil {
+			t.Errorf("LookupSignature found %s.Evil outside the module", pkg)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+// Package manifest reads lcc-features.yaml manifests, including the fields
+// the demo adds on top of the SDK schema (feature relations).
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"demo-app/internal/deps"
+	"demo-app/internal/semver"
+)
+
+// Manifest is a parsed lcc-features.yaml.
+type Manifest struct {
+	SDK      SDK       `yaml:"sdk" json:"sdk"`
+	Features []Feature `yaml:"features" json:"features"`
+}
+
+// SDK is the manifest's sdk: section.
+type SDK struct {
+	LCCURL         string `yaml:"lcc_url" json:"lcc_url"`
+	ProductID      string `yaml:"product_id" json:"product_id"`
+	ProductVersion string `yaml:"product_version" json:"product_version"`
+}
+
+// Feature is one protected feature.
+type Feature struct {
+	ID            string   `yaml:"id" json:"id"`
+	Name          string   `yaml:"name" json:"name"`
+	Tier          string   `yaml:"tier" json:"tier"`
+	Intercept     *Target  `yaml:"intercept,omitempty" json:"intercept,omitempty"`
+	Fallback      *Target  `yaml:"fallback,omitempty" json:"fallback,omitempty"`
+	Quota         *Quota   `yaml:"quota,omitempty" json:"quota,omitempty"`
+	OnDeny        *OnDeny  `yaml:"on_deny,omitempty" json:"on_deny,omitempty"`
+	Requires      []string `yaml:"requires,omitempty" json:"requires,omitempty"`
+	ConflictsWith []string `yaml:"conflicts_with,omitempty" json:"conflicts_with,omitempty"`
+	MinVersion    string   `yaml:"min_version,omitempty" json:"min_version,omitempty"`
+	MaxVersion    string   `yaml:"max_version,omitempty" json:"max_version,omitempty"`
+}
+
+// Versions returns the product versions the feature is available in.
+func (f Feature) Versions() semver.Range {
+	return semver.Range{Min: f.MinVersion, Max: f.MaxVersion}
+}
+
+// Target names a Go function by package import path and name.
+type Target struct {
+	Package  string `yaml:"package" json:"package"`
+	Function string `yaml:"function" json:"function"`
+}
+
+// Quota is a per-feature quota.
+type Quota struct {
+	Limit  int    `yaml:"limit" json:"limit"`
+	Period string `yaml:"period" json:"period"`
+}
+
+// OnDeny is the action taken when a feature without fallback is denied.
+type OnDeny struct {
+	Action  string `yaml:"action" json:"action"`
+	Message string `yaml:"message,omitempty" json:"message"`
+}
+
+// Parse decodes a manifest without validating it.
+func Parse(data []byte) (*Manifest, error) {
+	var m Manifest
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+	return &m, nil
+}
+
+// Load reads, parses and validates the manifest at path.
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	m, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := m.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return m, nil
+}
+
+// Validate checks the feature relations and version ranges.
+func (m *Manifest) Validate() error {
+	errs := deps.Validate(m.DependencyNodes())
+	for _, f := range m.Features {
+		if err := f.Versions().Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("feature %s: %w", f.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Feature returns the feature with the given ID.
+func (m *Manifest) Feature(id string) (Feature, bool) {
+	for _, f := range m.Features {
+		if f.ID == id {
+			return f, true
+		}
+	}
+	return Feature{}, false
+}
+
+// DependencyNodes returns the features as dependency graph nodes.
+func (m *Manifest) DependencyNodes() []deps.Node {
+	nodes := make([]deps.Node, 0, len(m.Features))
+	for _, f := range m.Features {
+		nodes = append(nodes, deps.Node{ID: f.ID, Requires: f.Requires, ConflictsWith: f.ConflictsWith})
+	}
+	return nodes
+}
@@ -0,0 +1,361 @@
+package manifest
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Func is an exported package-level function found by ScanFuncs.
+type Func struct {
+	Package     string // import path
+	PackageName string
+	Name        string
+}
+
+// ScanFuncs parses the non-test Go files under dir (inside mod) and returns
+// their exported package-level functions, sorted by package and name.
+// Packages whose last path element is in exclude are skipped.
+func ScanFuncs(mod *Module, dir string, exclude []string) ([]Func, error) {
+	root := dir
+	if !filepath.IsAbs(root) {
+		root = filepath.Join(mod.Dir, dir)
+	}
+	var out []Func
+	fset := token.NewFileSet()
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && (oneOf(d.Name(), exclude) || strings.HasPrefix(d.Name(), ".") || d.Name() == "testdata") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+		f, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return err
+		}
+		if f.Name.Name == "main" {
+			return nil
+		}
+		rel, err := filepath.Rel(mod.Dir, filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+		importPath := mod.Path
+		if rel != "." {
+			importPath += "/" + filepath.ToSlash(rel)
+		}
+		for _, decl := range f.Decls {
+			if fd, ok := decl.(*ast.FuncDecl); ok && fd.Recv == nil && fd.Name.IsExported() {
+				out = append(out, Func{Package: importPath, PackageName: f.Name.Name, Name: fd.Name.Name})
+			}
+		}
+		return nil
+	})
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Package != out[j].Package {
+			return out[i].Package < out[j].Package
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out, err
+}
+
+// Scaffolding heuristics.
+var (
+	// leadingVerbs are dropped from function names when deriving feature IDs.
+	leadingVerbs = []string{"Run", "Generate", "Get", "Do", "Create", "Make", "Build", "Execute", "Process", "Compute"}
+	// hookPrefixes mark configuration hooks rather than features.
+	hookPrefixes = []string{"Set", "New", "Must", "Register", "Init"}
+	// premiumWords/basicWords pair a gated function with its fallback,
+	// e.g. RunAdvanced -> RunBasic.
+	premiumWords = []string{"Advanced", "Pro", "Premium", "Enterprise", "Full", "Plus"}
+	basicWords   = []string{"Basic", "Simple", "Lite", "Free", "Standard"}
+)
+
+// PlaceholderQuota is the quota suggested for every scaffolded feature.
+var PlaceholderQuota = Quota{Limit: 100, Period: "daily"}
+
+// Suggest turns functions into starter features. A function with a basic
+// variant in the same package (RunAdvanced/RunBasic) gets it as fallback, and
+// the variant itself is not offered as a feature. Features without a
+// fallback deny with an error.
+func Suggest(funcs []Func) []Feature {
+	byPkg := map[string]map[string]bool{}
+	for _, fn := range funcs {
+		if byPkg[fn.Package] == nil {
+			byPkg[fn.Package] = map[string]bool{}
+		}
+		byPkg[fn.Package][fn.Name] = true
+	}
+
+	fallbacks := map[Func]string{}
+	isFallback := map[Func]bool{}
+	for _, fn := range funcs {
+		if fb := fallbackFor(fn.Name, byPkg[fn.Package]); fb != "" {
+			fallbacks[fn] = fb
+			isFallback[Func{fn.Package, fn.PackageName, fb}] = true
+		}
+	}
+
+	var out []Feature
+	for _, fn := range funcs {
+		if isFallback[fn] || hasWordPrefix(fn.Name, hookPrefixes) {
+			continue
+		}
+		words := splitCamel(fn.Name)
+		if len(words) > 1 && oneOf(words[0], leadingVerbs) {
+			words = words[1:]
+		}
+		if !strings.EqualFold(words[len(words)-1], fn.PackageName) {
+			words = append(words, fn.PackageName)
+		}
+
+		f := Feature{
+			ID:        strings.ToLower(strings.Join(words, "_")),
+			Name:      titleWords(words),
+			Tier:      "professional",
+			Intercept: &Target{Package: fn.Package, Function: fn.Name},
+		}
+		if hasWord(fn.Name, "Enterprise") {
+			f.Tier = "enterprise"
+		}
+		q := PlaceholderQuota
+		f.Quota = &q
+		if fb, ok := fallbacks[fn]; ok {
+			f.Fallback = &Target{Package: fn.Package, Function: fb}
+		} else {
+			f.OnDeny = &OnDeny{Action: "error", Message: fmt.Sprintf("%s requires %s license", f.Name, titleWords([]string{f.Tier}))}
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
+func fallbackFor(name string, pkgFuncs map[string]bool) string {
+	words := splitCamel(name)
+	for i, w := range words {
+		if !oneOf(w, premiumWords) {
+			continue
+		}
+		for _, b := range basicWords {
+			cand := strings.Join(words[:i], "") + b + strings.Join(words[i+1:], "")
+			if pkgFuncs[cand] {
+				return cand
+			}
+		}
+	}
+	return ""
+}
+
+// MergeResult reports what Merge did with each suggested feature.
+type MergeResult struct {
+	Added   []string `json:"added"`
+	Skipped []string `json:"skipped"` // already present by ID or intercept target
+}
+
+// Merge adds the suggested features that existing does not already declare
+// (by ID or intercept target). Existing content is never rewritten: new
+// features are appended as text when the features list ends the file, and
+// only otherwise is the document re-encoded (keeping comments and values,
+// but not blank lines).
+func Merge(existing []byte, suggested []Feature) ([]byte, MergeResult, error) {
+	var res MergeResult
+	var doc yaml.Node
+	if err := yaml.Unmarshal(existing, &doc); err != nil {
+		return nil, res, fmt.Errorf("invalid manifest: %w", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, res, fmt.Errorf("invalid manifest: not a mapping")
+	}
+	root := doc.Content[0]
+	seq := field(root, "features")
+
+	ids, targets := map[string]bool{}, map[string]bool{}
+	if seq != nil {
+		for _, item := range seq.Content {
+			var f Feature
+			if err := item.Decode(&f); err != nil {
+				continue
+			}
+			ids[f.ID] = true
+			if f.Intercept != nil {
+				targets[f.Intercept.Package+"."+f.Intercept.Function] = true
+			}
+		}
+	}
+	var add []Feature
+	for _, f := range suggested {
+		if ids[f.ID] || (f.Intercept != nil && targets[f.Intercept.Package+"."+f.Intercept.Function]) {
+			res.Skipped = append(res.Skipped, f.ID)
+			continue
+		}
+		ids[f.ID] = true
+		add = append(add, f)
+		res.Added = append(res.Added, f.ID)
+	}
+	if len(add) == 0 {
+		return existing, res, nil
+	}
+
+	last := root.Content[len(root.Content)-1]
+	switch {
+	case seq == nil:
+		out := withTrailingNewline(existing)
+		out = append(out, "\nfeatures:\n"...)
+		return append(out, renderFeatures(add, "  ")...), res, nil
+	case seq == last && seq.Style&yaml.FlowStyle == 0 && len(seq.Content) > 0:
+		indent := strings.Repeat(" ", max(seq.Content[0].Column-3, 0))
+		out := withTrailingNewline(existing)
+		if !bytes.HasSuffix(out, []byte("\n\n")) {
+			out = append(out, '\n')
+		}
+		return append(out, renderFeatures(add, indent)...), res, nil
+	}
+
+	// The features list is empty, in flow style, or followed by other keys:
+	// splice the new entries into the tree and re-encode.
+	seq.Style = 0
+	for _, f := range add {
+		n, err := featureNode(f)
+		if err != nil {
+			return nil, res, err
+		}
+		seq.Content = append(seq.Content, n)
+	}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, res, err
+	}
+	return buf.Bytes(), res, nil
+}
+
+// Render produces a new manifest document in the layout of this repo's
+// manifests: quoted strings and a blank line between features.
+func Render(m *Manifest) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "sdk:\n  lcc_url: %q\n  product_id: %q\n  product_version: %q\n", m.SDK.LCCURL, m.SDK.ProductID, m.SDK.ProductVersion)
+	b.WriteString("\nfeatures:\n")
+	b.Write(renderFeatures(m.Features, "  "))
+	return []byte(b.String())
+}
+
+func renderFeatures(features []Feature, indent string) []byte {
+	var parts []string
+	for _, f := range features {
+		n, err := featureNode(f)
+		if err != nil {
+			continue
+		}
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		_ = enc.Encode(&yaml.Node{Kind: yaml.SequenceNode, Content: []*yaml.Node{n}})
+		_ = enc.Close()
+		parts = append(parts, indentLines(buf.String(), indent, ""))
+	}
+	return []byte(strings.Join(parts, "\n"))
+}
+
+// featureNode encodes f with the string values the repo quotes quoted.
+func featureNode(f Feature) (*yaml.Node, error) {
+	var n yaml.Node
+	if err := n.Encode(f); err != nil {
+		return nil, err
+	}
+	quoteStrings(&n)
+	return &n, nil
+}
+
+var quotedKeys = []string{"name", "package", "function", "message", "lcc_url", "product_id", "product_version"}
+
+func quoteStrings(n *yaml.Node) {
+	if n.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			if v := n.Content[i+1]; v.Kind == yaml.ScalarNode && oneOf(n.Content[i].Value, quotedKeys) {
+				v.Style = yaml.DoubleQuotedStyle
+			}
+		}
+	}
+	for _, c := range n.Content {
+		quoteStrings(c)
+	}
+}
+
+// indentLines prefixes every non-empty line of s (after skipping head) with indent.
+func indentLines(s, indent, head string) string {
+	var b strings.Builder
+	b.WriteString(head)
+	for _, line := range strings.SplitAfter(s, "\n") {
+		if strings.TrimSpace(line) != "" {
+			b.WriteString(indent)
+		}
+		b.WriteString(line)
+	}
+	return b.String()
+}
+
+func withTrailingNewline(b []byte) []byte {
+	out := append([]byte(nil), b...)
+	if len(out) > 0 && out[len(out)-1] != '\n' {
+		out = append(out, '\n')
+	}
+	return out
+}
+
+// splitCamel splits GeneratePDF into [Generate PDF] and PDFExport into
+// [PDF Export].
+func splitCamel(s string) []string {
+	rs := []rune(s)
+	var words []string
+	start := 0
+	for i := 1; i < len(rs); i++ {
+		prev, cur := rs[i-1], rs[i]
+		next := rune(0)
+		if i+1 < len(rs) {
+			next = rs[i+1]
+		}
+		if unicode.IsUpper(cur) && (unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && unicode.IsLower(next))) {
+			words = append(words, string(rs[start:i]))
+			start = i
+		}
+	}
+	return append(words, string(rs[start:]))
+}
+
+func titleWords(words []string) string {
+	out := make([]string, len(words))
+	for i, w := range words {
+		rs := []rune(w)
+		rs[0] = unicode.ToUpper(rs[0])
+		out[i] = string(rs)
+	}
+	return strings.Join(out, " ")
+}
+
+// hasWordPrefix reports whether name's first word is one of prefixes; the
+// name may be that word alone (New).
+func hasWordPrefix(name string, prefixes []string) bool {
+	return oneOf(splitCamel(name)[0], prefixes)
+}
+
+func hasWord(name, word string) bool {
+	return oneOf(word, splitCamel(name))
+}
@@ -0,0 +1,109 @@
+package manifest
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSuggest(t *testing.T) {
+	funcs := []Func{
+		{"example.com/app/internal/analytics", "analytics", "RunAdvanced"},
+		{"example.com/app/internal/analytics", "analytics", "RunBasic"},
+		{"example.com/app/internal/analytics", "analytics", "SetVolumeConsumer"},
+		{"example.com/app/internal/analytics", "analytics", "New"},
+		{"example.com/app/internal/export", "export", "GeneratePDF"},
+	}
+	got := Suggest(funcs)
+	if len(got) != 2 {
+		t.Fatalf("Suggest = %+v, want 2 features", got)
+	}
+
+	adv := got[0]
+	if adv.ID != "advanced_analytics" || adv.Fallback == nil || adv.Fallback.Function != "RunBasic" || adv.OnDeny != nil {
+		t.Errorf("advanced = %+v", adv)
+	}
+	pdf := got[1]
+	if pdf.ID != "pdf_export" || pdf.Fallback != nil || pdf.OnDeny == nil || pdf.OnDeny.Action != "error" {
+		t.Errorf("pdf = %+v", pdf)
+	}
+	if pdf.Quota == nil || *pdf.Quota != PlaceholderQuota {
+		t.Errorf("quota = %+v", pdf.Quota)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	existing := []byte(`sdk:
+  product_id: "app" # hand-edited
+
+features:
+  - id: pdf_export
+    name: "PDF Export"
+    tier: enterprise
+    intercept:
+      package: "example.com/app/internal/export"
+      function: "GeneratePDF"
+`)
+	suggested := Suggest([]Func{
+		{"example.com/app/internal/export", "export", "GeneratePDF"},
+		{"example.com/app/internal/export", "export", "GenerateExcel"},
+	})
+
+	out, res, err := Merge(existing, suggested)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(out, existing) {
+		t.Fatalf("existing content was rewritten:\n%s", out)
+	}
+	if strings.Join(res.Added, ",") != "excel_export" || strings.Join(res.Skipped, ",") != "pdf_export" {
+		t.Errorf("result = %+v", res)
+	}
+
+	m, err := Parse(out)
+	if err != nil {
+		t.Fatalf("merged manifest does not parse: %v\n%s", err, out)
+	}
+	if len(m.Features) != 2 || m.Features[0].Tier != "enterprise" || m.Features[1].ID != "excel_export" {
+		t.Errorf("features = %+v", m.Features)
+	}
+
+	again, res, err := Merge(out, suggested)
+	if err != nil || !bytes.Equal(again, out) || len(res.Added) != 0 {
+		t.Errorf("second merge changed the file: %+v, %v", res, err)
+	}
+}
+
+func TestScanFuncsModuleRoot(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("go.mod", "module example.com/lib\n\ngo 1.24\n")
+	write("lib.go", "package lib\n\nfunc ExportPDF() {}\n")
+	write("export/export.go", "package export\n\nfunc GenerateExcel() {}\n")
+
+	mod, err := FindModule(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	funcs, err := ScanFuncs(mod, ".", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, f := range funcs {
+		got = append(got, f.Package+"."+f.Name)
+	}
+	if want := "example.com/lib.ExportPDF,example.com/lib/export.GenerateExcel"; strings.Join(got, ",") != want {
+		t.Errorf("ScanFuncs = %s, want %s", strings.Join(got, ","), want)
+	}
+}
@@ -0,0 +1,256 @@
+// Package metrics keeps counters, gauges and histograms in memory and
+// writes them in the Prometheus text exposition format, without depending
+// on the Prometheus client library.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Metric types.
+const (
+	TypeCounter   = "counter"
+	TypeGauge     = "gauge"
+	TypeHistogram = "histogram"
+)
+
+// ContentType is the content type of the text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefBuckets are the default latency buckets, in seconds.
+var DefBuckets = []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Registry holds metric families. Series are created on first use; labels
+// are given as name, value pairs.
+type Registry struct {
+	mu       sync.Mutex
+	families map[string]*family
+}
+
+type family struct {
+	name, help, typ string
+	buckets         []float64
+	series          map[string]*series // rendered labels -> series
+}
+
+type series struct {
+	labels string   // rendered, e.g. `op="consume",result="allowed"`
+	value  float64  // counter and gauge
+	counts []uint64 // histogram, per bucket (not cumulative)
+	sum    float64
+	count  uint64
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{families: make(map[string]*family)}
+}
+
+// NewCounter declares a counter.
+func (r *Registry) NewCounter(name, help string) { r.declare(name, help, TypeCounter, nil) }
+
+// NewGauge declares a gauge.
+func (r *Registry) NewGauge(name, help string) { r.declare(name, help, TypeGauge, nil) }
+
+// NewHistogram declares a histogram with the given upper bounds, which
+// must be sorted; nil uses DefBuckets.
+func (r *Registry) NewHistogram(name, help string, buckets []float64) {
+	if buckets == nil {
+		buckets = DefBuckets
+	}
+	r.declare(name, help, TypeHistogram, buckets)
+}
+
+func (r *Registry) declare(name, help, typ string, buckets []float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.families[name]; ok {
+		panic("metrics: " + name + " declared twice")
+	}
+	r.families[name] = &family{name: name, help: help, typ: typ, buckets: buckets, series: make(map[string]*series)}
+}
+
+// Add adds v to a counter or gauge.
+func (r *Registry) Add(name string, v float64, labels ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.series(name, labels, TypeCounter, TypeGauge)
+	s.value += v
+}
+
+// Set sets a gauge.
+func (r *Registry) Set(name string, v float64, labels ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.series(name, labels, TypeGauge).value = v
+}
+
+// Observe records v in a histogram.
+func (r *Registry) Observe(name string, v float64, labels ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.series(name, labels, TypeHistogram)
+	f := r.families[name]
+	if s.counts == nil {
+		s.counts = make([]uint64, len(f.buckets))
+	}
+	if i := sort.SearchFloat64s(f.buckets, v); i < len(f.buckets) {
+		s.counts[i]++
+	}
+	s.sum += v
+	s.count++
+}
+
+// Reset drops every series of a family, e.g. before setting gauges that
+// are recomputed at scrape time.
+func (r *Registry) Reset(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if f, ok := r.families[name]; ok {
+		f.series = make(map[string]*series)
+	}
+}
+
+// series returns the series of name with labels, creating it. r.mu must be
+// held.
+func (r *Registry) series(name string, labels []string, types ...string) *series {
+	f, ok := r.families[name]
+	if !ok {
+		panic("metrics: " + name + " is not declared")
+	}
+	if !contains(types, f.typ) {
+		panic(fmt.Sprintf("metrics: %s is a %s", name, f.typ))
+	}
+	key := renderLabels(labels)
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{labels: key}
+		f.series[key] = s
+	}
+	return s
+}
+
+// WriteText writes every family in the text exposition format, sorted by
+// name and labels.
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+	names := make([]string, 0, len(r.families))
+	for name := range r.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		f := r.families[name]
+		fmt.Fprintf(bw, "# HELP %s %s\n# TYPE %s %s\n", f.name, escapeHelp(f.help), f.name, f.typ)
+		keys := make([]string, 0, len(f.series))
+		for k := range f.series {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			s := f.series[k]
+			if f.typ != TypeHistogram {
+				fmt.Fprintf(bw, "%s%s %s\n", f.name, braces(s.labels), formatFloat(s.value))
+				continue
+			}
+			var cum uint64
+			for i, le := range f.buckets {
+				if s.counts != nil {
+					cum += s.counts[i]
+				}
+				fmt.Fprintf(bw, "%s_bucket%s %d\n", f.name, braces(join(s.labels, `le="`+formatFloat(le)+`"`)), cum)
+			}
+			fmt.Fprintf(bw, "%s_bucket%s %d\n", f.name, braces(join(s.labels, `le="+Inf"`)), s.count)
+			fmt.Fprintf(bw, "%s_sum%s %s\n", f.name, braces(s.labels), formatFloat(s.sum))
+			fmt.Fprintf(bw, "%s_count%s %d\n", f.name, braces(s.labels), s.count)
+		}
+	}
+	return bw.Flush()
+}
+
+// Handler serves the registry. collect, if not nil, runs before each
+// scrape to refresh gauges computed from other state.
+func (r *Registry) Handler(collect func()) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if collect != nil {
+			collect()
+		}
+		w.Header().Set("Content-Type", ContentType)
+		_ = r.WriteText(w)
+	})
+}
+
+// renderLabels renders name, value pairs sorted by name. A trailing name
+// without a value gets an empty value.
+func renderLabels(pairs []string) string {
+	if len(pairs)%2 == 1 {
+		pairs = append(pairs, "")
+	}
+	type label struct{ name, value string }
+	ls := make([]label, 0, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		ls = append(ls, label{pairs[i], pairs[i+1]})
+	}
+	sort.Slice(ls, func(i, j int) bool { return ls[i].name < ls[j].name })
+	parts := make([]string, len(ls))
+	for i, l := range ls {
+		parts[i] = l.name + `="` + escapeValue(l.value) + `"`
+	}
+	return strings.Join(parts, ",")
+}
+
+var (
+	valueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapeValue(s string) string { return valueEscaper.Replace(s) }
+func escapeHelp(s string) string  { return helpEscaper.Replace(s) }
+
+func braces(labels string) string {
+	if labels == "" {
+		return ""
+	}
+	return "{" + labels + "}"
+}
+
+func join(labels, extra string) string {
+	if labels == "" {
+		return extra
+	}
+	return labels + "," + extra
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
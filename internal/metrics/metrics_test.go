@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteText(t *testing.T) {
+	r := NewRegistry()
+	r.NewCounter("calls_total", "Calls.\nAll of them.")
+	r.NewGauge("remaining", "Remaining.")
+	r.NewHistogram("latency_seconds", "Latency.", []float64{0.1, 1})
+
+	r.Add("calls_total", 1, "result", "ok", "op", "consume")
+	r.Add("calls_total", 2, "op", "consume", "result", "ok")
+	r.Set("remaining", 7, "product", `a"b`)
+	r.Observe("latency_seconds", 0.05)
+	r.Observe("latency_seconds", 0.5)
+	r.Observe("latency_seconds", 3)
+
+	var b strings.Builder
+	if err := r.WriteText(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := `# HELP calls_total Calls.\nAll of them.
+# TYPE calls_total counter
+calls_total{op="consume",result="ok"} 3
+# HELP latency_seconds Latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{le="0.1"} 1
+latency_seconds_bucket{le="1"} 2
+latency_seconds_bucket{le="+Inf"} 3
+latency_seconds_sum 3.55
+latency_seconds_count 3
+# HELP remaining Remaining.
+# TYPE remaining gauge
+remaining{product="a\"b"} 7
+`
+	if got := b.String(); got != want {
+		t.Errorf("WriteText:\n%s\nwant:\n%s", got, want)
+	}
+
+	r.Reset("remaining")
+	b.Reset()
+	_ = r.WriteText(&b)
+	if strings.Contains(b.String(), "remaining{") {
+		t.Errorf("Reset kept series:\n%s", b.String())
+	}
+}
+
+func TestObserveCall(t *testing.T) {
+	r := NewSDKRegistry()
+	start := time.Now()
+	r.ObserveCall("p", "consume", "", start, true, nil)
+	r.ObserveCall("p", "consume", "", start, true, errors.New("down"))
+	r.ObserveCall("p", "check_feature", "pdf", start, false, nil)
+
+	var b strings.Builder
+	_ = r.WriteText(&b)
+	for _, line := range []string{
+		`lcc_sdk_calls_total{operation="consume",product="p",result="allowed"} 1`,
+		`lcc_sdk_calls_total{operation="consume",product="p",result="error"} 1`,
+		`lcc_sdk_calls_total{feature="pdf",operation="check_feature",product="p",result="denied"} 1`,
+		`lcc_sdk_call_duration_seconds_count{feature="pdf",operation="check_feature",product="p",result="denied"} 1`,
+	} {
+		if !strings.Contains(b.String(), line+"\n") {
+			t.Errorf("missing %s in:\n%s", line, b.String())
+		}
+	}
+}
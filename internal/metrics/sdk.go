@@ -0,0 +1,49 @@
+package metrics
+
+import "time"
+
+// SDK metric families shared by the demo status server and the web server.
+const (
+	SDKCalls            = "lcc_sdk_calls_total"
+	SDKCallDuration     = "lcc_sdk_call_duration_seconds"
+	QuotaRemaining      = "lcc_quota_remaining"
+	ActiveSlots         = "lcc_active_slots"
+	RegisteredInstances = "lcc_registered_instances"
+)
+
+// Results of an SDK call.
+const (
+	ResultAllowed = "allowed"
+	ResultDenied  = "denied"
+	ResultError   = "error"
+)
+
+// NewSDKRegistry returns a registry with the SDK families declared.
+func NewSDKRegistry() *Registry {
+	r := NewRegistry()
+	r.NewCounter(SDKCalls, "LCC SDK calls by product, operation, feature and result.")
+	r.NewHistogram(SDKCallDuration, "LCC SDK call latency in seconds by product, operation, feature and result.", nil)
+	r.NewGauge(QuotaRemaining, "Quota remaining as last reported by LCC.")
+	r.NewGauge(ActiveSlots, "Concurrency slots currently held.")
+	r.NewGauge(RegisteredInstances, "SDK instances registered with LCC.")
+	return r
+}
+
+// ObserveCall counts an SDK call that started at start. allowed is ignored
+// when err is set. Product-level operations pass an empty feature and get no
+// feature label.
+func (r *Registry) ObserveCall(product, op, feature string, start time.Time, allowed bool, err error) {
+	result := ResultDenied
+	switch {
+	case err != nil:
+		result = ResultError
+	case allowed:
+		result = ResultAllowed
+	}
+	labels := []string{"product", product, "operation", op, "result", result}
+	if feature != "" {
+		labels = append(labels, "feature", feature)
+	}
+	r.Add(SDKCalls, 1, labels...)
+	r.Observe(SDKCallDuration, time.Since(start).Seconds(), labels...)
+}
@@ -0,0 +1,171 @@
+package offline
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Lease reasons.
+const (
+	ReasonLeasesExhausted = "leases_exhausted"
+	ReasonLeaseNotFound   = "lease_not_found"
+	ReasonLeaseExpired    = "lease_expired"
+)
+
+// DefaultLeaseTTL is used when neither the license nor the caller sets a TTL.
+const DefaultLeaseTTL = 60 * time.Second
+
+// Lease is a checked-out floating license.
+type Lease struct {
+	ID           string    `json:"lease_id"`
+	Holder       string    `json:"holder"`
+	CheckedOutAt time.Time `json:"checked_out_at"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	TTLSeconds   float64   `json:"ttl_seconds"`
+	Renewals     int       `json:"renewals"`
+}
+
+// LeaseStatus is a snapshot of a lease pool.
+type LeaseStatus struct {
+	Max       int     `json:"max"`
+	InUse     int     `json:"in_use"`
+	Available int     `json:"available"`
+	Reclaimed int     `json:"reclaimed"` // leases reclaimed after their TTL ran out
+	Holders   []Lease `json:"holders"`
+}
+
+// LeasePool hands out floating licenses as leases. Unlike AcquireSlot, a lease
+// outlives the call that took it: the holder must renew it before its TTL runs
+// out, or the lease is reclaimed and the license returns to the pool.
+type LeasePool struct {
+	mu        sync.Mutex
+	max       int
+	ttl       time.Duration
+	now       func() time.Time
+	leases    map[string]*Lease    // leaseID -> lease
+	expired   map[string]time.Time // reclaimed leaseID -> reclaimed at
+	reclaimed int
+}
+
+// NewLeasePool returns a pool of max floating licenses with a default lease TTL.
+func NewLeasePool(max int, ttl time.Duration, now func() time.Time) *LeasePool {
+	if ttl <= 0 {
+		ttl = DefaultLeaseTTL
+	}
+	if now == nil {
+		now = time.Now
+	}
+	return &LeasePool{
+		max:     max,
+		ttl:     ttl,
+		now:     now,
+		leases:  make(map[string]*Lease),
+		expired: make(map[string]time.Time),
+	}
+}
+
+// Checkout takes a lease for holder. A zero ttl uses the pool default.
+func (p *LeasePool) Checkout(holder string, ttl time.Duration) (Lease, bool, string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.now()
+	p.reclaimLocked(now)
+
+	if len(p.leases) >= p.max {
+		return Lease{}, false, ReasonLeasesExhausted
+	}
+	if ttl <= 0 {
+		ttl = p.ttl
+	}
+	l := &Lease{
+		ID:           newLeaseID(),
+		Holder:       holder,
+		CheckedOutAt: now,
+		ExpiresAt:    now.Add(ttl),
+		TTLSeconds:   ttl.Seconds(),
+	}
+	p.leases[l.ID] = l
+	return *l, true, ReasonOK
+}
+
+// Renew extends a lease by its TTL from now.
+func (p *LeasePool) Renew(leaseID string) (Lease, bool, string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.now()
+	p.reclaimLocked(now)
+
+	l, ok := p.leases[leaseID]
+	if !ok {
+		return Lease{}, false, p.missingReasonLocked(leaseID)
+	}
+	l.ExpiresAt = now.Add(time.Duration(l.TTLSeconds * float64(time.Second)))
+	l.Renewals++
+	return *l, true, ReasonOK
+}
+
+// Checkin returns a lease to the pool.
+func (p *LeasePool) Checkin(leaseID string) (bool, string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.reclaimLocked(p.now())
+
+	if _, ok := p.leases[leaseID]; !ok {
+		return false, p.missingReasonLocked(leaseID)
+	}
+	delete(p.leases, leaseID)
+	return true, ReasonOK
+}
+
+// Status lists the current holders, oldest checkout first.
+func (p *LeasePool) Status() LeaseStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.reclaimLocked(p.now())
+
+	st := LeaseStatus{Max: p.max, InUse: len(p.leases), Reclaimed: p.reclaimed, Holders: make([]Lease, 0, len(p.leases))}
+	st.Available = p.max - st.InUse
+	for _, l := range p.leases {
+		st.Holders = append(st.Holders, *l)
+	}
+	sort.Slice(st.Holders, func(i, j int) bool {
+		if !st.Holders[i].CheckedOutAt.Equal(st.Holders[j].CheckedOutAt) {
+			return st.Holders[i].CheckedOutAt.Before(st.Holders[j].CheckedOutAt)
+		}
+		return st.Holders[i].ID < st.Holders[j].ID
+	})
+	return st
+}
+
+// reclaimLocked returns leases whose TTL has run out to the pool.
+func (p *LeasePool) reclaimLocked(now time.Time) {
+	for id, l := range p.leases {
+		if !now.Before(l.ExpiresAt) {
+			delete(p.leases, id)
+			p.expired[id] = now
+			p.reclaimed++
+		}
+	}
+	// Remember reclaimed IDs only long enough to answer late renewals.
+	for id, at := range p.expired {
+		if now.Sub(at) > 10*p.ttl {
+			delete(p.expired, id)
+		}
+	}
+}
+
+func (p *LeasePool) missingReasonLocked(leaseID string) string {
+	if _, ok := p.expired[leaseID]; ok {
+		return ReasonLeaseExpired
+	}
+	return ReasonLeaseNotFound
+}
+
+func newLeaseID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return "lease-" + hex.EncodeToString(b)
+}
@@ -0,0 +1,348 @@
+// Package offline evaluates license checks in-process against a license
+// document, without talking to an LCC server. Results mirror the shapes
+// returned by the lcc-sdk client so tests and air-gapped demos can swap it in.
+package offline
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"demo-app/internal/license"
+)
+
+// Reasons returned by the evaluator.
+const (
+	ReasonOK                  = "ok"
+	ReasonFeatureNotFound     = "feature_not_found"
+	ReasonInsufficientTier    = "insufficient_tier"
+	ReasonQuotaExceeded       = "quota_exceeded"
+	ReasonTPSExceeded         = "tps_exceeded"
+	ReasonCapacityExceeded    = "capacity_exceeded"
+	ReasonConcurrencyExceeded = "concurrency_exceeded"
+)
+
+// QuotaInfo mirrors lccclient.QuotaInfo.
+type QuotaInfo struct {
+	Limit     int       `json:"limit"`
+	Used      int       `json:"used"`
+	Remaining int       `json:"remaining"`
+	Window    string    `json:"window,omitempty"`
+	ResetAt   time.Time `json:"reset_at,omitempty"`
+}
+
+// FeatureStatus mirrors lccclient.FeatureStatus.
+type FeatureStatus struct {
+	Enabled        bool        `json:"enabled"`
+	Reason         string      `json:"reason"`
+	Quota          *QuotaInfo  `json:"quota,omitempty"`
+	MaxCapacity    int         `json:"max_capacity,omitempty"`
+	MaxTPS         float64     `json:"max_tps,omitempty"`
+	MaxConcurrency int         `json:"max_concurrency,omitempty"`
+	Volume         *VolumeInfo `json:"volume,omitempty"`
+}
+
+// Option configures an Evaluator.
+type Option func(*Evaluator)
+
+// WithClock overrides the time source (useful for tests and simulated time).
+func WithClock(now func() time.Time) Option {
+	return func(e *Evaluator) { e.now = now }
+}
+
+// Evaluator answers license checks for a single product.
+type Evaluator struct {
+	doc license.Document
+	now func() time.Time
+
+	mu sync.Mutex
+
+	// product-level limits; zero means unlimited
+	quotaMax       int
+	quotaUsed      int
+	quotaWindow    string
+	quotaResetAt   time.Time
+	maxTPS         float64
+	maxCapacity    int
+	maxConcurrency int
+	seats          *SeatPool    // nil when the license has no seat limit
+	leases         *LeasePool   // nil when the license has no floating licenses
+	volume         *VolumeMeter // nil when the license has no volume limit
+
+	calls  []time.Time // CheckTPS calls within the last second
+	active int         // slots currently held
+}
+
+// New returns an evaluator for doc.
+func New(doc license.Document, opts ...Option) *Evaluator {
+	e := &Evaluator{doc: doc, now: time.Now}
+	for _, opt := range opts {
+		opt(e)
+	}
+	e.loadLimits(doc.Limits)
+	return e
+}
+
+// NewFromJSON builds an evaluator from either a bare license document (as
+// produced by GetLicenseJSON) or a signed license file.
+func NewFromJSON(data []byte, opts ...Option) (*Evaluator, error) {
+	var probe struct {
+		License json.RawMessage `json:"license"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("invalid license json: %w", err)
+	}
+	if len(probe.License) > 0 {
+		data = probe.License
+	}
+	var doc license.Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid license document: %w", err)
+	}
+	if doc.ProductID == "" {
+		return nil, fmt.Errorf("license document has no product_id")
+	}
+	return New(doc, opts...), nil
+}
+
+// NewFromMap builds an evaluator from a map such as GetLicenseJSON returns.
+func NewFromMap(m map[string]interface{}, opts ...Option) (*Evaluator, error) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	return NewFromJSON(data, opts...)
+}
+
+// Document returns the license document backing the evaluator.
+func (e *Evaluator) Document() license.Document { return e.doc }
+
+// GetInstanceID returns a stable pseudo instance ID for the product.
+func (e *Evaluator) GetInstanceID() string { return "offline-" + e.doc.ProductID }
+
+// Register is a no-op kept for parity with the SDK client.
+func (e *Evaluator) Register() error { return nil }
+
+// Close is a no-op kept for parity with the SDK client.
+func (e *Evaluator) Close() error { return nil }
+
+// CheckFeature reports whether featureID is enabled and the product limits it is subject to.
+func (e *Evaluator) CheckFeature(featureID string) (*FeatureStatus, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	f, ok := e.doc.Features[featureID]
+	if !ok {
+		return &FeatureStatus{Enabled: false, Reason: ReasonFeatureNotFound}, nil
+	}
+	if !f.Enabled {
+		return &FeatureStatus{Enabled: false, Reason: ReasonInsufficientTier}, nil
+	}
+
+	st := &FeatureStatus{
+		Enabled:        true,
+		Reason:         ReasonOK,
+		MaxCapacity:    e.maxCapacity,
+		MaxTPS:         e.maxTPS,
+		MaxConcurrency: e.maxConcurrency,
+	}
+	if e.quotaMax > 0 {
+		e.rollQuotaLocked()
+		st.Quota = e.quotaInfoLocked()
+	}
+	if e.volume != nil {
+		info := e.volume.Info()
+		st.Volume = &info
+	}
+	return st, nil
+}
+
+// Consume draws amount from the product quota pool. remaining is -1 when
+// the license has no quota.
+func (e *Evaluator) Consume(amount int) (allowed bool, remaining int, err error) {
+	if amount <= 0 {
+		return false, 0, fmt.Errorf("positive amount required")
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.quotaMax <= 0 {
+		return true, -1, nil
+	}
+	e.rollQuotaLocked()
+	if e.quotaUsed+amount > e.quotaMax {
+		return false, e.quotaMax - e.quotaUsed, nil
+	}
+	e.quotaUsed += amount
+	return true, e.quotaMax - e.quotaUsed, nil
+}
+
+// CheckTPS records a call and checks the observed rate over the last second.
+func (e *Evaluator) CheckTPS() (allowed bool, max float64, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := e.now()
+	cutoff := now.Add(-time.Second)
+	kept := e.calls[:0]
+	for _, ts := range e.calls {
+		if ts.After(cutoff) {
+			kept = append(kept, ts)
+		}
+	}
+	e.calls = append(kept, now)
+
+	if e.maxTPS <= 0 {
+		return true, 0, nil
+	}
+	return float64(len(e.calls)) <= e.maxTPS, e.maxTPS, nil
+}
+
+// CheckCapacity reports whether one more resource may be created when
+// current resources already exist.
+func (e *Evaluator) CheckCapacity(current int) (allowed bool, max int, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.maxCapacity <= 0 {
+		return true, 0, nil
+	}
+	return current < e.maxCapacity, e.maxCapacity, nil
+}
+
+// AcquireSlot takes a concurrency slot; call release when done.
+func (e *Evaluator) AcquireSlot() (release func(), allowed bool, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.maxConcurrency > 0 && e.active >= e.maxConcurrency {
+		return func() {}, false, nil
+	}
+	e.active++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			e.mu.Lock()
+			e.active--
+			e.mu.Unlock()
+		})
+	}, true, nil
+}
+
+// ActiveSlots returns the number of concurrency slots currently held.
+func (e *Evaluator) ActiveSlots() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.active
+}
+
+// Seats returns the named-user seat pool, or nil if the license has no
+// "seats" limit.
+func (e *Evaluator) Seats() *SeatPool { return e.seats }
+
+// Leases returns the floating-license lease pool, or nil if the license has
+// no "floating" limit.
+func (e *Evaluator) Leases() *LeasePool { return e.leases }
+
+// Volume returns the data-volume meter, or nil if the license has no
+// "volume" limit.
+func (e *Evaluator) Volume() *VolumeMeter { return e.volume }
+
+func (e *Evaluator) quotaInfoLocked() *QuotaInfo {
+	return &QuotaInfo{
+		Limit:     e.quotaMax,
+		Used:      e.quotaUsed,
+		Remaining: e.quotaMax - e.quotaUsed,
+		Window:    e.quotaWindow,
+		ResetAt:   e.quotaResetAt,
+	}
+}
+
+// rollQuotaLocked resets usage once the quota window has elapsed.
+func (e *Evaluator) rollQuotaLocked() {
+	if next, rolled := advanceWindow(e.quotaResetAt, e.quotaWindow, e.now()); rolled {
+		e.quotaResetAt = next
+		e.quotaUsed = 0
+	}
+}
+
+// advanceWindow moves resetAt past now for a daily or monthly window and
+// reports whether a window boundary was crossed. An unknown window resets
+// once and then stops rolling.
+func advanceWindow(resetAt time.Time, window string, now time.Time) (time.Time, bool) {
+	if resetAt.IsZero() || now.Before(resetAt) {
+		return resetAt, false
+	}
+	for !now.Before(resetAt) {
+		switch window {
+		case "daily":
+			resetAt = resetAt.AddDate(0, 0, 1)
+		case "monthly":
+			resetAt = resetAt.AddDate(0, 1, 0)
+		default:
+			return time.Time{}, true
+		}
+	}
+	return resetAt, true
+}
+
+// windowEnd returns the end of the daily or monthly window containing now
+// (zero for an unknown window).
+func windowEnd(window string, now time.Time) time.Time {
+	y, m, d := now.UTC().Date()
+	switch window {
+	case "daily":
+		return time.Date(y, m, d+1, 0, 0, 0, 0, time.UTC)
+	case "monthly":
+		return time.Date(y, m+1, 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return time.Time{}
+	}
+}
+
+func (e *Evaluator) loadLimits(limits map[string]interface{}) {
+	if q, ok := limits["quota"].(map[string]interface{}); ok {
+		e.quotaMax = intValue(q["max"])
+		e.quotaUsed = intValue(q["used"])
+		e.quotaWindow, _ = q["window"].(string)
+		if s, ok := q["reset_at"].(string); ok {
+			e.quotaResetAt, _ = time.Parse(time.RFC3339, s)
+		}
+	}
+	e.maxTPS = floatValue(limits["max_tps"])
+	e.maxCapacity = intValue(limits["max_capacity"])
+	e.maxConcurrency = intValue(limits["max_concurrency"])
+	if st, ok := limits["seats"].(map[string]interface{}); ok && intValue(st["max"]) > 0 {
+		cooldown := time.Duration(floatValue(st["reassign_cooldown_days"]) * float64(24*time.Hour))
+		e.seats = NewSeatPool(intValue(st["max"]), cooldown, e.now)
+	}
+	if fl, ok := limits["floating"].(map[string]interface{}); ok && intValue(fl["max"]) > 0 {
+		ttl := time.Duration(floatValue(fl["lease_ttl_seconds"]) * float64(time.Second))
+		e.leases = NewLeasePool(intValue(fl["max"]), ttl, e.now)
+	}
+	if v, ok := limits["volume"].(map[string]interface{}); ok && floatValue(v["max"]) > 0 {
+		unit, _ := v["unit"].(string)
+		window, _ := v["window"].(string)
+		e.volume = NewVolumeMeter(unit, int64(floatValue(v["max"])), window, e.now)
+	}
+}
+
+func floatValue(v interface{}) float64 {
+	switch n := v.(type) {
+	case int:
+		return float64(n)
+	case int64:
+		return float64(n)
+	case float64:
+		return n
+	case json.Number:
+		f, _ := n.Float64()
+		return f
+	default:
+		return 0
+	}
+}
+
+func intValue(v interface{}) int { return int(floatValue(v)) }
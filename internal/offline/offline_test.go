@@ -0,0 +1,167 @@
+package offline
+
+import (
+	"testing"
+	"time"
+)
+
+const proLicense = `{
+  "product_id": "data-insight-pro",
+  "tier": "professional",
+  "issued_at": "2025-01-21T00:00:00Z",
+  "expires_at": "2026-01-21T00:00:00Z",
+  "features": {
+    "pdf_export": {"enabled": true},
+    "excel_export": {"enabled": false}
+  },
+  "limits": {
+    "quota": {"max": 3, "used": 0, "remaining": 3, "window": "daily", "reset_at": "2025-06-02T00:00:00Z"},
+    "max_tps": 2.0,
+    "max_capacity": 2,
+    "max_concurrency": 1
+  }
+}`
+
+func TestEvaluator(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	ev, err := NewFromJSON([]byte(proLicense), WithClock(func() time.Time { return now }))
+	if err != nil {
+		t.Fatalf("NewFromJSON: %v", err)
+	}
+
+	st, _ := ev.CheckFeature("pdf_export")
+	if !st.Enabled || st.MaxTPS != 2 || st.Quota == nil || st.Quota.Limit != 3 {
+		t.Fatalf("unexpected pdf_export status: %+v", st)
+	}
+	if st, _ := ev.CheckFeature("excel_export"); st.Enabled || st.Reason != ReasonInsufficientTier {
+		t.Fatalf("excel_export should be denied by tier: %+v", st)
+	}
+	if st, _ := ev.CheckFeature("nope"); st.Reason != ReasonFeatureNotFound {
+		t.Fatalf("unknown feature reason = %q", st.Reason)
+	}
+
+	for i, want := range []bool{true, true, true, false} {
+		if allowed, _, _ := ev.Consume(1); allowed != want {
+			t.Fatalf("consume #%d allowed=%v, want %v", i+1, allowed, want)
+		}
+	}
+	now = now.Add(24 * time.Hour) // next daily window
+	if allowed, remaining, _ := ev.Consume(1); !allowed || remaining != 2 {
+		t.Fatalf("quota did not reset: allowed=%v remaining=%d", allowed, remaining)
+	}
+
+	for i, want := range []bool{true, true, false} {
+		if allowed, _, _ := ev.CheckTPS(); allowed != want {
+			t.Fatalf("tps call #%d allowed=%v, want %v", i+1, allowed, want)
+		}
+	}
+
+	if allowed, max, _ := ev.CheckCapacity(2); allowed || max != 2 {
+		t.Fatalf("capacity at limit should be denied")
+	}
+
+	release, ok, _ := ev.AcquireSlot()
+	if !ok {
+		t.Fatalf("first slot denied")
+	}
+	if _, ok, _ := ev.AcquireSlot(); ok {
+		t.Fatalf("second slot should be denied")
+	}
+	release()
+	release()
+	if ev.ActiveSlots() != 0 {
+		t.Fatalf("release is not idempotent: active=%d", ev.ActiveSlots())
+	}
+}
+
+func TestSeatPool(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	pool := NewSeatPool(2, 7*24*time.Hour, func() time.Time { return now })
+
+	expect := func(step string, gotOK bool, gotReason string, wantOK bool, wantReason string) {
+		t.Helper()
+		if gotOK != wantOK || gotReason != wantReason {
+			t.Fatalf("%s: got (%v, %s), want (%v, %s)", step, gotOK, gotReason, wantOK, wantReason)
+		}
+	}
+
+	ok, reason := pool.Assign("alice")
+	expect("assign alice", ok, reason, true, ReasonOK)
+	ok, reason = pool.Assign("bob")
+	expect("assign bob", ok, reason, true, ReasonOK)
+	ok, reason = pool.Assign("carol")
+	expect("pool full", ok, reason, false, ReasonSeatsExhausted)
+
+	ok, reason = pool.Revoke("bob")
+	expect("revoke bob", ok, reason, true, ReasonOK)
+	ok, reason = pool.Assign("carol")
+	expect("carol during cooldown", ok, reason, false, ReasonSeatCooldown)
+	ok, reason = pool.Assign("bob")
+	expect("bob reclaims seat", ok, reason, true, ReasonOK)
+	ok, reason = pool.Revoke("bob")
+	expect("revoke bob again", ok, reason, true, ReasonOK)
+
+	now = now.Add(7 * 24 * time.Hour)
+	ok, reason = pool.Assign("carol")
+	expect("carol after cooldown", ok, reason, true, ReasonOK)
+	ok, reason = pool.Revoke("dave")
+	expect("revoke unassigned", ok, reason, false, ReasonNotAssigned)
+
+	if st := pool.Status(); st.Used != 2 || st.Available != 0 || st.Assignments[0].UserID != "alice" {
+		t.Fatalf("unexpected status: %+v", st)
+	}
+}
+
+func TestLeasePool(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	pool := NewLeasePool(1, 30*time.Second, func() time.Time { return now })
+
+	a, ok, _ := pool.Checkout("instance-a", 0)
+	if !ok {
+		t.Fatalf("first checkout denied")
+	}
+	if _, ok, reason := pool.Checkout("instance-b", 0); ok || reason != ReasonLeasesExhausted {
+		t.Fatalf("second checkout = %v %s, want %s", ok, reason, ReasonLeasesExhausted)
+	}
+
+	now = now.Add(20 * time.Second)
+	if _, ok, _ := pool.Renew(a.ID); !ok {
+		t.Fatalf("renew within TTL denied")
+	}
+	now = now.Add(20 * time.Second) // 40s after checkout, 20s after renewal
+	if st := pool.Status(); st.InUse != 1 {
+		t.Fatalf("renewed lease was reclaimed: %+v", st)
+	}
+
+	// instance-a crashes: no more renewals, the lease is reclaimed after its TTL.
+	now = now.Add(30 * time.Second)
+	b, ok, _ := pool.Checkout("instance-b", 0)
+	if !ok {
+		t.Fatalf("checkout after reclaim denied")
+	}
+	if _, ok, reason := pool.Renew(a.ID); ok || reason != ReasonLeaseExpired {
+		t.Fatalf("renew of reclaimed lease = %v %s, want %s", ok, reason, ReasonLeaseExpired)
+	}
+	if ok, _ := pool.Checkin(b.ID); !ok {
+		t.Fatalf("checkin denied")
+	}
+	if st := pool.Status(); st.InUse != 0 || st.Reclaimed != 1 {
+		t.Fatalf("unexpected status: %+v", st)
+	}
+}
+
+func TestVolumeMeter(t *testing.T) {
+	now := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	meter := NewVolumeMeter(UnitRecords, 100, "daily", func() time.Time { return now })
+
+	if ok, info := meter.Consume(1<<20, 60); !ok || info.Used != 60 {
+		t.Fatalf("first run: ok=%v info=%+v", ok, info)
+	}
+	if ok, info := meter.Consume(1<<20, 50); ok || info.Remaining != 40 {
+		t.Fatalf("over-limit run: ok=%v info=%+v", ok, info)
+	}
+	now = now.Add(14 * time.Hour) // past midnight
+	if info := meter.Info(); info.Used != 0 || !info.ResetAt.Equal(time.Date(2025, 6, 3, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("window did not roll: %+v", info)
+	}
+}
@@ -0,0 +1,142 @@
+package offline
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Seat reasons.
+const (
+	ReasonSeatsExhausted  = "seats_exhausted"
+	ReasonSeatCooldown    = "seat_cooldown"
+	ReasonAlreadyAssigned = "already_assigned"
+	ReasonNotAssigned     = "not_assigned"
+)
+
+// SeatAssignment is a named user holding a seat.
+type SeatAssignment struct {
+	UserID     string    `json:"user_id"`
+	AssignedAt time.Time `json:"assigned_at"`
+}
+
+// CoolingSeat is a revoked seat that cannot be given to another user yet.
+type CoolingSeat struct {
+	UserID string    `json:"user_id"`
+	FreeAt time.Time `json:"free_at"`
+}
+
+// SeatStatus is a snapshot of a seat pool.
+type SeatStatus struct {
+	Max          int              `json:"max"`
+	Used         int              `json:"used"`
+	Cooling      int              `json:"cooling"`
+	Available    int              `json:"available"`
+	CooldownDays float64          `json:"cooldown_days"`
+	Assignments  []SeatAssignment `json:"assignments"`
+	CoolingSeats []CoolingSeat    `json:"cooling_seats,omitempty"`
+}
+
+// SeatPool tracks named-user seats. A revoked seat stays reserved for the
+// reassignment cooldown so seats cannot be rotated between users at will;
+// re-assigning the same user during the cooldown reclaims their seat.
+type SeatPool struct {
+	mu       sync.Mutex
+	max      int
+	cooldown time.Duration
+	now      func() time.Time
+	assigned map[string]time.Time // userID -> assigned at
+	cooling  map[string]time.Time // userID -> seat free at
+}
+
+// NewSeatPool returns a pool of max seats with the given reassignment cooldown.
+func NewSeatPool(max int, cooldown time.Duration, now func() time.Time) *SeatPool {
+	if now == nil {
+		now = time.Now
+	}
+	return &SeatPool{
+		max:      max,
+		cooldown: cooldown,
+		now:      now,
+		assigned: make(map[string]time.Time),
+		cooling:  make(map[string]time.Time),
+	}
+}
+
+// Assign gives userID a seat.
+func (p *SeatPool) Assign(userID string) (bool, string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.now()
+	p.expireLocked(now)
+
+	if _, ok := p.assigned[userID]; ok {
+		return true, ReasonAlreadyAssigned
+	}
+	if _, ok := p.cooling[userID]; ok {
+		delete(p.cooling, userID)
+		p.assigned[userID] = now
+		return true, ReasonOK
+	}
+	if len(p.assigned)+len(p.cooling) >= p.max {
+		if len(p.cooling) > 0 {
+			return false, ReasonSeatCooldown
+		}
+		return false, ReasonSeatsExhausted
+	}
+	p.assigned[userID] = now
+	return true, ReasonOK
+}
+
+// Revoke takes the seat back from userID.
+func (p *SeatPool) Revoke(userID string) (bool, string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.now()
+	p.expireLocked(now)
+
+	if _, ok := p.assigned[userID]; !ok {
+		return false, ReasonNotAssigned
+	}
+	delete(p.assigned, userID)
+	if p.cooldown > 0 {
+		p.cooling[userID] = now.Add(p.cooldown)
+	}
+	return true, ReasonOK
+}
+
+// Status returns the current assignments, sorted by user ID.
+func (p *SeatPool) Status() SeatStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.expireLocked(p.now())
+
+	st := SeatStatus{
+		Max:          p.max,
+		Used:         len(p.assigned),
+		Cooling:      len(p.cooling),
+		CooldownDays: p.cooldown.Hours() / 24,
+		Assignments:  make([]SeatAssignment, 0, len(p.assigned)),
+	}
+	st.Available = p.max - st.Used - st.Cooling
+	if st.Available < 0 {
+		st.Available = 0
+	}
+	for id, at := range p.assigned {
+		st.Assignments = append(st.Assignments, SeatAssignment{UserID: id, AssignedAt: at})
+	}
+	for id, at := range p.cooling {
+		st.CoolingSeats = append(st.CoolingSeats, CoolingSeat{UserID: id, FreeAt: at})
+	}
+	sort.Slice(st.Assignments, func(i, j int) bool { return st.Assignments[i].UserID < st.Assignments[j].UserID })
+	sort.Slice(st.CoolingSeats, func(i, j int) bool { return st.CoolingSeats[i].UserID < st.CoolingSeats[j].UserID })
+	return st
+}
+
+func (p *SeatPool) expireLocked(now time.Time) {
+	for id, freeAt := range p.cooling {
+		if !now.Before(freeAt) {
+			delete(p.cooling, id)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package offline
+
+import (
+	"sync"
+	"time"
+)
+
+// ReasonVolumeExceeded is returned when a volume window is used up.
+const ReasonVolumeExceeded = "volume_exceeded"
+
+// Volume units.
+const (
+	UnitBytes   = "bytes"
+	UnitRecords = "records"
+)
+
+// VolumeInfo reports used vs. allowed data volume in the current window.
+type VolumeInfo struct {
+	Unit      string    `json:"unit"`
+	Limit     int64     `json:"limit"`
+	Used      int64     `json:"used"`
+	Remaining int64     `json:"remaining"`
+	Window    string    `json:"window,omitempty"`
+	ResetAt   time.Time `json:"reset_at,omitempty"`
+}
+
+// VolumeMeter meters processed data (bytes or records) per daily or monthly
+// window. Callers report both measures and the meter charges its unit.
+type VolumeMeter struct {
+	mu      sync.Mutex
+	unit    string
+	max     int64
+	used    int64
+	window  string
+	resetAt time.Time
+	now     func() time.Time
+}
+
+// NewVolumeMeter returns a meter allowing max units per window. An empty unit
+// means bytes.
+func NewVolumeMeter(unit string, max int64, window string, now func() time.Time) *VolumeMeter {
+	if unit != UnitRecords {
+		unit = UnitBytes
+	}
+	if now == nil {
+		now = time.Now
+	}
+	return &VolumeMeter{unit: unit, max: max, window: window, now: now, resetAt: windowEnd(window, now())}
+}
+
+// Consume charges a run that processed bytes and records. A run that would
+// exceed the window is denied and not charged.
+func (m *VolumeMeter) Consume(bytes, records int64) (bool, VolumeInfo) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rollLocked()
+
+	amount := bytes
+	if m.unit == UnitRecords {
+		amount = records
+	}
+	if m.used+amount > m.max {
+		return false, m.infoLocked()
+	}
+	m.used += amount
+	return true, m.infoLocked()
+}
+
+// Info returns the current window usage.
+func (m *VolumeMeter) Info() VolumeInfo {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rollLocked()
+	return m.infoLocked()
+}
+
+func (m *VolumeMeter) rollLocked() {
+	if next, rolled := advanceWindow(m.resetAt, m.window, m.now()); rolled {
+		m.resetAt = next
+		m.used = 0
+	}
+}
+
+func (m *VolumeMeter) infoLocked() VolumeInfo {
+	return VolumeInfo{
+		Unit:      m.unit,
+		Limit:     m.max,
+		Used:      m.used,
+		Remaining: m.max - m.used,
+		Window:    m.window,
+		ResetAt:   m.resetAt,
+	}
+}
@@ -2,15 +2,40 @@ package reporting
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"sync"
 	"time"
 )
 
+var (
+	outMu sync.Mutex
+	out   io.Writer = os.Stdout
+)
+
+// SetOutput sets where the functions print their progress; nil restores stdout.
+func SetOutput(w io.Writer) {
+	outMu.Lock()
+	defer outMu.Unlock()
+	if w == nil {
+		w = os.Stdout
+	}
+	out = w
+}
+
+func output() io.Writer {
+	outMu.Lock()
+	defer outMu.Unlock()
+	return out
+}
+
 func Schedule(frequency, schedule string) {
-	fmt.Printf("  Scheduling %s report...\n", frequency)
+	w := output()
+	fmt.Fprintf(w, "  Scheduling %s report...\n", frequency)
 	time.Sleep(300 * time.Millisecond)
 	
-	fmt.Printf("  Schedule: %s\n", schedule)
-	fmt.Println("  Recipients: admin@example.com, manager@example.com")
-	fmt.Println("  Format: PDF with charts")
-	fmt.Println("  Report scheduled successfully!")
+	fmt.Fprintf(w, "  Schedule: %s\n", schedule)
+	fmt.Fprintln(w, "  Recipients: admin@example.com, manager@example.com")
+	fmt.Fprintln(w, "  Format: PDF with charts")
+	fmt.Fprintln(w, "  Report scheduled successfully!")
 }
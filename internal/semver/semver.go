@@ -0,0 +1,183 @@
+// Package semver parses and compares semantic versions, and evaluates the
+// min_version/max_version ranges features declare.
+package semver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ReasonVersionUnsupported is reported for a feature whose version range
+// does not include the registered product version.
+const ReasonVersionUnsupported = "version_unsupported"
+
+// Version is a parsed semantic version. Build metadata is ignored.
+type Version struct {
+	Major, Minor, Patch int
+	Pre                 []string
+}
+
+// Parse parses MAJOR.MINOR.PATCH[-PRERELEASE][+BUILD], with an optional
+// leading "v".
+func Parse(s string) (Version, error) {
+	var v Version
+	rest := strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexByte(rest, '+'); i >= 0 {
+		rest = rest[:i]
+	}
+	if i := strings.IndexByte(rest, '-'); i >= 0 {
+		v.Pre = strings.Split(rest[i+1:], ".")
+		rest = rest[:i]
+		for _, id := range v.Pre {
+			if id == "" {
+				return Version{}, fmt.Errorf("invalid version %q: empty pre-release identifier", s)
+			}
+		}
+	}
+	parts := strings.Split(rest, ".")
+	if len(parts) != 3 {
+		return Version{}, fmt.Errorf("invalid version %q: want MAJOR.MINOR.PATCH", s)
+	}
+	nums := [3]*int{&v.Major, &v.Minor, &v.Patch}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 || p == "" || (len(p) > 1 && p[0] == '0') {
+			return Version{}, fmt.Errorf("invalid version %q: bad number %q", s, p)
+		}
+		*nums[i] = n
+	}
+	return v, nil
+}
+
+// String formats v without a leading "v".
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if len(v.Pre) > 0 {
+		s += "-" + strings.Join(v.Pre, ".")
+	}
+	return s
+}
+
+// Compare returns -1, 0 or 1 as a is less than, equal to or greater than b,
+// using semver precedence (a pre-release sorts before its release).
+func Compare(a, b Version) int {
+	for _, d := range [3]int{a.Major - b.Major, a.Minor - b.Minor, a.Patch - b.Patch} {
+		if d != 0 {
+			return sign(d)
+		}
+	}
+	switch {
+	case len(a.Pre) == 0 && len(b.Pre) == 0:
+		return 0
+	case len(a.Pre) == 0:
+		return 1
+	case len(b.Pre) == 0:
+		return -1
+	}
+	for i := 0; i < len(a.Pre) && i < len(b.Pre); i++ {
+		if c := compareIdent(a.Pre[i], b.Pre[i]); c != 0 {
+			return c
+		}
+	}
+	return sign(len(a.Pre) - len(b.Pre))
+}
+
+// Range is an inclusive version range; an empty bound is open.
+type Range struct {
+	Min string `json:"min_version,omitempty"`
+	Max string `json:"max_version,omitempty"`
+}
+
+// IsZero reports whether the range has no bounds.
+func (r Range) IsZero() bool { return r.Min == "" && r.Max == "" }
+
+// Validate checks that both bounds parse and that min does not exceed max.
+func (r Range) Validate() error {
+	lo, hi, err := r.bounds()
+	if err != nil {
+		return err
+	}
+	if lo != nil && hi != nil && Compare(*lo, *hi) > 0 {
+		return fmt.Errorf("min_version %s is greater than max_version %s", r.Min, r.Max)
+	}
+	return nil
+}
+
+// Contains reports whether version lies within the range.
+func (r Range) Contains(version string) (bool, error) {
+	lo, hi, err := r.bounds()
+	if err != nil {
+		return false, err
+	}
+	v, err := Parse(version)
+	if err != nil {
+		return false, err
+	}
+	if lo != nil && Compare(v, *lo) < 0 {
+		return false, nil
+	}
+	if hi != nil && Compare(v, *hi) > 0 {
+		return false, nil
+	}
+	return true, nil
+}
+
+// String describes the range, e.g. ">= 2.0.0, <= 3.0.0".
+func (r Range) String() string {
+	var parts []string
+	if r.Min != "" {
+		parts = append(parts, ">= "+r.Min)
+	}
+	if r.Max != "" {
+		parts = append(parts, "<= "+r.Max)
+	}
+	if len(parts) == 0 {
+		return "any"
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (r Range) bounds() (lo, hi *Version, err error) {
+	if r.Min != "" {
+		v, err := Parse(r.Min)
+		if err != nil {
+			return nil, nil, fmt.Errorf("min_version: %w", err)
+		}
+		lo = &v
+	}
+	if r.Max != "" {
+		v, err := Parse(r.Max)
+		if err != nil {
+			return nil, nil, fmt.Errorf("max_version: %w", err)
+		}
+		hi = &v
+	}
+	return lo, hi, nil
+}
+
+// compareIdent compares pre-release identifiers: numeric identifiers
+// compare numerically and sort before alphanumeric ones.
+func compareIdent(a, b string) int {
+	an, aErr := strconv.Atoi(a)
+	bn, bErr := strconv.Atoi(b)
+	switch {
+	case aErr == nil && bErr == nil:
+		return sign(an - bn)
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,35 @@
+package semver
+
+import "testing"
+
+func TestCompareAndRange(t *testing.T) {
+	order := []string{"0.9.0", "1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-beta", "1.0.0", "v1.2.0", "1.10.0", "2.0.0+build.5"}
+	for i := 1; i < len(order); i++ {
+		a, err := Parse(order[i-1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := Parse(order[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if Compare(a, b) >= 0 || Compare(b, a) <= 0 {
+			t.Errorf("expected %s < %s", order[i-1], order[i])
+		}
+	}
+	for _, bad := range []string{"1.0", "1.0.x", "01.0.0", "1.0.0-"} {
+		if _, err := Parse(bad); err == nil {
+			t.Errorf("Parse(%q) succeeded", bad)
+		}
+	}
+
+	r := Range{Min: "1.2.0", Max: "2.0.0"}
+	for v, want := range map[string]bool{"1.1.9": false, "1.2.0": true, "2.0.0": true, "2.0.1": false, "2.0.0-rc.1": true} {
+		if got, err := r.Contains(v); err != nil || got != want {
+			t.Errorf("Contains(%s) = %v, %v; want %v", v, got, err, want)
+		}
+	}
+	if err := (Range{Min: "3.0.0", Max: "2.0.0"}).Validate(); err == nil {
+		t.Error("inverted range validated")
+	}
+}
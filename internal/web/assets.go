@@ -0,0 +1,154 @@
+package web
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// embeddedStatic holds the SPA: static/index.html and the assets it loads
+// from /static/.
+//
+//go:embed static
+var embeddedStatic embed.FS
+
+// Cache-Control values of the SPA responses.
+const (
+	cacheImmutable  = "public, max-age=31536000, immutable" // content-hashed URLs
+	cacheRevalidate = "no-cache"                            // index.html, unhashed URLs
+	assetHashLen    = 10                                    // hex digits in hashed file names
+	indexFile       = "index.html"
+	staticURLPrefix = "/static/"
+)
+
+// asset is an embedded file with its content hash.
+type asset struct {
+	data []byte
+	etag string
+}
+
+// assets serves the SPA. The embedded assets are also served under
+// content-hashed names (css/styles.<hash>.css), which index.html refers to
+// and which are cached for a year; index.html and the plain names are
+// revalidated by ETag. With a static directory the files are read from
+// disk on every request and revalidated, for frontend development.
+type assets struct {
+	dir    string            // static directory; "" serves the embedded assets
+	files  map[string]*asset // name -> embedded file
+	hashed map[string]string // hashed name -> name
+	index  *asset            // index.html with the hashed references
+}
+
+// newAssets returns the SPA assets from dir, or the embedded ones if dir is
+// empty.
+func newAssets(dir string) *assets {
+	if dir != "" {
+		if _, err := os.Stat(filepath.Join(dir, indexFile)); err != nil {
+			log.Printf("Warning: static dir %s: %v", dir, err)
+		}
+		log.Printf("Serving UI from %s", dir)
+		return &assets{dir: dir}
+	}
+	a, err := loadEmbedded(embeddedStatic, "static")
+	if err != nil {
+		panic("web: embedded assets: " + err.Error())
+	}
+	return a
+}
+
+// loadEmbedded reads the assets under root in fsys and hashes them.
+func loadEmbedded(fsys fs.FS, root string) (*assets, error) {
+	a := &assets{files: map[string]*asset{}, hashed: map[string]string{}}
+	var refs []string // "/static/name", "/static/hashed name" pairs for index.html
+	err := fs.WalkDir(fsys, root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+		name := strings.TrimPrefix(p, root+"/")
+		f := newAsset(data)
+		a.files[name] = f
+		if name != indexFile {
+			h := hashedName(name, f.etag[1:1+assetHashLen])
+			a.hashed[h] = name
+			refs = append(refs, staticURLPrefix+name+`"`, staticURLPrefix+h+`"`)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	index, ok := a.files[indexFile]
+	if !ok {
+		return nil, fs.ErrNotExist
+	}
+	a.index = newAsset([]byte(strings.NewReplacer(refs...).Replace(string(index.data))))
+	return a, nil
+}
+
+func newAsset(data []byte) *asset {
+	sum := sha256.Sum256(data)
+	return &asset{data: data, etag: `"` + hex.EncodeToString(sum[:]) + `"`}
+}
+
+// hashedName inserts hash before the extension of name: js/app.js ->
+// js/app.<hash>.js.
+func hashedName(name, hash string) string {
+	ext := path.Ext(name)
+	return strings.TrimSuffix(name, ext) + "." + hash + ext
+}
+
+// serveIndex serves index.html.
+func (a *assets) serveIndex(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", cacheRevalidate)
+	if a.dir != "" {
+		http.ServeFile(w, r, filepath.Join(a.dir, indexFile))
+		return
+	}
+	serveAsset(w, r, indexFile, a.index)
+}
+
+// handler serves /static/.
+func (a *assets) handler() http.Handler {
+	if a.dir != "" {
+		files := http.StripPrefix(staticURLPrefix, http.FileServer(http.Dir(a.dir)))
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Cache-Control", cacheRevalidate)
+			files.ServeHTTP(w, r)
+		})
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, staticURLPrefix)
+		if orig, ok := a.hashed[name]; ok {
+			w.Header().Set("Cache-Control", cacheImmutable)
+			serveAsset(w, r, orig, a.files[orig])
+			return
+		}
+		f, ok := a.files[name]
+		if !ok || name == indexFile {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Cache-Control", cacheRevalidate)
+		serveAsset(w, r, name, f)
+	})
+}
+
+// serveAsset writes f, answering If-None-Match with 304. The content type
+// follows the extension of name.
+func serveAsset(w http.ResponseWriter, r *http.Request, name string, f *asset) {
+	w.Header().Set("ETag", f.etag)
+	http.ServeContent(w, r, name, time.Time{}, bytes.NewReader(f.data))
+}
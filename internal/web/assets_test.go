@@ -0,0 +1,166 @@
+package web
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+const testIndex = `<link rel="stylesheet" href="/static/css/styles.css">
+<script src="/static/js/app.js"></script>
+`
+
+func testAssets(t *testing.T) *assets {
+	t.Helper()
+	a, err := loadEmbedded(fstest.MapFS{
+		"static/index.html":     {Data: []byte(testIndex)},
+		"static/css/styles.css": {Data: []byte("body { margin: 0 }")},
+		"static/js/app.js":      {Data: []byte("console.log('app')")},
+	}, "static")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+// getAsset serves path from a (index.html for "/") with the given request headers.
+func getAsset(a *assets, path string, header ...string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, path, nil)
+	for i := 0; i+1 < len(header); i += 2 {
+		r.Header.Set(header[i], header[i+1])
+	}
+	w := httptest.NewRecorder()
+	if path == "/" {
+		a.serveIndex(w, r)
+	} else {
+		a.handler().ServeHTTP(w, r)
+	}
+	return w
+}
+
+func contentHash(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])[:assetHashLen]
+}
+
+func TestAssetsHashedNames(t *testing.T) {
+	a := testAssets(t)
+	css := "/static/css/styles." + contentHash("body { margin: 0 }") + ".css"
+	js := "/static/js/app." + contentHash("console.log('app')") + ".js"
+
+	index := getAsset(a, "/").Body.String()
+	for _, ref := range []string{`href="` + css + `"`, `src="` + js + `"`} {
+		if !strings.Contains(index, ref) {
+			t.Errorf("index.html lacks %s:\n%s", ref, index)
+		}
+	}
+	if strings.Contains(index, "/static/css/styles.css") || strings.Contains(index, "/static/js/app.js") {
+		t.Errorf("index.html still refers to unhashed names:\n%s", index)
+	}
+
+	w := getAsset(a, css)
+	if w.Code != http.StatusOK || w.Body.String() != "body { margin: 0 }" || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/css") {
+		t.Errorf("GET %s = %d %q (%s)", css, w.Code, w.Body.String(), w.Header().Get("Content-Type"))
+	}
+	for _, path := range []string{"/static/index.html", "/static/css/styles.0000000000.css", "/static/nope.js"} {
+		if code := getAsset(a, path).Code; code != http.StatusNotFound {
+			t.Errorf("GET %s = %d, want 404", path, code)
+		}
+	}
+}
+
+func TestAssetsCacheControl(t *testing.T) {
+	a := testAssets(t)
+	for _, tc := range []struct {
+		path, cache string
+	}{
+		{"/", cacheRevalidate},
+		{"/static/css/styles.css", cacheRevalidate},
+		{"/static/css/styles." + contentHash("body { margin: 0 }") + ".css", cacheImmutable},
+		{"/static/js/app." + contentHash("console.log('app')") + ".js", cacheImmutable},
+	} {
+		w := getAsset(a, tc.path)
+		if w.Code != http.StatusOK || w.Header().Get("Cache-Control") != tc.cache {
+			t.Errorf("GET %s = %d, Cache-Control %q, want %q", tc.path, w.Code, w.Header().Get("Cache-Control"), tc.cache)
+		}
+		if w.Header().Get("ETag") == "" {
+			t.Errorf("GET %s: no ETag", tc.path)
+		}
+	}
+}
+
+func TestAssetsNotModified(t *testing.T) {
+	a := testAssets(t)
+	for _, path := range []string{"/", "/static/js/app.js", "/static/js/app." + contentHash("console.log('app')") + ".js"} {
+		etag := getAsset(a, path).Header().Get("ETag")
+		if w := getAsset(a, path, "If-None-Match", etag); w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+			t.Errorf("GET %s with If-None-Match %s = %d, want 304", path, etag, w.Code)
+		}
+		if w := getAsset(a, path, "If-None-Match", `"stale"`); w.Code != http.StatusOK {
+			t.Errorf("GET %s with a stale ETag = %d, want 200", path, w.Code)
+		}
+	}
+}
+
+func TestAssetsStaticDir(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) {
+		t.Helper()
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("index.html", testIndex)
+	write("css/styles.css", "body { margin: 0 }")
+	a := newAssets(dir)
+
+	// Files come from disk as they are: no hashed names, always revalidated
+	if w := getAsset(a, "/"); w.Body.String() != testIndex || w.Header().Get("Cache-Control") != cacheRevalidate {
+		t.Errorf("GET / = %q, Cache-Control %q", w.Body.String(), w.Header().Get("Cache-Control"))
+	}
+	if w := getAsset(a, "/static/css/styles.css"); w.Body.String() != "body { margin: 0 }" || w.Header().Get("Cache-Control") != cacheRevalidate {
+		t.Errorf("GET styles.css = %q, Cache-Control %q", w.Body.String(), w.Header().Get("Cache-Control"))
+	}
+
+	// Edits show up on the next request
+	write("css/styles.css", "body { margin: 1em }")
+	if body := getAsset(a, "/static/css/styles.css").Body.String(); body != "body { margin: 1em }" {
+		t.Errorf("GET styles.css after an edit = %q", body)
+	}
+	if code := getAsset(a, "/static/css/styles."+contentHash("body { margin: 0 }")+".css").Code; code != http.StatusNotFound {
+		t.Errorf("hashed name in static dir = %d, want 404", code)
+	}
+
+	// WithStaticDir (--static-dir) replaces the embedded UI in the router
+	srv := newTestServer(t, WithStaticDir(dir))
+	for path, want := range map[string]string{"/": testIndex, "/static/css/styles.css": "body { margin: 1em }"} {
+		w := httptest.NewRecorder()
+		srv.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusOK || w.Body.String() != want {
+			t.Errorf("server GET %s = %d %q, want %q", path, w.Code, w.Body.String(), want)
+		}
+	}
+}
+
+func TestEmbeddedAssets(t *testing.T) {
+	a := newAssets("")
+	index := getAsset(a, "/").Body.String()
+	if strings.Contains(index, `"/static/js/app.js"`) || !strings.Contains(index, "/static/js/app.") {
+		t.Errorf("embedded index.html refers to unhashed app.js")
+	}
+	for hashed := range a.hashed {
+		if w := getAsset(a, staticURLPrefix+hashed); w.Code != http.StatusOK || w.Header().Get("Cache-Control") != cacheImmutable {
+			t.Errorf("GET %s = %d, Cache-Control %q", hashed, w.Code, w.Header().Get("Cache-Control"))
+		}
+	}
+}
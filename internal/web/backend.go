@@ -0,0 +1,129 @@
+package web
+
+import (
+	"fmt"
+	"sort"
+
+	"demo-app/internal/offline"
+
+	lccclient "github.com/yourorg/lcc-sdk/pkg/client"
+)
+
+// LicenseBackend is what the /api/sim/{product}/* handlers need from a
+// licensing client. *lccclient.Client satisfies it directly; offlineBackend
+// adapts an in-process offline.Evaluator.
+type LicenseBackend interface {
+	GetInstanceID() string
+	CheckFeature(featureID string) (*lccclient.FeatureStatus, error)
+	Consume(amount int) (bool, int, error)
+	CheckTPS() (bool, float64, error)
+	CheckCapacity(current int) (bool, int, error)
+	AcquireSlot() (func(), bool, error)
+}
+
+// ServerOption configures a Server.
+type ServerOption func(*Server)
+
+// WithOfflineEvaluator backs /api/sim/{product}/* with an offline.Evaluator
+// built from the tier license of that product instead of a client
+// registered against an LCC server.
+func WithOfflineEvaluator() ServerOption {
+	return func(s *Server) { s.offline = true }
+}
+
+// offlineBackend adapts offline.Evaluator results to the SDK result types.
+type offlineBackend struct {
+	*offline.Evaluator
+}
+
+func (b offlineBackend) CheckFeature(featureID string) (*lccclient.FeatureStatus, error) {
+	st, err := b.Evaluator.CheckFeature(featureID)
+	if err != nil {
+		return nil, err
+	}
+	out := &lccclient.FeatureStatus{
+		Enabled:        st.Enabled,
+		Reason:         st.Reason,
+		MaxCapacity:    st.MaxCapacity,
+		MaxTPS:         st.MaxTPS,
+		MaxConcurrency: st.MaxConcurrency,
+	}
+	if st.Quota != nil {
+		out.Quota = &lccclient.QuotaInfo{
+			Limit:     st.Quota.Limit,
+			Used:      st.Quota.Used,
+			Remaining: st.Quota.Remaining,
+		}
+	}
+	return out, nil
+}
+
+// tierForProduct returns the tier whose license is issued for productID.
+func tierForProduct(productID string) *TierDefinition {
+	for _, t := range AllTiers {
+		if t.ProductID == productID {
+			return t
+		}
+	}
+	return GetTierByID(productID)
+}
+
+// tierFeatures lists the features defined by tier (nil for a nil tier).
+func tierFeatures(tier *TierDefinition) []PublicFeature {
+	if tier == nil {
+		return nil
+	}
+	out := make([]PublicFeature, 0, len(tier.Features))
+	for _, f := range tier.Features {
+		out = append(out, PublicFeature{ID: f.ID, Name: f.Name})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+// offlineEvaluator returns (creating on first use) the evaluator for productID.
+func (s *Server) offlineEvaluator(productID string) (*offline.Evaluator, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ev, ok := s.offlineEvals[productID]; ok {
+		return ev, nil
+	}
+	tier := tierForProduct(productID)
+	if tier == nil {
+		return nil, fmt.Errorf("no offline license for product: %s", productID)
+	}
+	// Issue against the simulated clock so /api/sim/clock drives both the
+	// license lifetime and the evaluator's quota windows.
+	doc := NewLicenseDocument(tier, "offline-demo", s.now(), DefaultLicenseValidity)
+	ev := offline.New(doc, offline.WithClock(s.now))
+	s.offlineEvals[productID] = ev
+	return ev, nil
+}
+
+// licenseBackend resolves the backend of productID: its offline evaluator,
+// or its registered client.
+func (s *Server) licenseBackend(productID string) (LicenseBackend, error) {
+	if s.offline {
+		ev, err := s.offlineEvaluator(productID)
+		if err != nil {
+			return nil, err
+		}
+		return offlineBackend{ev}, nil
+	}
+	cli, err := s.getClient(productID)
+	if err != nil {
+		return nil, err
+	}
+	return cli, nil
+}
+
+// simBackend resolves the backend serving /api/sim/{product}/*: the
+// license backend with tier trials applied and its calls recorded in
+// sdkMetrics.
+func (s *Server) simBackend(productID string) (LicenseBackend, error) {
+	b, err := s.licenseBackend(productID)
+	if err != nil {
+		return nil, err
+	}
+	return meteredBackend{trialBackend{b, s, productID}, productID}, nil
+}
@@ -0,0 +1,60 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"demo-app/internal/deps"
+)
+
+// handleTierDependencies returns the requires/conflicts_with graph of a tier.
+func (s *Server) handleTierDependencies(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	tierID := extractTierFromPath(r.URL.Path, "/api/tiers/", "/dependencies")
+	if tierID == "" {
+		writeErr(w, http.StatusBadRequest, fmt.Errorf("invalid path: want /api/tiers/{tier}/dependencies"))
+		return
+	}
+
+	tier := GetTierByID(tierID)
+	if tier == nil {
+		writeErr(w, http.StatusNotFound, fmt.Errorf("unknown tier %q", tierID))
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(TierDependencyGraph(tier))
+}
+
+type manifestGraph struct {
+	Path      string     `json:"path"`
+	ProductID string     `json:"product_id,omitempty"`
+	Graph     deps.Graph `json:"graph"`
+	Error     string     `json:"error,omitempty"`
+}
+
+// handleManifestDependencies returns the dependency graph of every local
+// manifest. Invalid relations are reported in the graph's errors rather than
+// failing the request.
+func (s *Server) handleManifestDependencies(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	out := []manifestGraph{}
+	for _, e := range s.manifests.list() {
+		g := manifestGraph{Path: e.Path, ProductID: e.ProductID, Error: e.Error}
+		if e.Manifest != nil {
+			g.Graph = deps.Build(e.Manifest.DependencyNodes())
+		}
+		out = append(out, g)
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
@@ -0,0 +1,42 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"demo-app/internal/deps"
+)
+
+func TestTierDependencies(t *testing.T) {
+	s := newTestServer(t, WithOfflineEvaluator())
+
+	rec := httptest.NewRecorder()
+	s.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/tiers/basic/dependencies", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("basic = %d: %s", rec.Code, rec.Body)
+	}
+	var g deps.Graph
+	if err := json.Unmarshal(rec.Body.Bytes(), &g); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the known tiers are routed; the handler still rejects other
+	// paths on its own.
+	for _, tc := range []struct {
+		path string
+		code int
+		want string
+	}{
+		{"/api/tiers/gold/dependencies", http.StatusNotFound, `unknown tier \"gold\"`},
+		{"/api/tiers/basic/graph", http.StatusBadRequest, "invalid path"},
+	} {
+		rec := httptest.NewRecorder()
+		s.handleTierDependencies(rec, httptest.NewRequest(http.MethodGet, tc.path, nil))
+		if rec.Code != tc.code || !strings.Contains(rec.Body.String(), tc.want) {
+			t.Errorf("%s = %d %s, want %d %s", tc.path, rec.Code, rec.Body, tc.code, tc.want)
+		}
+	}
+}
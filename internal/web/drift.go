@@ -0,0 +1,155 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// driftReport compares the local manifest of a product with the features the
+// LCC server knows about.
+type driftReport struct {
+	ProductID      string          `json:"product_id"`
+	ManifestPath   string          `json:"manifest_path,omitempty"`
+	NoManifest     bool            `json:"no_manifest"`
+	OnServer       bool            `json:"on_server"`
+	OnlyInManifest []PublicFeature `json:"only_in_manifest"`
+	OnlyOnServer   []PublicFeature `json:"only_on_server"`
+	InBoth         []PublicFeature `json:"in_both"`
+	InSync         bool            `json:"in_sync"`
+	Error          string          `json:"error,omitempty"`
+}
+
+// handleProductsRoot dispatches /api/products/drift and
+// /api/products/{id}/drift.
+func (s *Server) handleProductsRoot(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/products/"), "/"), "/")
+	switch {
+	case len(parts) == 1 && parts[0] == "drift":
+		s.handleAllDrift(w, r)
+	case len(parts) == 2 && parts[0] != "" && parts[1] == "drift":
+		s.handleProductDrift(parts[0], w, r)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// publicClient returns a client of the public API of the configured LCC
+// server.
+func (s *Server) publicClient() (*PublicServiceClient, error) {
+	s.mu.RLock()
+	lccURL := s.lccURL
+	s.mu.RUnlock()
+	if lccURL == "" {
+		return nil, fmt.Errorf("lcc_url not configured; POST /api/config first")
+	}
+	return lccPublicClient(lccURL), nil
+}
+
+// lccPublicClient returns a client of the public API of the LCC server at
+// lccURL.
+func lccPublicClient(lccURL string) *PublicServiceClient {
+	return NewPublicServiceClient(strings.TrimRight(lccURL, "/") + "/api/v1/public")
+}
+
+func (s *Server) handleProductDrift(productID string, w http.ResponseWriter, r *http.Request) {
+	pc, err := s.publicClient()
+	if err != nil {
+		writeErr(w, http.StatusBadRequest, err)
+		return
+	}
+	serverFeatures, err := pc.ListFeatures(r.Context(), productID)
+	if err != nil {
+		writeErr(w, http.StatusBadGateway, fmt.Errorf("failed to fetch features: %w", err))
+		return
+	}
+	_ = json.NewEncoder(w).Encode(s.driftFor(productID, serverFeatures, true))
+}
+
+// handleAllDrift reports drift for every product on the server plus every
+// local manifest whose product the server does not list.
+func (s *Server) handleAllDrift(w http.ResponseWriter, r *http.Request) {
+	pc, err := s.publicClient()
+	if err != nil {
+		writeErr(w, http.StatusBadRequest, err)
+		return
+	}
+	products, err := pc.ListProducts(r.Context())
+	if err != nil {
+		writeErr(w, http.StatusBadGateway, fmt.Errorf("failed to fetch products: %w", err))
+		return
+	}
+
+	out := make([]driftReport, 0, len(products))
+	seen := map[string]bool{}
+	for _, p := range products {
+		seen[p.ID] = true
+		out = append(out, s.productDrift(r.Context(), pc, p.ID))
+	}
+	for _, m := range s.manifests.loaded() {
+		if pid := m.ProductID; pid != "" && !seen[pid] {
+			seen[pid] = true
+			out = append(out, s.driftFor(pid, nil, false))
+		}
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+func (s *Server) productDrift(ctx context.Context, pc *PublicServiceClient, productID string) driftReport {
+	serverFeatures, err := pc.ListFeatures(ctx, productID)
+	if err != nil {
+		rep := s.driftFor(productID, nil, true)
+		rep.InSync = false
+		rep.Error = err.Error()
+		return rep
+	}
+	return s.driftFor(productID, serverFeatures, true)
+}
+
+// driftFor compares the local manifest of productID with serverFeatures.
+func (s *Server) driftFor(productID string, serverFeatures []PublicFeature, onServer bool) driftReport {
+	rep := driftReport{
+		ProductID:      productID,
+		OnServer:       onServer,
+		OnlyInManifest: []PublicFeature{},
+		OnlyOnServer:   []PublicFeature{},
+		InBoth:         []PublicFeature{},
+	}
+	local := map[string]PublicFeature{}
+	if m, ok := s.manifests.forProduct(productID); ok {
+		rep.ManifestPath = m.Path
+		for _, f := range m.Manifest.Features {
+			local[f.ID] = PublicFeature{ID: f.ID, Name: f.Name}
+		}
+	} else {
+		rep.NoManifest = true
+	}
+
+	remote := map[string]bool{}
+	for _, f := range serverFeatures {
+		remote[f.ID] = true
+		if _, ok := local[f.ID]; ok {
+			rep.InBoth = append(rep.InBoth, f)
+		} else {
+			rep.OnlyOnServer = append(rep.OnlyOnServer, f)
+		}
+	}
+	for id, f := range local {
+		if !remote[id] {
+			rep.OnlyInManifest = append(rep.OnlyInManifest, f)
+		}
+	}
+	for _, list := range [][]PublicFeature{rep.OnlyInManifest, rep.OnlyOnServer, rep.InBoth} {
+		sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	}
+	rep.InSync = onServer && !rep.NoManifest && len(rep.OnlyInManifest) == 0 && len(rep.OnlyOnServer) == 0
+	return rep
+}
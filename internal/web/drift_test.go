@@ -0,0 +1,117 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testDriftManifest = `sdk:
+  product_id: %s
+features:
+%s`
+
+// testDriftServer serves the public API of an LCC server knowing synced,
+// app and server-only, next to local manifests for synced, app and local.
+func testDriftServer(t *testing.T) *Server {
+	t.Helper()
+	serverFeatures := map[string][]PublicFeature{
+		"synced":      {{ID: "pdf_export"}},
+		"app":         {{ID: "pdf_export"}, {ID: "cloud_sync"}},
+		"server-only": {{ID: "pdf_export"}},
+	}
+	lcc := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/api/v1/public/products")
+		if path == "" {
+			_ = json.NewEncoder(w).Encode([]PublicProduct{{ID: "synced"}, {ID: "app"}, {ID: "server-only"}})
+			return
+		}
+		features, ok := serverFeatures[strings.TrimSuffix(strings.TrimPrefix(path, "/"), "/features")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(features)
+	}))
+	t.Cleanup(lcc.Close)
+
+	dir := t.TempDir()
+	for product, features := range map[string]string{
+		"synced": "  - id: pdf_export\n",
+		"app":    "  - id: pdf_export\n  - id: local_only\n",
+		"local":  "  - id: pdf_export\n",
+	} {
+		writeTestFile(t, filepath.Join(dir, product+".yaml"), fmt.Sprintf(testDriftManifest, product, features))
+	}
+	return newTestServer(t, WithLCCURL(lcc.URL), WithManifestPaths([]string{filepath.Join(dir, "*.yaml")}))
+}
+
+func featureList(fs []PublicFeature) string {
+	ids := make([]string, len(fs))
+	for i, f := range fs {
+		ids[i] = f.ID
+	}
+	return strings.Join(ids, ",")
+}
+
+func getDrift(t *testing.T, s *Server, path string, out any) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	s.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s = %d: %s", path, rec.Code, rec.Body)
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProductDrift(t *testing.T) {
+	s := testDriftServer(t)
+
+	var rep driftReport
+	getDrift(t, s, "/api/products/app/drift", &rep)
+	if rep.InSync || rep.NoManifest || !rep.OnServer {
+		t.Errorf("app = %+v, want out of sync with a manifest", rep)
+	}
+	if got := featureList(rep.OnlyInManifest); got != "local_only" {
+		t.Errorf("only in manifest = %s, want local_only", got)
+	}
+	if got := featureList(rep.OnlyOnServer); got != "cloud_sync" {
+		t.Errorf("only on server = %s, want cloud_sync", got)
+	}
+	if got := featureList(rep.InBoth); got != "pdf_export" {
+		t.Errorf("in both = %s, want pdf_export", got)
+	}
+}
+
+func TestAllDrift(t *testing.T) {
+	s := testDriftServer(t)
+
+	var reps []driftReport
+	getDrift(t, s, "/api/products/drift", &reps)
+	byProduct := map[string]driftReport{}
+	for _, rep := range reps {
+		byProduct[rep.ProductID] = rep
+	}
+	if len(byProduct) != 4 {
+		t.Fatalf("%d reports, want synced, app, server-only and local: %+v", len(reps), reps)
+	}
+
+	if rep := byProduct["synced"]; !rep.InSync || featureList(rep.InBoth) != "pdf_export" {
+		t.Errorf("synced = %+v, want in sync", rep)
+	}
+	if rep := byProduct["app"]; rep.InSync {
+		t.Errorf("app = %+v, want out of sync", rep)
+	}
+	if rep := byProduct["server-only"]; !rep.NoManifest || rep.InSync || featureList(rep.OnlyOnServer) != "pdf_export" {
+		t.Errorf("server-only = %+v, want no_manifest with pdf_export only on the server", rep)
+	}
+	if rep := byProduct["local"]; rep.OnServer || rep.InSync || featureList(rep.OnlyInManifest) != "pdf_export" {
+		t.Errorf("local = %+v, want not on the server with pdf_export only in the manifest", rep)
+	}
+}
@@ -2,43 +2,33 @@ package web
 
 import (
 	"os"
-
-	lccconfig "github.com/yourorg/lcc-sdk/pkg/config"
 )
 
-var candidateManifests = []string{
-	"lcc-features.yaml",
-	"configs/lcc-features.basic.yaml",
-	"configs/lcc-features.pro.yaml",
-	"configs/lcc-features.ent.yaml",
-}
+// Where the feature list of a status response came from.
+const (
+	featureSourceManifest = "manifest" // the product's own manifest
+	featureSourceTier     = "tier"     // the tier definition (offline mode)
+	featureSourceUnion    = "union"    // no manifest; union of all manifests
+	featureSourceLCC      = "lcc"      // the LCC server's feature list
+)
 
-// LoadFeaturesForProduct loads features from local manifests matching sdk.product_id.
-func LoadFeaturesForProduct(productID string) ([]PublicFeature, error) {
-	for _, p := range candidateManifests {
-		if !fileExists(p) { continue }
-		mf, err := lccconfig.LoadManifest(p)
-		if err != nil { continue }
-		if mf.SDK.ProductID == productID {
-			out := make([]PublicFeature, 0, len(mf.Features))
-			for _, f := range mf.Features {
-				out = append(out, PublicFeature{ID: f.ID, Name: f.Name})
-			}
-			return out, nil
-		}
+// loadFeaturesForProduct loads features from the local manifest indexed under sdk.product_id.
+func (m *manifestStore) loadFeaturesForProduct(productID string) ([]PublicFeature, error) {
+	e, ok := m.forProduct(productID)
+	if !ok { return nil, nil }
+	out := make([]PublicFeature, 0, len(e.Manifest.Features))
+	for _, f := range e.Manifest.Features {
+		out = append(out, PublicFeature{ID: f.ID, Name: f.Name})
 	}
-	return nil, nil
+	return out, nil
 }
 
-// LoadFeatureUnion returns the union of all features across local manifests.
-func LoadFeatureUnion() ([]PublicFeature, error) {
+// loadFeatureUnion returns the union of all features across local manifests.
+func (m *manifestStore) loadFeatureUnion() ([]PublicFeature, error) {
 	seen := map[string]bool{}
 	var out []PublicFeature
-	for _, p := range candidateManifests {
-		if !fileExists(p) { continue }
-		mf, err := lccconfig.LoadManifest(p)
-		if err != nil { continue }
-		for _, f := range mf.Features {
+	for _, e := range m.loaded() {
+		for _, f := range e.Manifest.Features {
 			if seen[f.ID] { continue }
 			seen[f.ID] = true
 			out = append(out, PublicFeature{ID: f.ID, Name: f.Name})
@@ -50,4 +40,4 @@ func LoadFeatureUnion() ([]PublicFeature, error) {
 func fileExists(path string) bool {
 	st, err := os.Stat(path)
 	return err == nil && !st.IsDir()
-}
\ No newline at end of file
+}
@@ -0,0 +1,96 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"demo-app/internal/health"
+
+	lccclient "github.com/yourorg/lcc-sdk/pkg/client"
+)
+
+const (
+	readyTimeout   = 3 * time.Second // bounds the readiness checks of one /readyz request
+	simStopTimeout = 5 * time.Second // bounds the wait for stopped simulations in Close
+)
+
+// handleHealthz reports that the process serves requests.
+func (s *Server) handleHealthz() http.Handler { return health.Live() }
+
+// handleReadyz reports whether simulations can run: the server is not
+// shutting down, LCC answers and at least one product is registered.
+// Offline mode needs neither LCC nor registrations.
+func (s *Server) handleReadyz() http.Handler {
+	return health.Ready(readyTimeout, s.checkShutdown, s.checkLCC, s.checkClients)
+}
+
+func (s *Server) checkShutdown(context.Context) health.Check {
+	if s.closing.Load() {
+		return health.Check{Name: "shutdown", Detail: "shutting down"}
+	}
+	return health.Check{Name: "shutdown", OK: true}
+}
+
+func (s *Server) checkLCC(ctx context.Context) health.Check {
+	if s.offline {
+		return health.Check{Name: "lcc", OK: true, Detail: "offline mode"}
+	}
+	s.mu.RLock()
+	lccURL := s.lccURL
+	s.mu.RUnlock()
+	if lccURL == "" {
+		lccURL = "http://localhost:7086"
+	}
+	return health.Reachable("lcc", lccURL)(ctx)
+}
+
+func (s *Server) checkClients(context.Context) health.Check {
+	if s.offline {
+		return health.Check{Name: "clients", OK: true, Detail: "offline evaluators"}
+	}
+	s.mu.RLock()
+	ids := make([]string, 0, len(s.clients))
+	for pid := range s.clients {
+		ids = append(ids, pid)
+	}
+	s.mu.RUnlock()
+	if len(ids) == 0 {
+		return health.Check{Name: "clients", Detail: "no product registered"}
+	}
+	sort.Strings(ids)
+	return health.Check{Name: "clients", OK: true, Detail: fmt.Sprintf("%d registered: %s", len(ids), strings.Join(ids, ", "))}
+}
+
+// Drain fails readiness so that /readyz reports the shutdown while the HTTP
+// server finishes its in-flight requests. Call it before shutting the HTTP
+// server down, and Close after.
+func (s *Server) Drain() { s.closing.Store(true) }
+
+// Close fails readiness, stops the manifest watcher and running
+// simulations, waits for them, and closes every registered SDK client. Call it after the
+// HTTP server has shut down; it is safe to call more than once.
+func (s *Server) Close() {
+	s.closeOnce.Do(func() {
+		s.closing.Store(true)
+		close(s.stop)
+
+		// The simulations call the SDK clients, so they stop first
+		if n := s.sims.stopAll(simStopTimeout); n > 0 {
+			log.Printf("Stopped %d running simulation(s)", n)
+		}
+
+		s.mu.Lock()
+		clients := s.clients
+		s.clients = make(map[string]*lccclient.Client)
+		s.mu.Unlock()
+		for _, cli := range clients {
+			cli.Close()
+		}
+		log.Printf("Closed %d SDK client(s)", len(clients))
+	})
+}
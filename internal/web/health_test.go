@@ -0,0 +1,87 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"demo-app/internal/health"
+)
+
+func readyz(t *testing.T, s *Server) (int, map[string]bool) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	s.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	var body struct {
+		Checks []health.Check `json:"checks"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("readyz %d: %v", rec.Code, err)
+	}
+	ok := map[string]bool{}
+	for _, c := range body.Checks {
+		ok[c.Name] = c.OK
+	}
+	return rec.Code, ok
+}
+
+func TestDrain(t *testing.T) {
+	s := newTestServer(t, WithOfflineEvaluator())
+	if code, _ := readyz(t, s); code != http.StatusOK {
+		t.Fatalf("readyz before Drain = %d, want 200", code)
+	}
+
+	s.Drain()
+	if code, checks := readyz(t, s); code != http.StatusServiceUnavailable || checks["shutdown"] {
+		t.Fatalf("readyz after Drain = %d %v, want 503 with the shutdown check failing", code, checks)
+	}
+	// Draining keeps serving requests.
+	rec := httptest.NewRecorder()
+	s.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("healthz after Drain = %d, want 200", rec.Code)
+	}
+}
+
+func TestClose(t *testing.T) {
+	s := testRegisteredServer(t, "data-insight-pro", time.Now())
+
+	cfg := SimulationConfig{ProductID: "data-insight-pro", InstanceID: "close-test", Iterations: 1000, IntervalMS: 10}
+	engine := s.sims.Create(cfg, &fakeBackend{})
+	if err := engine.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	// Another server's simulation keeps running.
+	other := newTestServer(t, WithOfflineEvaluator())
+	otherEngine := other.sims.Create(cfg, &fakeBackend{})
+	if err := otherEngine.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	s.Close()
+	if status, _ := engine.GetStatus(); status != StatusStopped {
+		t.Errorf("simulation status = %s, want %s", status, StatusStopped)
+	}
+	select {
+	case <-engine.done:
+	default:
+		t.Error("Close returned before the simulation loop")
+	}
+	if status, _ := otherEngine.GetStatus(); status != StatusRunning {
+		t.Errorf("other server's simulation status = %s, want %s", status, StatusRunning)
+	}
+	s.mu.RLock()
+	clients := len(s.clients)
+	s.mu.RUnlock()
+	if clients != 0 {
+		t.Errorf("%d clients left after Close", clients)
+	}
+	if code, checks := readyz(t, s); code != http.StatusServiceUnavailable || checks["shutdown"] || checks["clients"] {
+		t.Errorf("readyz after Close = %d %v, want 503 with shutdown and clients failing", code, checks)
+	}
+
+	s.Close() // safe to call again
+}
@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"time"
 
+	"demo-app/internal/semver"
+
 	"github.com/yourorg/lcc-sdk/pkg/auth"
 	lccclient "github.com/yourorg/lcc-sdk/pkg/client"
 	lccconfig "github.com/yourorg/lcc-sdk/pkg/config"
@@ -36,8 +38,9 @@ type InstanceStatusResponse struct {
 }
 
 type TestInstanceRequest struct {
-	ProductID string `json:"product_id"`
-	FeatureID string `json:"feature_id"`
+	ProductID  string `json:"product_id"`
+	FeatureID  string `json:"feature_id"`
+	InstanceID string `json:"instance_id,omitempty"` // gate at this instance's version
 }
 
 type TestInstanceResponse struct {
@@ -95,6 +98,13 @@ func (s *Server) handleInstanceRegister(w http.ResponseWriter, r *http.Request)
 	if req.Version == "" {
 		req.Version = "1.0.0"
 	}
+	if _, err := semver.Parse(req.Version); err != nil {
+		_ = json.NewEncoder(w).Encode(&RegisterInstanceResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
 
 	s.mu.RLock()
 	lccURL := s.lccURL
@@ -120,7 +130,7 @@ func (s *Server) handleInstanceRegister(w http.ResponseWriter, r *http.Request)
 		CacheTTL:       5 * time.Second,
 	}
 
-	ks, _ := NewKeyStore()
+	ks, _ := NewKeyStore(s.dataDir)
 	var kp *auth.KeyPair
 
 	if ks != nil {
@@ -164,9 +174,10 @@ func (s *Server) handleInstanceRegister(w http.ResponseWriter, r *http.Request)
 	instanceID := cli.GetInstanceID()
 	registeredAt := time.Now()
 
-	s.mu.Lock()
 	// Store in old map for backward compatibility
-	s.clients[req.ProductID] = cli
+	s.addClient(req.ProductID, cli, req.Version)
+
+	s.mu.Lock()
 	// Store in new multi-instance map with unique key per product-version
 	instanceKey := fmt.Sprintf("%s:%s:%s", req.ProductID, req.Version, instanceID)
 	s.instances[instanceKey] = &Instance{
@@ -211,11 +222,12 @@ func (s *Server) handleInstanceStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	features, _ := LoadFeaturesForProduct(productID)
+	features, _ := s.manifests.loadFeaturesForProduct(productID)
 	if len(features) == 0 {
-		features, _ = LoadFeatureUnion()
+		features, _ = s.manifests.loadFeatureUnion()
 	}
 
+	version := s.requestVersion(productID, r.URL.Query().Get("instance_id"))
 	out := make([]featureStatusDTO, 0, len(features))
 	for _, f := range features {
 		st, err := cli.CheckFeature(f.ID)
@@ -228,7 +240,7 @@ func (s *Server) handleInstanceStatus(w http.ResponseWriter, r *http.Request) {
 			})
 			continue
 		}
-		out = append(out, featureStatusDTO{
+		dto := featureStatusDTO{
 			ID:             f.ID,
 			Name:           f.Name,
 			Enabled:        st.Enabled,
@@ -237,7 +249,9 @@ func (s *Server) handleInstanceStatus(w http.ResponseWriter, r *http.Request) {
 			MaxCapacity:    st.MaxCapacity,
 			MaxTPS:         st.MaxTPS,
 			MaxConcurrency: st.MaxConcurrency,
-		})
+		}
+		s.applyVersionGate(&dto, productID, version)
+		out = append(out, dto)
 	}
 
 	_ = json.NewEncoder(w).Encode(&InstanceStatusResponse{
@@ -291,10 +305,13 @@ func (s *Server) handleInstanceTest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	dto := featureStatusDTO{ID: req.FeatureID, Enabled: status.Enabled, Reason: status.Reason}
+	s.applyVersionGate(&dto, req.ProductID, s.requestVersion(req.ProductID, req.InstanceID))
+
 	_ = json.NewEncoder(w).Encode(&TestInstanceResponse{
 		Success:   true,
-		Enabled:   status.Enabled,
-		Reason:    status.Reason,
+		Enabled:   dto.Enabled,
+		Reason:    dto.Reason,
 		FeatureID: req.FeatureID,
 		Message:   "Feature check successful",
 	})
@@ -326,12 +343,17 @@ func (s *Server) handleInstanceClear(w http.ResponseWriter, r *http.Request) {
 
 	s.mu.Lock()
 	// Delete from old single-instance map
+	if cli, ok := s.clients[req.ProductID]; ok && cli != nil {
+		delete(s.versions, cli.GetInstanceID())
+	}
 	delete(s.clients, req.ProductID)
 
 	// Delete from new multi-instance map if instanceID provided
 	if req.InstanceID != "" {
 		for key := range s.instances {
 			if s.instances[key].InstanceID == req.InstanceID {
+				delete(s.seatPools, seatPoolKey(s.instances[key].ProductID, req.InstanceID))
+				delete(s.versions, req.InstanceID)
 				delete(s.instances, key)
 				delete(s.instanceKeys, key)
 				break
@@ -341,6 +363,8 @@ func (s *Server) handleInstanceClear(w http.ResponseWriter, r *http.Request) {
 		// If no instanceID, delete all instances for this product
 		for key := range s.instances {
 			if s.instances[key].ProductID == req.ProductID {
+				delete(s.seatPools, seatPoolKey(req.ProductID, s.instances[key].InstanceID))
+				delete(s.versions, s.instances[key].InstanceID)
 				delete(s.instances, key)
 				delete(s.instanceKeys, key)
 			}
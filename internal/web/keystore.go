@@ -1,7 +1,9 @@
 package web
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 
@@ -13,10 +15,33 @@ type KeyStore struct {
 	baseDir string
 }
 
-func NewKeyStore() (*KeyStore, error) {
+// DefaultDataDir is where the server keeps its config file and keys unless
+// WithDataDir says otherwise.
+func DefaultDataDir() string {
 	h, err := os.UserHomeDir()
-	if err != nil { return nil, err }
-	root := filepath.Join(h, ".lcc-demo", "keys")
+	if err != nil {
+		return ".lcc-demo"
+	}
+	return filepath.Join(h, ".lcc-demo")
+}
+
+// WithDataDir keeps the config file and keys under dir.
+func WithDataDir(dir string) ServerOption {
+	return func(s *Server) { s.dataDir = dir }
+}
+
+// NewKeyStore keeps the product keys under dataDir/keys.
+func NewKeyStore(dataDir string) (*KeyStore, error) {
+	return newKeyStoreAt(filepath.Join(dataDir, "keys"))
+}
+
+// newIssuerKeyStore keeps the license issuer key under dataDir/issuer, apart
+// from the product keys so no product ID can name it.
+func newIssuerKeyStore(dataDir string) (*KeyStore, error) {
+	return newKeyStoreAt(filepath.Join(dataDir, "issuer"))
+}
+
+func newKeyStoreAt(root string) (*KeyStore, error) {
 	if err := os.MkdirAll(root, 0700); err != nil { return nil, err }
 	return &KeyStore{ baseDir: root }, nil
 }
@@ -40,3 +65,20 @@ func (ks *KeyStore) Save(productID string, kp *auth.KeyPair) error {
 	if err != nil { return err }
 	return kp.SavePrivateKeyPEMFile(p)
 }
+
+// LoadOrGenerate returns the stored key for name, generating and persisting a
+// new one on first use. A key that exists but cannot be read is an error:
+// replacing it would orphan everything signed with it.
+func (ks *KeyStore) LoadOrGenerate(name string) (*auth.KeyPair, error) {
+	kp, err := ks.Load(name)
+	if err == nil {
+		return kp, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	kp, err = auth.GenerateKeyPair()
+	if err != nil { return nil, err }
+	if err := ks.Save(name, kp); err != nil { return nil, err }
+	return kp, nil
+}
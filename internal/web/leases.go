@@ -0,0 +1,225 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"demo-app/internal/offline"
+)
+
+// leasePool returns the floating-license pool of a product, or nil if its
+// tier grants no floating licenses. Like seats, registered products get a
+// local pool sized from the tier limits.
+func (s *Server) leasePool(productID string) *offline.LeasePool {
+	if s.offline {
+		ev, err := s.offlineEvaluator(productID)
+		if err != nil {
+			return nil
+		}
+		return ev.Leases()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if pool, ok := s.leasePools[productID]; ok {
+		return pool
+	}
+	cfg := tierLimitConfig(productID, "floating")
+	if cfg == nil {
+		return nil
+	}
+	max, _ := cfg["max"].(int)
+	ttl, _ := cfg["lease_ttl_seconds"].(int)
+	pool := offline.NewLeasePool(max, time.Duration(ttl)*time.Second, s.now)
+	s.leasePools[productID] = pool
+	return pool
+}
+
+type leaseReq struct {
+	Holder     string  `json:"holder"`
+	TTLSeconds float64 `json:"ttl_seconds"`
+	LeaseID    string  `json:"lease_id"`
+}
+
+type leaseResp struct {
+	Allowed bool                `json:"allowed"`
+	Reason  string              `json:"reason"`
+	Lease   *offline.Lease      `json:"lease,omitempty"`
+	Pool    offline.LeaseStatus `json:"pool"`
+	Warning string              `json:"warning,omitempty"`
+}
+
+// handleLeases serves floating-license leases:
+//
+//	GET  /api/sim/{product}/leases           list holders
+//	POST /api/sim/{product}/leases/checkout  {"holder", "ttl_seconds"}
+//	POST /api/sim/{product}/leases/renew     {"lease_id"}
+//	POST /api/sim/{product}/leases/checkin   {"lease_id"}
+func (s *Server) handleLeases(productID, op, warning string, w http.ResponseWriter, r *http.Request) {
+	pool := s.leasePool(productID)
+	if pool == nil {
+		writeErr(w, http.StatusNotFound, fmt.Errorf("product %s has no floating licenses", productID))
+		return
+	}
+
+	if op == "" {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(&leaseResp{Allowed: true, Reason: offline.ReasonOK, Pool: pool.Status(), Warning: warning})
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	var req leaseReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErr(w, http.StatusBadRequest, fmt.Errorf("invalid json: %w", err))
+		return
+	}
+
+	resp := leaseResp{Warning: warning}
+	switch op {
+	case "checkout":
+		if req.Holder == "" {
+			writeErr(w, http.StatusBadRequest, fmt.Errorf("holder is required"))
+			return
+		}
+		lease, ok, reason := pool.Checkout(req.Holder, time.Duration(req.TTLSeconds*float64(time.Second)))
+		resp.Allowed, resp.Reason = ok, reason
+		if ok {
+			resp.Lease = &lease
+		}
+	case "renew":
+		lease, ok, reason := pool.Renew(req.LeaseID)
+		resp.Allowed, resp.Reason = ok, reason
+		if ok {
+			resp.Lease = &lease
+		}
+	case "checkin":
+		resp.Allowed, resp.Reason = pool.Checkin(req.LeaseID)
+	default:
+		writeErr(w, http.StatusNotFound, fmt.Errorf("unknown lease operation: %s", op))
+		return
+	}
+	resp.Pool = pool.Status()
+	_ = json.NewEncoder(w).Encode(&resp)
+}
+
+type leaseCrashReq struct {
+	MaxLeases       int `json:"max_leases"`
+	TTLSeconds      int `json:"ttl_seconds"`
+	RenewEvery      int `json:"renew_every_seconds"`
+	CrashAtSeconds  int `json:"crash_at_seconds"`
+	DurationSeconds int `json:"duration_seconds"`
+}
+
+// handleLeaseCrashSimulation plays out a crashed lease holder on a simulated
+// clock: instance-a and instance-b hold every floating license and renew them,
+// instance-c waits for one, and instance-a stops renewing at crash_at_seconds.
+// Its leases are reclaimed once their TTL runs out and instance-c gets one.
+func (s *Server) handleLeaseCrashSimulation(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	req := leaseCrashReq{MaxLeases: 2, TTLSeconds: 30, RenewEvery: 10, CrashAtSeconds: 20, DurationSeconds: 90}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeErr(w, http.StatusBadRequest, fmt.Errorf("invalid json: %w", err))
+			return
+		}
+	}
+	if req.MaxLeases < 2 || req.TTLSeconds <= 0 || req.RenewEvery <= 0 || req.DurationSeconds <= 0 || req.DurationSeconds > 3600 {
+		writeErr(w, http.StatusBadRequest, fmt.Errorf("max_leases must be >= 2; ttl, renew interval and duration (<= 3600s) must be positive"))
+		return
+	}
+	_ = json.NewEncoder(w).Encode(simulateLeaseCrash(req))
+}
+
+func simulateLeaseCrash(req leaseCrashReq) SimulateResponse {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	pool := offline.NewLeasePool(req.MaxLeases, time.Duration(req.TTLSeconds)*time.Second, func() time.Time { return now })
+
+	results := make([]SimulationResult, 0)
+	record := func(sec int, allowed bool, reason, details string) {
+		st := pool.Status()
+		results = append(results, SimulationResult{
+			Iteration: sec,
+			Allowed:   allowed,
+			Remaining: fmt.Sprintf("%d free", st.Available),
+			Reason:    reason,
+			Details:   fmt.Sprintf("t=%ds %s (in_use=%d/%d, reclaimed=%d)", sec, details, st.InUse, st.Max, st.Reclaimed),
+		})
+	}
+
+	// instance-a takes one license, instance-b takes the rest.
+	held := map[string][]string{}
+	for i := 0; i < req.MaxLeases; i++ {
+		holder := "instance-b"
+		if i == 0 {
+			holder = "instance-a"
+		}
+		lease, ok, reason := pool.Checkout(holder, 0)
+		if ok {
+			held[holder] = append(held[holder], lease.ID)
+		}
+		record(0, ok, reason, holder+" checkout")
+	}
+
+	waiting, crashed := true, false
+	reclaimed, crashedAt := 0, 0
+	for sec := 1; sec <= req.DurationSeconds; sec++ {
+		now = start.Add(time.Duration(sec) * time.Second)
+
+		if st := pool.Status(); st.Reclaimed > reclaimed {
+			record(sec, true, "reclaimed", fmt.Sprintf("%d expired lease(s) returned to the pool", st.Reclaimed-reclaimed))
+			reclaimed = st.Reclaimed
+		}
+
+		if !crashed && sec >= req.CrashAtSeconds {
+			crashed, crashedAt = true, sec
+			record(sec, false, "crashed", "instance-a crashed (stops renewing, never checks in)")
+		}
+		if sec%req.RenewEvery == 0 {
+			for _, holder := range []string{"instance-a", "instance-b", "instance-c"} {
+				if holder == "instance-a" && crashed {
+					continue
+				}
+				for _, id := range held[holder] {
+					_, ok, reason := pool.Renew(id)
+					record(sec, ok, reason, holder+" renew")
+				}
+			}
+		}
+		if waiting && sec%req.RenewEvery == 0 {
+			lease, ok, reason := pool.Checkout("instance-c", 0)
+			record(sec, ok, reason, "instance-c checkout")
+			if ok {
+				held["instance-c"] = append(held["instance-c"], lease.ID)
+				waiting = false
+			}
+		}
+	}
+
+	st := pool.Status()
+	crash := fmt.Sprintf("instance-a did not crash (crash_at_seconds %d is after the run)", req.CrashAtSeconds)
+	if crashed {
+		crash = fmt.Sprintf("instance-a crashed at t=%ds", crashedAt)
+	}
+	summary := fmt.Sprintf("Simulated %ds. %s; leases reclaimed: %d; instance-c obtained a lease: %v",
+		req.DurationSeconds, crash, st.Reclaimed, !waiting)
+	return SimulateResponse{
+		Success: true,
+		Type:    "floating",
+		Results: results,
+		Summary: summary,
+	}
+}
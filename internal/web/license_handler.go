@@ -0,0 +1,218 @@
+package web
+
+import (
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"time"
+
+	"demo-app/internal/license"
+
+	"github.com/yourorg/lcc-sdk/pkg/auth"
+)
+
+// LicenseIssuerKeyID names the license signing key in the issuer KeyStore.
+const LicenseIssuerKeyID = "license-issuer"
+
+// DefaultLicenseValidity is used when an issue request does not set valid_days.
+const DefaultLicenseValidity = 365 * 24 * time.Hour
+
+// ErrNoIssuerKey is returned when licenses are verified before any was issued.
+var ErrNoIssuerKey = errors.New("no issuer key")
+
+// licenseIssuerKeys loads the issuer key pair from the issuer KeyStore under
+// dataDir. Only issuing (create) may generate it; verifying against a freshly
+// minted key would reject every license anyway.
+func licenseIssuerKeys(dataDir string, create bool) (crypto.Signer, crypto.PublicKey, error) {
+	kp, err := loadIssuerKeyPair(dataDir, create)
+	if err != nil {
+		return nil, nil, err
+	}
+	privPEM, err := kp.ExportPrivateKeyPEM()
+	if err != nil {
+		return nil, nil, fmt.Errorf("export issuer key: %w", err)
+	}
+	signer, err := license.ParsePrivateKeyPEM([]byte(privPEM))
+	if err != nil {
+		return nil, nil, err
+	}
+	return signer, signer.Public(), nil
+}
+
+func loadIssuerKeyPair(dataDir string, create bool) (*auth.KeyPair, error) {
+	ks, err := newIssuerKeyStore(dataDir)
+	if err != nil {
+		return nil, fmt.Errorf("keystore: %w", err)
+	}
+	var kp *auth.KeyPair
+	if create {
+		kp, err = ks.LoadOrGenerate(LicenseIssuerKeyID)
+	} else {
+		kp, err = ks.Load(LicenseIssuerKeyID)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, ErrNoIssuerKey
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("issuer key: %w", err)
+	}
+	return kp, nil
+}
+
+// LicenseIssuerPublicKeyPEM returns the PEM public key under dataDir that
+// verifies issued licenses, or ErrNoIssuerKey if none has been issued yet.
+func LicenseIssuerPublicKeyPEM(dataDir string) (string, error) {
+	kp, err := loadIssuerKeyPair(dataDir, false)
+	if err != nil {
+		return "", err
+	}
+	return kp.GetPublicKeyPEM()
+}
+
+// IssueLicense builds and signs a license for tierID issued to customer with
+// the issuer key under dataDir.
+func IssueLicense(dataDir, tierID, customer string, validity time.Duration) (*license.SignedLicense, error) {
+	tier := GetTierByID(tierID)
+	if tier == nil {
+		return nil, fmt.Errorf("unknown tier: %s", tierID)
+	}
+	if customer == "" {
+		return nil, fmt.Errorf("customer is required")
+	}
+	if validity <= 0 {
+		validity = DefaultLicenseValidity
+	}
+	signer, _, err := licenseIssuerKeys(dataDir, true)
+	if err != nil {
+		return nil, err
+	}
+	doc := NewLicenseDocument(tier, customer, time.Now(), validity)
+	return license.Sign(doc, signer, LicenseIssuerKeyID)
+}
+
+// VerifyLicense checks sl against the issuer key under dataDir. productID may
+// be empty to skip the product check. Rejections are *license.VerifyError.
+func VerifyLicense(dataDir string, sl *license.SignedLicense, productID string, now time.Time) error {
+	_, pub, err := licenseIssuerKeys(dataDir, false)
+	if err != nil {
+		return err
+	}
+	return license.Verify(sl, pub, license.VerifyOptions{ProductID: productID, Now: now})
+}
+
+type IssueLicenseRequest struct {
+	Tier      string `json:"tier"`
+	Customer  string `json:"customer"`
+	ValidDays int    `json:"valid_days,omitempty"`
+}
+
+type VerifyLicenseRequest struct {
+	License   json.RawMessage `json:"license"`
+	ProductID string          `json:"product_id,omitempty"`
+}
+
+type VerifyLicenseResponse struct {
+	Valid     bool   `json:"valid"`
+	Reason    string `json:"reason,omitempty"`
+	Error     string `json:"error,omitempty"`
+	LicenseID string `json:"license_id,omitempty"`
+	ProductID string `json:"product_id,omitempty"`
+	Customer  string `json:"customer,omitempty"`
+	ExpiresAt string `json:"expires_at,omitempty"`
+}
+
+// handleLicenseIssue signs a license for a tier and customer.
+func (s *Server) handleLicenseIssue(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req IssueLicenseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErr(w, http.StatusBadRequest, fmt.Errorf("invalid json: %w", err))
+		return
+	}
+	if GetTierByID(req.Tier) == nil {
+		writeErr(w, http.StatusBadRequest, fmt.Errorf("unknown tier: %q", req.Tier))
+		return
+	}
+	if req.Customer == "" {
+		writeErr(w, http.StatusBadRequest, fmt.Errorf("customer is required"))
+		return
+	}
+
+	validity := time.Duration(req.ValidDays) * 24 * time.Hour
+	sl, err := IssueLicense(s.dataDir, req.Tier, req.Customer, validity)
+	if err != nil {
+		writeErr(w, http.StatusInternalServerError, err)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(sl)
+}
+
+// handleLicenseVerify checks signature, expiry and product of a license file.
+func (s *Server) handleLicenseVerify(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req VerifyLicenseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErr(w, http.StatusBadRequest, fmt.Errorf("invalid json: %w", err))
+		return
+	}
+	if len(req.License) == 0 {
+		writeErr(w, http.StatusBadRequest, fmt.Errorf("license is required"))
+		return
+	}
+
+	sl, err := license.Parse(req.License)
+	if err != nil {
+		_ = json.NewEncoder(w).Encode(&VerifyLicenseResponse{Reason: license.ReasonOf(err), Error: err.Error()})
+		return
+	}
+
+	resp := VerifyLicenseResponse{
+		LicenseID: sl.License.LicenseID,
+		ProductID: sl.License.ProductID,
+		Customer:  sl.License.Customer,
+		ExpiresAt: sl.License.ExpiresAt.Format(time.RFC3339),
+	}
+	if err := VerifyLicense(s.dataDir, sl, req.ProductID, time.Now()); err != nil {
+		resp.Reason = license.ReasonOf(err)
+		resp.Error = err.Error()
+	} else {
+		resp.Valid = true
+	}
+	_ = json.NewEncoder(w).Encode(&resp)
+}
+
+// handleLicensePublicKey returns the issuer public key for offline verification.
+func (s *Server) handleLicensePublicKey(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	pemStr, err := LicenseIssuerPublicKeyPEM(s.dataDir)
+	if errors.Is(err, ErrNoIssuerKey) {
+		writeErr(w, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		writeErr(w, http.StatusInternalServerError, err)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"key_id":     LicenseIssuerKeyID,
+		"public_key": pemStr,
+	})
+}
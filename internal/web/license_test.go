@@ -0,0 +1,128 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"demo-app/internal/license"
+)
+
+func TestVerifyLicenseDoesNotMintKey(t *testing.T) {
+	dir := t.TempDir()
+
+	sl := &license.SignedLicense{License: NewLicenseDocument(GetTierByID("basic"), "acme", time.Now(), time.Hour)}
+	if err := VerifyLicense(dir, sl, "", time.Now()); !errors.Is(err, ErrNoIssuerKey) {
+		t.Fatalf("VerifyLicense err = %v, want ErrNoIssuerKey", err)
+	}
+	if _, err := LicenseIssuerPublicKeyPEM(dir); !errors.Is(err, ErrNoIssuerKey) {
+		t.Fatalf("LicenseIssuerPublicKeyPEM err = %v, want ErrNoIssuerKey", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "issuer", LicenseIssuerKeyID+".pem")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("issuer key was created by verify (stat err = %v)", err)
+	}
+
+	sl, err := IssueLicense(dir, "basic", "acme", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyLicense(dir, sl, "", time.Now()); err != nil {
+		t.Fatalf("VerifyLicense after issue: %v", err)
+	}
+}
+
+func TestLoadOrGenerateKeepsUnreadableKey(t *testing.T) {
+	ks := &KeyStore{baseDir: t.TempDir()}
+	p := filepath.Join(ks.baseDir, LicenseIssuerKeyID+".pem")
+	corrupt := []byte("not a pem file")
+	if err := os.WriteFile(p, corrupt, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ks.LoadOrGenerate(LicenseIssuerKeyID); err == nil {
+		t.Fatal("LoadOrGenerate accepted a corrupt key")
+	}
+	got, err := os.ReadFile(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(corrupt) {
+		t.Fatal("LoadOrGenerate overwrote the existing key")
+	}
+
+	if _, err := ks.LoadOrGenerate("fresh"); err != nil {
+		t.Fatalf("LoadOrGenerate on a missing key: %v", err)
+	}
+}
+
+func TestServersKeepTheirDataDirs(t *testing.T) {
+	post := func(s *Server, path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		s.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+		return rec
+	}
+	a, b := newTestServer(t), newTestServer(t)
+	dirA, dirB := a.dataDir, b.dataDir
+
+	rec := post(a, "/api/licenses/issue", `{"tier":"basic","customer":"acme"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("issue = %d: %s", rec.Code, rec.Body)
+	}
+	if _, err := os.Stat(filepath.Join(dirA, "issuer", LicenseIssuerKeyID+".pem")); err != nil {
+		t.Fatalf("issuer key not under the server's data dir: %v", err)
+	}
+	if p, err := a.configPath(); err != nil || p != filepath.Join(dirA, ConfigFileName) {
+		t.Fatalf("configPath = %s, %v; want it under %s", p, err, dirA)
+	}
+
+	body := `{"license":` + rec.Body.String() + `}`
+	for _, tc := range []struct {
+		s     *Server
+		valid bool
+	}{{a, true}, {b, false}} {
+		var resp VerifyLicenseResponse
+		if err := json.Unmarshal(post(tc.s, "/api/licenses/verify", body).Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.Valid != tc.valid {
+			t.Errorf("verify on %s = %+v, want valid %v", tc.s.dataDir, resp, tc.valid)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dirB, "issuer", LicenseIssuerKeyID+".pem")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("verify created an issuer key under the other data dir (stat err = %v)", err)
+	}
+}
+
+func TestProductKeyCannotShadowIssuerKey(t *testing.T) {
+	dir := t.TempDir()
+	sl, err := IssueLicense(dir, "basic", "acme", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ks, err := NewKeyStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ks.LoadOrGenerate(LicenseIssuerKeyID); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyLicense(dir, sl, "", time.Now()); err != nil {
+		t.Fatalf("a product key named %q replaced the issuer key: %v", LicenseIssuerKeyID, err)
+	}
+}
+
+func TestIssuedLicenseCarriesTrials(t *testing.T) {
+	sl, err := IssueLicense(t.TempDir(), "basic", "acme", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := sl.License.Features["ml_analytics"].Trial; got == nil || *got != *BasicTier.Features["ml_analytics"].Trial {
+		t.Fatalf("ml_analytics trial = %+v, want %+v", got, BasicTier.Features["ml_analytics"].Trial)
+	}
+}
@@ -0,0 +1,158 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"demo-app/internal/license"
+)
+
+// licenseTerm is the locally modeled validity window of a product license.
+type licenseTerm struct {
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+	Renewals  int
+}
+
+// licenseStatusDTO is the license lifetime block of status responses.
+type licenseStatusDTO struct {
+	license.Lifecycle
+	IssuedAt time.Time `json:"issued_at"`
+	Renewals int       `json:"renewals"`
+	Now      time.Time `json:"now"`
+}
+
+// now returns the server's simulated time (wall clock plus the offset set
+// through /api/sim/clock).
+func (s *Server) now() time.Time {
+	return s.clock().Add(time.Duration(s.clockOffset.Load()))
+}
+
+// licenseTermFor returns (creating on first use) the license term of a product.
+// Offline products take the term of their evaluator's license document; the
+// others get DefaultLicenseValidity from registration (see addClient).
+func (s *Server) licenseTermFor(productID string) *licenseTerm {
+	s.mu.RLock()
+	term, ok := s.terms[productID]
+	s.mu.RUnlock()
+	if ok {
+		return term
+	}
+
+	term = &licenseTerm{IssuedAt: s.now(), ExpiresAt: s.now().Add(DefaultLicenseValidity)}
+	if s.offline {
+		if ev, err := s.offlineEvaluator(productID); err == nil {
+			doc := ev.Document()
+			term.IssuedAt, term.ExpiresAt = doc.IssuedAt, doc.ExpiresAt
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, ok := s.terms[productID]; ok {
+		return existing
+	}
+	s.terms[productID] = term
+	return term
+}
+
+// licenseStatus evaluates the license lifetime of a product at simulated now.
+func (s *Server) licenseStatus(productID string) licenseStatusDTO {
+	term := s.licenseTermFor(productID)
+	now := s.now()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return licenseStatusDTO{
+		Lifecycle: license.DefaultPolicy.Evaluate(term.ExpiresAt, now),
+		IssuedAt:  term.IssuedAt,
+		Renewals:  term.Renewals,
+		Now:       now,
+	}
+}
+
+type renewReq struct {
+	Days int `json:"days"`
+}
+
+type renewResp struct {
+	ProductID string           `json:"product_id"`
+	Renewed   bool             `json:"renewed"`
+	License   licenseStatusDTO `json:"license"`
+}
+
+// handleRenew extends a product license: /api/sim/{product}/renew
+func (s *Server) handleRenew(productID string, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	var req renewReq
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeErr(w, http.StatusBadRequest, fmt.Errorf("invalid json: %w", err))
+			return
+		}
+	}
+	termLen := DefaultLicenseValidity
+	if req.Days > 0 {
+		termLen = time.Duration(req.Days) * 24 * time.Hour
+	}
+
+	term := s.licenseTermFor(productID)
+	now := s.now()
+	s.mu.Lock()
+	term.ExpiresAt = license.Renew(term.ExpiresAt, now, termLen)
+	term.Renewals++
+	s.mu.Unlock()
+
+	_ = json.NewEncoder(w).Encode(&renewResp{ProductID: productID, Renewed: true, License: s.licenseStatus(productID)})
+}
+
+type clockReq struct {
+	AdvanceDays float64 `json:"advance_days"`
+	Reset       bool    `json:"reset"`
+}
+
+type clockResp struct {
+	Now        time.Time `json:"now"`
+	OffsetDays float64   `json:"offset_days"`
+}
+
+// handleSimClock reads or moves the simulated clock: /api/sim/clock
+func (s *Server) handleSimClock(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		var req clockReq
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeErr(w, http.StatusBadRequest, fmt.Errorf("invalid json: %w", err))
+			return
+		}
+		if req.Reset {
+			s.clockOffset.Store(0)
+			s.trials.reset()
+		}
+		if req.AdvanceDays != 0 {
+			s.clockOffset.Add(int64(time.Duration(req.AdvanceDays * float64(24*time.Hour))))
+		}
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	offset := time.Duration(s.clockOffset.Load())
+	_ = json.NewEncoder(w).Encode(&clockResp{Now: s.now(), OffsetDays: offset.Hours() / 24})
+}
+
+// writeLicenseExpired answers a simulation action on an expired license.
+func writeLicenseExpired(w http.ResponseWriter, lic licenseStatusDTO) {
+	w.WriteHeader(http.StatusForbidden)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"allowed": false,
+		"reason":  license.ReasonLicenseExpired,
+		"warning": lic.Warning,
+		"license": lic,
+	})
+}
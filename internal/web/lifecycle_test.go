@@ -0,0 +1,75 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"demo-app/internal/license"
+
+	lccclient "github.com/yourorg/lcc-sdk/pkg/client"
+)
+
+// testRegisteredServer returns a server with no manifests whose clock is
+// pinned to at, and with productID registered through addClient.
+func testRegisteredServer(t *testing.T, productID string, at time.Time) *Server {
+	t.Helper()
+	s := newTestServer(t)
+	s.clock = func() time.Time { return at }
+	s.mu.Lock()
+	s.lccURL = "http://lcc.invalid"
+	s.mu.Unlock()
+	s.addClient(productID, new(lccclient.Client), "1.0.0")
+	return s
+}
+
+func TestFreshRegistrationIsActive(t *testing.T) {
+	at := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	s := testRegisteredServer(t, "data-insight-pro", at)
+
+	rec := httptest.NewRecorder()
+	s.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/sim/data-insight-pro/status", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	var resp productStatusResp
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.License == nil {
+		t.Fatal("status has no license block")
+	}
+	if resp.License.State != license.StateActive {
+		t.Fatalf("license state = %q, want %q", resp.License.State, license.StateActive)
+	}
+	if !resp.License.IssuedAt.Equal(at) || !resp.License.ExpiresAt.Equal(at.Add(DefaultLicenseValidity)) {
+		t.Fatalf("term = %s..%s, want one year from registration at %s", resp.License.IssuedAt, resp.License.ExpiresAt, at)
+	}
+}
+
+func TestRegisteredLicenseExpires(t *testing.T) {
+	at := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	s := testRegisteredServer(t, "data-insight-pro", at)
+
+	// Past the term and the grace period, actions are denied.
+	s.clock = func() time.Time { return at.Add(DefaultLicenseValidity + 30*24*time.Hour) }
+	rec := httptest.NewRecorder()
+	s.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/sim/data-insight-pro/consume", strings.NewReader(`{"amount": 1}`)))
+	if rec.Code != http.StatusForbidden || !strings.Contains(rec.Body.String(), license.ReasonLicenseExpired) {
+		t.Fatalf("consume = %d %s, want 403 %s", rec.Code, rec.Body, license.ReasonLicenseExpired)
+	}
+}
+
+func TestTierLicenseIsCurrent(t *testing.T) {
+	lic := GetLicenseJSON(ProfessionalTier)
+	expires, err := time.Parse(time.RFC3339, lic["expires_at"].(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st := license.DefaultPolicy.Evaluate(expires, time.Now()); st.State != license.StateActive {
+		t.Fatalf("tier license state = %q, want %q", st.State, license.StateActive)
+	}
+}
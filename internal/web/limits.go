@@ -1,5 +1,11 @@
 package web
 
+import (
+	"strings"
+
+	"demo-app/internal/manifest"
+)
+
 type LimitType struct {
 	Type        string `json:"type"`
 	Name        string `json:"name"`
@@ -77,6 +83,38 @@ var AllLimitTypes = []LimitType{
 		TimeDimension: "Duration of operation (held then released)",
 		WhoTracks:     "SDK tracks automatically - compiler injects acquire/release",
 	},
+	{
+		Type:        "seats",
+		Name:        "Named-User Seats",
+		Title:       "Named-User Seats",
+		Description: "Product-level number of named users. Seats are assigned to and revoked from user IDs; a revoked seat cannot be reassigned to another user until the cooldown expires.",
+		SDKAPI:      "AssignSeat(userID) / RevokeSeat(userID) / ListSeats()",
+		Tracking:    "Per product instance seat registry",
+		UseCases: []string{
+			"Named analyst accounts (10 seats on Professional)",
+			"Per-user desktop installs",
+			"Preventing seat sharing by rotating users",
+			"Audit of who holds a license seat",
+		},
+		TimeDimension: "Persistent assignment, reassignment cooldown after revoke",
+		WhoTracks:     "Admin assigns/revokes seats - login path checks the assignment",
+	},
+	{
+		Type:        "volume",
+		Name:        "Data Volume",
+		Title:       "Data Volume Metering",
+		Description: "Product-level amount of data processed (bytes or records) per window. Charged by what a call processed, not by the number of calls.",
+		SDKAPI:      "ConsumeVolume(bytes, records) - reported through a VolumeConsumer hook",
+		Tracking:    "Developer reports processed volume via consumer hook",
+		UseCases: []string{
+			"Analytics billed per GB processed (10 GiB/month)",
+			"Records ingested per day",
+			"ETL throughput allowances",
+			"Metered data exports",
+		},
+		TimeDimension: "Daily or Monthly window with auto-reset",
+		WhoTracks:     "Business function reports volume through the consumer hook",
+	},
 }
 
 func GetLimitTypeByType(limitType string) *LimitType {
@@ -137,20 +175,7 @@ func GetConsumeAmount(ctx context.Context, args ...interface{}) int {
     return data.SizeKB()  // Charge by data size
 }
 
-// ========== Compiler Auto-Generated Code ==========
-func ProcessAnalytics__generated(data Dataset) error {
-    // Auto-injected quota check
-    amount := GetConsumeAmount(context.Background(), data)
-    allowed, remaining, err := __lcc.Consume(amount)  // No featureID!
-    
-    if err != nil || !allowed {
-        log.Warn("Quota exceeded", "remaining", remaining)
-        return ErrQuotaExceeded
-    }
-    
-    // Original business logic
-    return analytics.RunMLModel(data)
-}`,
+` + generatedExample("ProcessAnalytics", []manifest.Param{{Name: "data", Type: "Dataset"}}, manifest.GenOptions{Limits: []string{manifest.LimitQuota}, QuotaConsumer: "GetConsumeAmount"}),
 			BehaviorTable: []BehaviorRow{
 				{Call: "1", Allowed: "✓ Yes", Remaining: "49,999", Reason: "ok"},
 				{Call: "1,000", Allowed: "✓ Yes", Remaining: "49,000", Reason: "ok"},
@@ -194,20 +219,7 @@ func GetCurrentTPS() float64 {
     return myRateLimiter.GetCurrentRate()  // Custom rate measurement
 }
 
-// ========== Compiler Auto-Generated Code ==========
-func HandleAPIRequest__generated(ctx context.Context) error {
-    // Auto-injected TPS check
-    currentTPS := GetCurrentTPS()  // Or SDK auto-tracks if not provided
-    allowed, maxTPS, err := __lcc.CheckTPS(currentTPS)  // No featureID!
-    
-    if err != nil || !allowed {
-        log.Warn("TPS exceeded", "current", currentTPS, "max", maxTPS)
-        return ErrRateLimitExceeded
-    }
-    
-    // Original business logic
-    return processRequest(ctx)
-}`,
+` + generatedExample("HandleAPIRequest", []manifest.Param{{Name: "ctx", Type: "context.Context"}}, manifest.GenOptions{Limits: []string{manifest.LimitTPS}, TPSProvider: "GetCurrentTPS"}),
 			BehaviorTable: []BehaviorRow{
 				{Call: "TPS=50.5", Allowed: "✓ Yes", Remaining: "max=100.0", Reason: "ok"},
 				{Call: "TPS=95.3", Allowed: "✓ Yes", Remaining: "max=100.0", Reason: "ok"},
@@ -250,20 +262,7 @@ func GetCurrentProjectCount() int {
     return count  // Return current resource usage
 }
 
-// ========== Compiler Auto-Generated Code ==========
-func CreateProject__generated(name string) error {
-    // Auto-injected capacity check
-    currentCount := GetCurrentProjectCount()  // Call required helper
-    allowed, maxCap, err := __lcc.CheckCapacity(currentCount)  // No featureID!
-    
-    if err != nil || !allowed {
-        log.Warn("Capacity exceeded", "current", currentCount, "max", maxCap)
-        return ErrCapacityExceeded
-    }
-    
-    // Original business logic
-    return db.CreateProject(name)
-}`,
+` + generatedExample("CreateProject", []manifest.Param{{Name: "name", Type: "string"}}, manifest.GenOptions{Limits: []string{manifest.LimitCapacity}, CapacityCounter: "GetCurrentProjectCount"}),
 			BehaviorTable: []BehaviorRow{
 				{Call: "count=10", Allowed: "✓ Yes", Remaining: "max=100", Reason: "ok"},
 				{Call: "count=50", Allowed: "✓ Yes", Remaining: "max=100", Reason: "ok"},
@@ -299,22 +298,7 @@ func HandleUserSession(userID string) error {
     return handleUserActions(userID)
 }
 
-// ========== Compiler Auto-Generated Code ==========
-func HandleUserSession__generated(userID string) error {
-    // Auto-injected concurrency control
-    release, allowed, err := __lcc.AcquireSlot()  // No featureID!
-    if err != nil || !allowed {
-        log.Warn("Concurrency limit reached")
-        return ErrTooManyConcurrentUsers
-    }
-    
-    // Auto-injected defer to release slot
-    defer release()
-    
-    // Original business logic
-    log.Info("Session started", "user", userID)
-    return handleUserActions(userID)
-}`,
+` + generatedExample("HandleUserSession", []manifest.Param{{Name: "userID", Type: "string"}}, manifest.GenOptions{Limits: []string{manifest.LimitConcurrency}}),
 			BehaviorTable: []BehaviorRow{
 				{Call: "Slot 1", Allowed: "✓ Yes", Remaining: "9 free", Reason: "ok"},
 				{Call: "Slot 5", Allowed: "✓ Yes", Remaining: "5 free", Reason: "ok"},
@@ -331,7 +315,122 @@ func HandleUserSession__generated(userID string) error {
 				"⚡ Real-time slot management (instant acquire/release)",
 			},
 		}
+	case "seats":
+		return &LimitExample{
+			LicenseConfig: `{
+  "product_id": "data-insight-pro",
+  "tier": "professional",
+  "features": {
+    "ml_analytics": { "enabled": true },
+    "api_access": { "enabled": true }
+  },
+  "limits": {
+    "seats": {
+      "max": 10,                     // Named users per product instance
+      "reassign_cooldown_days": 7    // Freed seat is reserved for 7 days
+    }
+  }
+}`,
+			CodeExample: `// ========== Admin Code: Manage Named Users ==========
+func AddAnalyst(userID string) error {
+    allowed, reason, err := __lcc.AssignSeat(userID)
+    if err != nil || !allowed {
+        return fmt.Errorf("cannot assign seat: %s", reason)  // seats_exhausted / seat_cooldown
+    }
+    return directory.Enable(userID)
+}
+
+func RemoveAnalyst(userID string) error {
+    _, _, err := __lcc.RevokeSeat(userID)  // Seat enters reassignment cooldown
+    return err
+}
+
+// ========== Developer Code (Clean Business Logic) ==========
+func OpenWorkspace(userID string) error {
+    // Login path: only users holding a seat may proceed
+    return workspace.Open(userID)
+}`,
+			BehaviorTable: []BehaviorRow{
+				{Call: "assign alice", Allowed: "✓ Yes", Remaining: "9 free", Reason: "ok"},
+				{Call: "assign #10", Allowed: "✓ Yes", Remaining: "0 free", Reason: "ok"},
+				{Call: "assign #11", Allowed: "❌ No", Remaining: "0 free", Reason: "seats_exhausted"},
+				{Call: "revoke bob", Allowed: "✓ Yes", Remaining: "0 free (1 cooling)", Reason: "ok"},
+				{Call: "assign carol", Allowed: "❌ No", Remaining: "0 free (1 cooling)", Reason: "seat_cooldown"},
+				{Call: "assign bob", Allowed: "✓ Yes", Remaining: "0 free", Reason: "ok (reclaims own seat)"},
+				{Call: "(After 7 days)", Allowed: "✓ Yes", Remaining: "1 free", Reason: "ok (cooldown over)"},
+			},
+			KeyPoints: []string{
+				"✅ Product-level limit (named users per instance)",
+				"👤 Seats belong to user IDs, not to concurrent sessions",
+				"⏳ Reassignment cooldown stops seat sharing by rotation",
+				"♻️ A revoked user can reclaim their own seat during cooldown",
+				"📋 List assignments for audit at any time",
+			},
+		}
+	case "volume":
+		return &LimitExample{
+			LicenseConfig: `{
+  "product_id": "data-insight-pro",
+  "tier": "professional",
+  "features": {
+    "ml_analytics": { "enabled": true }
+  },
+  "limits": {
+    "volume": {
+      "max": 10737418240,   // 10 GiB per window (product-level)
+      "unit": "bytes",      // or "records"
+      "window": "monthly"   // Reset period
+    }
+  }
+}`,
+			CodeExample: `// ========== Developer Code (Clean Business Logic) ==========
+func RunAdvanced() {
+    events := loadEvents()
+    runModels(events)
+
+    // Report what this run processed
+    reportVolume(Volume{Records: len(events), Bytes: sizeOf(events)})
+}
+
+// ========== Consumer Hook (wired once at startup) ==========
+// Not configured in YAML: register the hook with analytics.SetVolumeConsumer
+analytics.SetVolumeConsumer(func(v analytics.Volume) {
+    allowed, info, err := __lcc.ConsumeVolume(v.Bytes, v.Records)
+    if err != nil || !allowed {
+        log.Warn("Volume exceeded", "used", info.Used, "limit", info.Limit)
+    }
+})`,
+			BehaviorTable: []BehaviorRow{
+				{Call: "run 1 (2 GiB)", Allowed: "✓ Yes", Remaining: "8 GiB", Reason: "ok"},
+				{Call: "run 2 (5 GiB)", Allowed: "✓ Yes", Remaining: "3 GiB", Reason: "ok"},
+				{Call: "run 3 (3 GiB)", Allowed: "✓ Yes", Remaining: "0 GiB", Reason: "ok"},
+				{Call: "run 4 (1 GiB)", Allowed: "❌ No", Remaining: "0 GiB", Reason: "volume_exceeded"},
+				{Call: "(Next Month)", Allowed: "✓ Yes", Remaining: "10 GiB", Reason: "reset"},
+			},
+			KeyPoints: []string{
+				"✅ Product-level limit (all features share the volume pool)",
+				"📦 Charged by data processed, not by call count",
+				"🔧 Consumer hook: business code reports bytes and records",
+				"📏 License picks the unit: bytes or records",
+				"🔄 Auto-resets daily/monthly per license config",
+			},
+		}
 	default:
 		return nil
 	}
 }
+
+// generatedExample renders the wrapper codegen emits for an example function
+// returning error, without the file header.
+func generatedExample(fn string, params []manifest.Param, opts manifest.GenOptions) string {
+	f := manifest.Feature{Intercept: &manifest.Target{Package: "example.com/data-insight/app", Function: fn}}
+	code, err := manifest.Generate(f, manifest.Signature{Params: params, Results: []string{"error"}}, opts)
+	if err != nil {
+		return "// codegen failed: " + err.Error()
+	}
+	src := string(code)
+	if i := strings.Index(src, "\n// "+fn+manifest.GeneratedSuffix); i >= 0 {
+		src = src[i+1:]
+	}
+	return "// ========== Compiler Auto-Generated Code (lcc_gen.go) ==========\n" + src
+}
@@ -6,6 +6,9 @@ import (
 	"math/rand"
 	"net/http"
 	"strings"
+	"time"
+
+	"demo-app/internal/offline"
 )
 
 func (s *Server) handleGetLimitTypes(w http.ResponseWriter, r *http.Request) {
@@ -101,6 +104,10 @@ func simulateLimitType(limitType string, req SimulateRequest) SimulateResponse {
 		return simulateCapacity(req)
 	case "concurrency":
 		return simulateConcurrency(req)
+	case "seats":
+		return simulateSeats(req)
+	case "volume":
+		return simulateVolume(req)
 	default:
 		return SimulateResponse{
 			Success: false,
@@ -320,6 +327,116 @@ func simulateConcurrency(req SimulateRequest) SimulateResponse {
 	}
 }
 
+// simulateSeats treats each iteration as one day of user churn: users are
+// assigned seats, some are revoked, and replacements wait out the cooldown.
+func simulateSeats(req SimulateRequest) SimulateResponse {
+	maxSeats := 5
+	if max, ok := req.Params["max_seats"].(float64); ok {
+		maxSeats = int(max)
+	}
+	cooldownDays := 3
+	if d, ok := req.Params["cooldown_days"].(float64); ok {
+		cooldownDays = int(d)
+	}
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	pool := offline.NewSeatPool(maxSeats, time.Duration(cooldownDays)*24*time.Hour, func() time.Time { return now })
+	results := make([]SimulationResult, 0, req.Iterations)
+	successCount := 0
+	nextUser := 1
+
+	for i := 1; i <= req.Iterations; i++ {
+		action := "assign"
+		var userID string
+		if st := pool.Status(); st.Used > 0 && rand.Float64() > 0.6 {
+			action = "revoke"
+			userID = st.Assignments[rand.Intn(len(st.Assignments))].UserID
+		} else {
+			userID = fmt.Sprintf("user-%d", nextUser)
+		}
+
+		var allowed bool
+		var reason string
+		if action == "assign" {
+			allowed, reason = pool.Assign(userID)
+			if allowed {
+				nextUser++
+				successCount++
+			}
+		} else {
+			allowed, reason = pool.Revoke(userID)
+		}
+
+		st := pool.Status()
+		results = append(results, SimulationResult{
+			Iteration: i,
+			Allowed:   allowed,
+			Remaining: fmt.Sprintf("%d free", st.Available),
+			Reason:    reason,
+			Details:   fmt.Sprintf("day=%d, %s %s, seats=%d/%d, cooling=%d", i, action, userID, st.Used, maxSeats, st.Cooling),
+		})
+		now = now.Add(24 * time.Hour)
+	}
+
+	st := pool.Status()
+	summary := fmt.Sprintf("Completed %d iterations. Assigned: %d, Seats in use: %d/%d, Cooling: %d",
+		req.Iterations, successCount, st.Used, maxSeats, st.Cooling)
+
+	return SimulateResponse{
+		Success: true,
+		Type:    "seats",
+		Results: results,
+		Summary: summary,
+	}
+}
+
+// simulateVolume runs analytics jobs of random size against a volume meter.
+func simulateVolume(req SimulateRequest) SimulateResponse {
+	maxMB := 1024.0
+	if max, ok := req.Params["max_mb"].(float64); ok {
+		maxMB = max
+	}
+	avgMB := 150.0
+	if avg, ok := req.Params["avg_mb"].(float64); ok {
+		avgMB = avg
+	}
+
+	const mb = 1 << 20
+	meter := offline.NewVolumeMeter(offline.UnitBytes, int64(maxMB*mb), "monthly", nil)
+	results := make([]SimulationResult, 0, req.Iterations)
+	successCount := 0
+
+	for i := 1; i <= req.Iterations; i++ {
+		bytes := int64((0.5 + rand.Float64()) * avgMB * mb)
+		allowed, info := meter.Consume(bytes, bytes/512)
+		reason := "ok"
+		if allowed {
+			successCount++
+		} else {
+			reason = offline.ReasonVolumeExceeded
+		}
+
+		results = append(results, SimulationResult{
+			Iteration: i,
+			Allowed:   allowed,
+			Remaining: fmt.Sprintf("%.0f MB", float64(info.Remaining)/mb),
+			Reason:    reason,
+			Details:   fmt.Sprintf("job=%.0f MB, used=%.0f/%.0f MB", float64(bytes)/mb, float64(info.Used)/mb, maxMB),
+		})
+	}
+
+	info := meter.Info()
+	summary := fmt.Sprintf("Completed %d iterations. Success: %d, Failed: %d, Processed: %.0f/%.0f MB",
+		req.Iterations, successCount, req.Iterations-successCount, float64(info.Used)/mb, maxMB)
+
+	return SimulateResponse{
+		Success: true,
+		Type:    "volume",
+		Results: results,
+		Summary: summary,
+	}
+}
+
 func extractLimitTypeFromPath(path, prefix, suffix string) string {
 	if !strings.HasPrefix(path, prefix) {
 		return ""
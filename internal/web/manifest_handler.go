@@ -0,0 +1,186 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"demo-app/internal/manifest"
+)
+
+type manifestValidateReq struct {
+	Path    string `json:"path,omitempty"` // label for positions
+	Content string `json:"content"`
+}
+
+type manifestLintResult struct {
+	Path           string           `json:"path"`
+	Issues         []manifest.Issue `json:"issues"`
+	Error          string           `json:"error,omitempty"`
+	TargetsSkipped string           `json:"targets_skipped,omitempty"` // why intercept targets were not checked
+}
+
+type manifestValidateResp struct {
+	Valid bool                 `json:"valid"`
+	Files []manifestLintResult `json:"files"`
+}
+
+// manifestModule finds the Go module a manifest belongs to from its
+// directory, not the server's working directory.
+func manifestModule(manifestPath string) (*manifest.Module, error) {
+	mod, err := manifest.FindModule(filepath.Dir(manifestPath))
+	if err != nil {
+		return nil, fmt.Errorf("module of %s: %w", manifestPath, err)
+	}
+	return mod, nil
+}
+
+// manifestLinter returns a linter for the manifest at manifestPath and, when
+// its module cannot be found, why intercept targets go unchecked.
+func manifestLinter(manifestPath string) (*manifest.Linter, string) {
+	mod, err := manifestModule(manifestPath)
+	if err != nil {
+		return manifest.NewLinter(nil), err.Error()
+	}
+	return manifest.NewLinter(mod), ""
+}
+
+// uploadLinter returns a linter for a manifest posted to validate. Its path
+// is only a label: targets resolve in the module of the configured manifest
+// with that path or, for any other label, in the server's own module.
+func (s *Server) uploadLinter(label string) (*manifest.Linter, string) {
+	for _, e := range s.manifests.list() {
+		if filepath.Clean(e.Path) == filepath.Clean(label) {
+			return manifestLinter(e.Path)
+		}
+	}
+	mod, err := manifest.FindModule(".")
+	if err != nil {
+		return manifest.NewLinter(nil), fmt.Sprintf("module of the server: %v", err)
+	}
+	return manifest.NewLinter(mod), ""
+}
+
+// handleManifestValidate lints manifests. GET lints the local manifests;
+// POST lints the YAML in the request body (see uploadLinter).
+func (s *Server) handleManifestValidate(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	resp := manifestValidateResp{Valid: true, Files: []manifestLintResult{}}
+	add := func(res manifestLintResult) {
+		if res.Error != "" || manifest.HasErrors(res.Issues) {
+			resp.Valid = false
+		}
+		resp.Files = append(resp.Files, res)
+	}
+
+	if r.Method == http.MethodPost {
+		var req manifestValidateReq
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeErr(w, http.StatusBadRequest, fmt.Errorf("invalid json: %w", err))
+			return
+		}
+		if req.Content == "" {
+			writeErr(w, http.StatusBadRequest, fmt.Errorf("content is required"))
+			return
+		}
+		path := nonEmpty(req.Path, "manifest.yaml")
+		linter, skipped := s.uploadLinter(path)
+		add(manifestLintResult{Path: path, Issues: nonNilIssues(linter.Lint(path, []byte(req.Content))), TargetsSkipped: skipped})
+		_ = json.NewEncoder(w).Encode(&resp)
+		return
+	}
+
+	for _, e := range s.manifests.list() {
+		linter, skipped := manifestLinter(e.Path)
+		res := manifestLintResult{Path: e.Path, TargetsSkipped: skipped}
+		issues, err := linter.LintFile(e.Path)
+		if err != nil {
+			res.Error = err.Error()
+		}
+		res.Issues = nonNilIssues(issues)
+		add(res)
+	}
+	_ = json.NewEncoder(w).Encode(&resp)
+}
+
+func nonNilIssues(issues []manifest.Issue) []manifest.Issue {
+	if issues == nil {
+		return []manifest.Issue{}
+	}
+	return issues
+}
+
+type featurePreviewResp struct {
+	ProductID      string              `json:"product_id"`
+	FeatureID      string              `json:"feature_id"`
+	ManifestPath   string              `json:"manifest_path"`
+	File           string              `json:"file,omitempty"` // where codegen writes the wrapper
+	Signature      *manifest.Signature `json:"signature,omitempty"`
+	SignatureError string              `json:"signature_error,omitempty"`
+	Code           string              `json:"code"`
+}
+
+// handleManifestsRoot dispatches /api/manifests/{product}/features/{id}/preview.
+func (s *Server) handleManifestsRoot(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/manifests/"), "/"), "/")
+	if len(parts) == 4 && parts[0] != "" && parts[1] == "features" && parts[2] != "" && parts[3] == "preview" {
+		s.handleFeaturePreview(parts[0], parts[2], w)
+		return
+	}
+	w.WriteHeader(http.StatusNotFound)
+}
+
+// handleFeaturePreview renders the wrapper codegen would generate for one
+// manifest feature. The intercepted function's signature is read from the
+// manifest's module; targets outside it get an empty signature.
+func (s *Server) handleFeaturePreview(productID, featureID string, w http.ResponseWriter) {
+	e, ok := s.manifests.forProduct(productID)
+	if !ok {
+		writeErr(w, http.StatusNotFound, fmt.Errorf("no manifest for product %s", productID))
+		return
+	}
+	f, ok := e.Manifest.Feature(featureID)
+	if !ok {
+		writeErr(w, http.StatusNotFound, fmt.Errorf("feature %s not in %s", featureID, e.Path))
+		return
+	}
+	if f.Intercept == nil {
+		writeErr(w, http.StatusUnprocessableEntity, fmt.Errorf("feature %s has no intercept target", featureID))
+		return
+	}
+
+	resp := featurePreviewResp{ProductID: productID, FeatureID: featureID, ManifestPath: e.Path}
+	var sig manifest.Signature
+	mod, err := manifestModule(e.Path)
+	if err == nil {
+		sig, err = manifest.LookupSignature(mod, *f.Intercept)
+	}
+	if err != nil {
+		resp.SignatureError = err.Error()
+	} else {
+		resp.Signature = &sig
+		rel := strings.TrimPrefix(strings.TrimPrefix(f.Intercept.Package, mod.Path), "/")
+		resp.File = path.Join(rel, "lcc_gen.go")
+	}
+
+	code, err := manifest.Generate(f, sig, manifest.GenOptions{})
+	if err != nil {
+		writeErr(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	resp.Code = string(code)
+	_ = json.NewEncoder(w).Encode(&resp)
+}
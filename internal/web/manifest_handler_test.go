@@ -0,0 +1,135 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testInterceptManifest = `sdk:
+  product_id: %s
+features:
+  - id: pdf_export
+    tier: professional
+    intercept:
+      package: "example.com/app/internal/export"
+      function: "%s"
+    on_deny:
+      action: error
+`
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// testModuleManifests writes a manifest inside a Go module (app, intercepting
+// export.appFunc) and one outside any module (loose), and returns a server
+// loading both.
+func testModuleManifests(t *testing.T, appFunc string) *Server {
+	t.Helper()
+	modDir := t.TempDir()
+	writeTestFile(t, filepath.Join(modDir, "go.mod"), "module example.com/app\n\ngo 1.24\n")
+	writeTestFile(t, filepath.Join(modDir, "internal/export/export.go"), "package export\n\nfunc GeneratePDF(name string) error { return nil }\n")
+	app := filepath.Join(modDir, "configs", "lcc-features.yaml")
+	writeTestFile(t, app, fmt.Sprintf(testInterceptManifest, "app", appFunc))
+
+	loose := filepath.Join(t.TempDir(), "lcc-features.yaml")
+	writeTestFile(t, loose, fmt.Sprintf(testInterceptManifest, "loose", "GeneratePDF"))
+
+	return newTestServer(t, WithManifestPaths([]string{app, loose}))
+}
+
+func TestManifestValidateResolvesManifestModule(t *testing.T) {
+	s := testModuleManifests(t, "GenerateWord")
+
+	rec := httptest.NewRecorder()
+	s.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/manifests/validate", nil))
+	var resp manifestValidateResp
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("validate %d: %v", rec.Code, err)
+	}
+	if resp.Valid || len(resp.Files) != 2 {
+		t.Fatalf("validate = %+v, want 2 files and invalid", resp)
+	}
+
+	app, loose := resp.Files[0], resp.Files[1]
+	found := false
+	for _, issue := range app.Issues {
+		found = found || issue.Rule == "unknown_intercept_function"
+	}
+	if !found || app.TargetsSkipped != "" {
+		t.Errorf("app = %+v, want unknown_intercept_function from its own module", app)
+	}
+	if loose.TargetsSkipped == "" || len(loose.Issues) != 0 {
+		t.Errorf("loose = %+v, want targets_skipped and no issues", loose)
+	}
+}
+
+func TestFeaturePreviewResolvesManifestModule(t *testing.T) {
+	s := testModuleManifests(t, "GeneratePDF")
+
+	for _, tc := range []struct {
+		product, file string
+		signature     bool
+	}{
+		{"app", "internal/export/lcc_gen.go", true},
+		{"loose", "", false},
+	} {
+		rec := httptest.NewRecorder()
+		s.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/manifests/"+tc.product+"/features/pdf_export/preview", nil))
+		var resp featurePreviewResp
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("%s preview %d: %v", tc.product, rec.Code, err)
+		}
+		if (resp.Signature != nil) != tc.signature || resp.File != tc.file {
+			t.Errorf("%s preview = signature %v, file %q (error %q); want signature %v, file %q",
+				tc.product, resp.Signature, resp.File, resp.SignatureError, tc.signature, tc.file)
+		}
+		if !tc.signature && resp.SignatureError == "" {
+			t.Errorf("%s preview has no signature error", tc.product)
+		}
+	}
+}
+
+func TestManifestValidateUploadIgnoresPathModule(t *testing.T) {
+	s := testModuleManifests(t, "GeneratePDF")
+	appPath := s.manifests.list()[0].Path
+
+	// A module the label points into; it has the function the upload names.
+	other := t.TempDir()
+	writeTestFile(t, filepath.Join(other, "go.mod"), "module example.com/app\n\ngo 1.24\n")
+	writeTestFile(t, filepath.Join(other, "internal/export/export.go"), "package export\n\nfunc GenerateWord() {}\n")
+
+	for _, tc := range []struct {
+		label, rule string
+	}{
+		{appPath, "unknown_intercept_function"},                // the configured manifest's module
+		{filepath.Join(other, "m.yaml"), "external_intercept"}, // the server's module, not other
+	} {
+		body, _ := json.Marshal(manifestValidateReq{Path: tc.label, Content: fmt.Sprintf(testInterceptManifest, "app", "GenerateWord")})
+		rec := httptest.NewRecorder()
+		s.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/manifests/validate", bytes.NewReader(body)))
+		var resp manifestValidateResp
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || len(resp.Files) != 1 {
+			t.Fatalf("validate %s %d: %v %s", tc.label, rec.Code, err, rec.Body)
+		}
+		var rules []string
+		for _, issue := range resp.Files[0].Issues {
+			rules = append(rules, issue.Rule)
+		}
+		if len(rules) != 1 || rules[0] != tc.rule {
+			t.Errorf("validate %s = %v, want %s", tc.label, rules, tc.rule)
+		}
+	}
+}
@@ -0,0 +1,317 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"demo-app/internal/manifest"
+)
+
+// EnvManifestPath overrides the manifest search path. Entries are separated
+// by commas or the OS path list separator and may be globs.
+const EnvManifestPath = "LCC_MANIFEST_PATH"
+
+// DefaultManifestPaths is the search path used when neither a flag, the
+// environment nor the persisted config sets one.
+var DefaultManifestPaths = []string{
+	"lcc-features.yaml",
+	"configs/lcc-features.*.yaml",
+}
+
+// DefaultManifestPoll is how often the server re-checks manifests for changes.
+const DefaultManifestPoll = 2 * time.Second
+
+// manifestEntry is one manifest file found on the search path.
+type manifestEntry struct {
+	Path            string             `json:"path"`
+	ProductID       string             `json:"product_id,omitempty"`
+	Features        int                `json:"features"`
+	ModTime         time.Time          `json:"mod_time"`
+	LoadedAt        time.Time          `json:"loaded_at"`
+	Error           string             `json:"error,omitempty"`            // parse error; the file is not used
+	ValidationError string             `json:"validation_error,omitempty"` // invalid relations or versions
+	Shadowed        bool               `json:"shadowed,omitempty"`         // an earlier file has the same product_id
+	Manifest        *manifest.Manifest `json:"-"`
+
+	size int64
+}
+
+// manifestStore caches parsed manifests from a configurable search path and
+// indexes them by sdk.product_id. Refresh re-parses only files whose size or
+// modification time changed.
+type manifestStore struct {
+	mu        sync.RWMutex
+	patterns  []string
+	source    string                    // flag, env, config or default
+	entries   map[string]*manifestEntry // path -> entry
+	order     []string                  // paths in search order
+	byProduct map[string]*manifestEntry // product_id -> first entry
+	refreshed time.Time
+}
+
+func newManifestStore() *manifestStore {
+	return &manifestStore{
+		patterns:  DefaultManifestPaths,
+		source:    "default",
+		entries:   make(map[string]*manifestEntry),
+		byProduct: make(map[string]*manifestEntry),
+	}
+}
+
+// SplitManifestPath splits a search path given as a flag or env value.
+func SplitManifestPath(s string) []string {
+	var out []string
+	for _, p := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == os.PathListSeparator }) {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// setPaths replaces the search path and reloads.
+func (m *manifestStore) setPaths(patterns []string, source string) {
+	m.mu.Lock()
+	m.patterns = append([]string(nil), patterns...)
+	m.source = source
+	m.entries = make(map[string]*manifestEntry)
+	m.refreshed = time.Time{}
+	m.mu.Unlock()
+	m.refresh()
+}
+
+func (m *manifestStore) paths() ([]string, string) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]string(nil), m.patterns...), m.source
+}
+
+// files expands the search path. Literal paths that do not exist are skipped.
+func (m *manifestStore) files() []string {
+	patterns, _ := m.paths()
+	seen := map[string]bool{}
+	var out []string
+	for _, pat := range patterns {
+		matches, err := filepath.Glob(pat)
+		if err != nil {
+			log.Printf("manifest path %q: %v", pat, err)
+			continue
+		}
+		sort.Strings(matches)
+		for _, p := range matches {
+			if !seen[p] && fileExists(p) {
+				seen[p] = true
+				out = append(out, p)
+			}
+		}
+	}
+	return out
+}
+
+// refresh re-reads changed manifests and reports whether anything changed.
+func (m *manifestStore) refresh() bool {
+	files := m.files()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	changed := len(files) != len(m.order)
+	next := make(map[string]*manifestEntry, len(files))
+	for i, p := range files {
+		if !changed && m.order[i] != p {
+			changed = true
+		}
+		st, err := os.Stat(p)
+		if err != nil {
+			changed = true
+			continue
+		}
+		if e, ok := m.entries[p]; ok && e.ModTime.Equal(st.ModTime()) && e.size == st.Size() {
+			next[p] = e
+			continue
+		}
+		changed = true
+		next[p] = loadManifestEntry(p, st)
+	}
+	if !changed && !m.refreshed.IsZero() {
+		return false
+	}
+
+	m.entries = next
+	m.order = files
+	m.byProduct = make(map[string]*manifestEntry)
+	for _, p := range files {
+		e, ok := next[p]
+		if !ok {
+			continue
+		}
+		e.Shadowed = false
+		if e.Manifest == nil || e.ProductID == "" {
+			continue
+		}
+		if _, dup := m.byProduct[e.ProductID]; dup {
+			e.Shadowed = true
+			continue
+		}
+		m.byProduct[e.ProductID] = e
+	}
+	m.refreshed = time.Now()
+	return true
+}
+
+func loadManifestEntry(path string, st os.FileInfo) *manifestEntry {
+	e := &manifestEntry{Path: path, ModTime: st.ModTime(), LoadedAt: time.Now(), size: st.Size()}
+	data, err := os.ReadFile(path)
+	if err == nil {
+		e.Manifest, err = manifest.Parse(data)
+	}
+	if err != nil {
+		e.Error = err.Error()
+		e.Manifest = nil
+		return e
+	}
+	e.ProductID = e.Manifest.SDK.ProductID
+	e.Features = len(e.Manifest.Features)
+	if err := e.Manifest.Validate(); err != nil {
+		e.ValidationError = err.Error()
+	}
+	return e
+}
+
+// ensureLoaded loads the manifests once if no watcher has done so yet.
+func (m *manifestStore) ensureLoaded() {
+	m.mu.RLock()
+	loaded := !m.refreshed.IsZero()
+	m.mu.RUnlock()
+	if !loaded {
+		m.refresh()
+	}
+}
+
+// watch polls the search path every interval until stop is closed.
+func (m *manifestStore) watch(interval time.Duration, stop <-chan struct{}) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-t.C:
+			if m.refresh() {
+				log.Printf("Manifests reloaded (%d file(s))", len(m.list()))
+			}
+		}
+	}
+}
+
+// forProduct returns the manifest declaring productID.
+func (m *manifestStore) forProduct(productID string) (manifestEntry, bool) {
+	m.ensureLoaded()
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	e, ok := m.byProduct[productID]
+	if !ok {
+		return manifestEntry{}, false
+	}
+	return *e, true
+}
+
+// list returns copies of all entries in search order, including unparsable
+// files. The parsed manifests are shared and must not be modified.
+func (m *manifestStore) list() []manifestEntry {
+	m.ensureLoaded()
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := make([]manifestEntry, 0, len(m.order))
+	for _, p := range m.order {
+		if e, ok := m.entries[p]; ok {
+			out = append(out, *e)
+		}
+	}
+	return out
+}
+
+// loaded returns the entries that parsed.
+func (m *manifestStore) loaded() []manifestEntry {
+	var out []manifestEntry
+	for _, e := range m.list() {
+		if e.Manifest != nil {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// resolveManifestPaths picks the search path: explicit (flag) first, then
+// the environment, then the persisted config, then the default.
+func resolveManifestPaths(explicit, persisted []string) ([]string, string) {
+	if len(explicit) > 0 {
+		return explicit, "flag"
+	}
+	if env := SplitManifestPath(os.Getenv(EnvManifestPath)); len(env) > 0 {
+		return env, "env"
+	}
+	if len(persisted) > 0 {
+		return persisted, "config"
+	}
+	return DefaultManifestPaths, "default"
+}
+
+type manifestsResp struct {
+	Paths     []string          `json:"paths"`
+	Source    string            `json:"source"`
+	PollEvery string            `json:"poll_every,omitempty"`
+	Products  map[string]string `json:"products"` // product_id -> path
+	Manifests []manifestEntry   `json:"manifests"`
+}
+
+// handleManifests lists the manifests on the search path with their parse
+// errors. POST forces a reload.
+func (s *Server) handleManifests(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		s.manifests.refresh()
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	paths, source := s.manifests.paths()
+	resp := manifestsResp{Paths: paths, Source: source, Products: map[string]string{}, Manifests: s.manifests.list()}
+	if s.manifestPoll > 0 {
+		resp.PollEvery = s.manifestPoll.String()
+	}
+	for _, e := range resp.Manifests {
+		if e.ProductID != "" && !e.Shadowed {
+			resp.Products[e.ProductID] = e.Path
+		}
+	}
+	_ = json.NewEncoder(w).Encode(&resp)
+}
+
+// WithManifestPaths sets the manifest search path, overriding the
+// environment and persisted config.
+func WithManifestPaths(patterns []string) ServerOption {
+	return func(s *Server) { s.manifestPaths = patterns }
+}
+
+// WithManifestPolling re-checks manifests for changes every interval; zero
+// disables polling.
+func WithManifestPolling(interval time.Duration) ServerOption {
+	return func(s *Server) { s.manifestPoll = interval }
+}
+
+// describe is logged at startup.
+func (m *manifestStore) describe() string {
+	paths, source := m.paths()
+	return fmt.Sprintf("%s (from %s)", strings.Join(paths, ", "), source)
+}
@@ -0,0 +1,198 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func storeManifest(product string, features ...string) string {
+	out := "sdk:\n  product_id: " + product + "\nfeatures:\n"
+	for _, f := range features {
+		out += "  - id: " + f + "\n"
+	}
+	return out
+}
+
+func TestManifestStoreRefresh(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.yaml")
+	b := filepath.Join(dir, "b.yaml")
+	c := filepath.Join(dir, "sub", "c.yaml")
+	m := newManifestStore()
+	m.setPaths([]string{filepath.Join(dir, "*.yaml"), filepath.Join(dir, "sub", "*.yaml")}, "flag")
+
+	// Every write gets a new mtime, so an edit that keeps the size is still
+	// a change.
+	mtime := time.Now().Add(-time.Hour)
+	write := func(path, content string) {
+		writeTestFile(t, path, content)
+		mtime = mtime.Add(time.Second)
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, step := range []struct {
+		name     string
+		do       func()
+		changed  bool
+		files    string            // list() paths, base names
+		products map[string]string // forProduct: product -> base name ("" for none)
+		shadowed string            // base names of shadowed entries
+		broken   string            // base names of entries with a parse error
+	}{
+		{
+			name:     "empty",
+			do:       func() {},
+			changed:  false,
+			products: map[string]string{"alpha": ""},
+		},
+		{
+			name:     "add",
+			do:       func() { write(a, storeManifest("alpha", "f1")); write(c, storeManifest("gamma", "f1")) },
+			changed:  true,
+			files:    "a.yaml,c.yaml",
+			products: map[string]string{"alpha": "a.yaml", "gamma": "c.yaml"},
+		},
+		{
+			name:     "unchanged",
+			do:       func() {},
+			changed:  false,
+			files:    "a.yaml,c.yaml",
+			products: map[string]string{"alpha": "a.yaml"},
+		},
+		{
+			name:     "duplicate product",
+			do:       func() { write(b, storeManifest("alpha", "f2")) },
+			changed:  true,
+			files:    "a.yaml,b.yaml,c.yaml",
+			products: map[string]string{"alpha": "a.yaml"},
+			shadowed: "b.yaml",
+		},
+		{
+			name:     "edit same size",
+			do:       func() { write(a, storeManifest("omega", "f1")) },
+			changed:  true,
+			files:    "a.yaml,b.yaml,c.yaml",
+			products: map[string]string{"alpha": "b.yaml", "omega": "a.yaml"},
+		},
+		{
+			name:     "unparsable",
+			do:       func() { write(c, "features: [") },
+			changed:  true,
+			files:    "a.yaml,b.yaml,c.yaml",
+			products: map[string]string{"gamma": ""},
+			broken:   "c.yaml",
+		},
+		{
+			name: "remove",
+			do: func() {
+				if err := os.Remove(a); err != nil {
+					t.Fatal(err)
+				}
+			},
+			changed:  true,
+			files:    "b.yaml,c.yaml",
+			products: map[string]string{"alpha": "b.yaml", "omega": ""},
+			broken:   "c.yaml",
+		},
+	} {
+		step.do()
+		if got := m.refresh(); got != step.changed {
+			t.Errorf("%s: refresh() = %v, want %v", step.name, got, step.changed)
+		}
+
+		var files, shadowed, broken []string
+		for _, e := range m.list() {
+			files = append(files, filepath.Base(e.Path))
+			if e.Shadowed {
+				shadowed = append(shadowed, filepath.Base(e.Path))
+			}
+			if e.Error != "" {
+				broken = append(broken, filepath.Base(e.Path))
+			}
+		}
+		if got := strings.Join(files, ","); got != step.files {
+			t.Errorf("%s: list() = %s, want %s", step.name, got, step.files)
+		}
+		if got := strings.Join(shadowed, ","); got != step.shadowed {
+			t.Errorf("%s: shadowed = %s, want %s", step.name, got, step.shadowed)
+		}
+		if got := strings.Join(broken, ","); got != step.broken {
+			t.Errorf("%s: parse errors in %s, want %s", step.name, got, step.broken)
+		}
+		for product, want := range step.products {
+			e, ok := m.forProduct(product)
+			got := ""
+			if ok {
+				got = filepath.Base(e.Path)
+			}
+			if got != want {
+				t.Errorf("%s: forProduct(%s) = %q, want %q", step.name, product, got, want)
+			}
+		}
+	}
+}
+
+func TestResolveManifestPaths(t *testing.T) {
+	for _, tc := range []struct {
+		explicit, persisted []string
+		env                 string
+		want, source        string
+	}{
+		{[]string{"flag.yaml"}, []string{"config.yaml"}, "env.yaml", "flag.yaml", "flag"},
+		{nil, []string{"config.yaml"}, "env.yaml, other/*.yaml", "env.yaml,other/*.yaml", "env"},
+		{nil, []string{"config.yaml"}, "", "config.yaml", "config"},
+		{nil, nil, " , ", strings.Join(DefaultManifestPaths, ","), "default"},
+	} {
+		t.Setenv(EnvManifestPath, tc.env)
+		paths, source := resolveManifestPaths(tc.explicit, tc.persisted)
+		if got := strings.Join(paths, ","); got != tc.want || source != tc.source {
+			t.Errorf("resolveManifestPaths(%v, %v) with env %q = %s from %s, want %s from %s",
+				tc.explicit, tc.persisted, tc.env, got, source, tc.want, tc.source)
+		}
+	}
+}
+
+func TestSetConfigManifestPathsPrecedence(t *testing.T) {
+	t.Setenv(EnvManifestPath, "")
+	for _, tc := range []struct {
+		name         string
+		flag         []string
+		want, source string
+	}{
+		{"flag wins", []string{"flag.yaml"}, "flag.yaml", "flag"},
+		{"saved applies", nil, "saved.yaml", "config"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newTestServer(t, WithManifestPaths(tc.flag))
+			cfgFile, err := s.configPath()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rec := httptest.NewRecorder()
+			s.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/config", strings.NewReader(`{"manifest_paths":["saved.yaml"]}`)))
+			var resp setConfigResp
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("POST /api/config %d: %v", rec.Code, err)
+			}
+			if got := strings.Join(resp.ManifestPaths, ","); got != tc.want || resp.ManifestPathSource != tc.source {
+				t.Errorf("response paths = %s from %s, want %s from %s", got, resp.ManifestPathSource, tc.want, tc.source)
+			}
+			if paths, source := s.manifests.paths(); strings.Join(paths, ",") != tc.want || source != tc.source {
+				t.Errorf("search path = %v from %s, want %s from %s", paths, source, tc.want, tc.source)
+			}
+			data, err := os.ReadFile(cfgFile)
+			if err != nil || !strings.Contains(string(data), "saved.yaml") {
+				t.Errorf("saved config = %s, %v; want the posted manifest_paths", data, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package web
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"demo-app/internal/metrics"
+
+	lccclient "github.com/yourorg/lcc-sdk/pkg/client"
+)
+
+// simulationRuns is the gauge of simulations by status.
+const simulationRuns = "lcc_simulation_runs"
+
+// sdkMetrics records the SDK calls made on behalf of /api/sim and the
+// simulations; /metrics serves it.
+var sdkMetrics = func() *metrics.Registry {
+	r := metrics.NewSDKRegistry()
+	r.NewGauge(simulationRuns, "Simulations by status.")
+	return r
+}()
+
+// handleMetrics serves /metrics in the Prometheus text format.
+func (s *Server) handleMetrics() http.Handler {
+	return sdkMetrics.Handler(s.collectMetrics)
+}
+
+// collectMetrics refreshes the gauges computed from server state.
+func (s *Server) collectMetrics() {
+	// Registered instances: the per-instance registrations plus the
+	// clients registered through product selection, by instance ID
+	byProduct := map[string]map[string]bool{}
+	add := func(product, instanceID string) {
+		if byProduct[product] == nil {
+			byProduct[product] = map[string]bool{}
+		}
+		byProduct[product][instanceID] = true
+	}
+	s.mu.RLock()
+	for _, inst := range s.instances {
+		add(inst.ProductID, inst.InstanceID)
+	}
+	for pid, cli := range s.clients {
+		add(pid, cli.GetInstanceID())
+	}
+	s.mu.RUnlock()
+	sdkMetrics.Reset(metrics.RegisteredInstances)
+	for pid, ids := range byProduct {
+		sdkMetrics.Set(metrics.RegisteredInstances, float64(len(ids)), "product", pid)
+	}
+
+	sdkMetrics.Reset(simulationRuns)
+	for status, n := range s.sims.countByStatus() {
+		sdkMetrics.Set(simulationRuns, float64(n), "status", string(status))
+	}
+}
+
+// meteredBackend records the calls of a LicenseBackend in sdkMetrics.
+type meteredBackend struct {
+	LicenseBackend
+	product string
+}
+
+func (b meteredBackend) CheckFeature(featureID string) (*lccclient.FeatureStatus, error) {
+	start := time.Now()
+	st, err := b.LicenseBackend.CheckFeature(featureID)
+	sdkMetrics.ObserveCall(b.product, "check_feature", featureID, start, err == nil && st.Enabled, err)
+	if err == nil && st.Quota != nil {
+		sdkMetrics.Set(metrics.QuotaRemaining, float64(st.Quota.Remaining), "product", b.product, "feature", featureID)
+	}
+	return st, err
+}
+
+func (b meteredBackend) Consume(amount int) (bool, int, error) {
+	start := time.Now()
+	allowed, remaining, err := b.LicenseBackend.Consume(amount)
+	sdkMetrics.ObserveCall(b.product, "consume", "", start, allowed, err)
+	if err == nil {
+		sdkMetrics.Set(metrics.QuotaRemaining, float64(remaining), "product", b.product)
+	}
+	return allowed, remaining, err
+}
+
+func (b meteredBackend) CheckTPS() (bool, float64, error) {
+	start := time.Now()
+	allowed, max, err := b.LicenseBackend.CheckTPS()
+	sdkMetrics.ObserveCall(b.product, "check_tps", "", start, allowed, err)
+	return allowed, max, err
+}
+
+func (b meteredBackend) CheckCapacity(current int) (bool, int, error) {
+	start := time.Now()
+	allowed, max, err := b.LicenseBackend.CheckCapacity(current)
+	sdkMetrics.ObserveCall(b.product, "check_capacity", "", start, allowed, err)
+	return allowed, max, err
+}
+
+// AcquireSlot also keeps the active slot gauge until the slot is released.
+func (b meteredBackend) AcquireSlot() (func(), bool, error) {
+	start := time.Now()
+	release, ok, err := b.LicenseBackend.AcquireSlot()
+	sdkMetrics.ObserveCall(b.product, "acquire_slot", "", start, ok, err)
+	if err != nil || !ok {
+		return release, ok, err
+	}
+	sdkMetrics.Add(metrics.ActiveSlots, 1, "product", b.product)
+	var once sync.Once
+	return func() {
+		once.Do(func() { sdkMetrics.Add(metrics.ActiveSlots, -1, "product", b.product) })
+		release()
+	}, true, nil
+}
@@ -1,5 +1,16 @@
 package web
 
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"demo-app/internal/deps"
+	"demo-app/internal/license"
+	"demo-app/internal/semver"
+)
+
 // TierDefinition represents a product tier with its features and limits
 type TierDefinition struct {
 	ID          string                 `json:"id"`
@@ -20,6 +31,24 @@ type FeatureInfo struct {
 	Description  string `json:"description,omitempty"`
 	RequiredTier string `json:"required_tier,omitempty"`
 	Reason       string `json:"reason,omitempty"`
+
+	// Trial optionally grants a disabled feature for a limited time.
+	Trial *license.Trial `json:"trial,omitempty"`
+
+	// Requires lists features this one only works with; ConflictsWith lists
+	// features that may not be enabled together with it.
+	Requires      []string `json:"requires,omitempty"`
+	ConflictsWith []string `json:"conflicts_with,omitempty"`
+
+	// MinVersion/MaxVersion bound the product versions (semver, inclusive)
+	// the feature is available in; empty means unbounded.
+	MinVersion string `json:"min_version,omitempty"`
+	MaxVersion string `json:"max_version,omitempty"`
+}
+
+// Versions returns the product versions the feature is available in.
+func (f FeatureInfo) Versions() semver.Range {
+	return semver.Range{Min: f.MinVersion, Max: f.MaxVersion}
 }
 
 var (
@@ -31,6 +60,11 @@ var (
 
 func init() {
 	initializeTiers()
+	for _, tier := range AllTiers {
+		if err := ValidateTier(tier); err != nil {
+			panic(fmt.Sprintf("invalid tier definition %s: %v", tier.ID, err))
+		}
+	}
 }
 
 func initializeTiers() {
@@ -55,6 +89,7 @@ func initializeTiers() {
 				Description:  "ML-powered analytics with predictive models",
 				RequiredTier: "professional",
 				Reason:       "requires_professional",
+				Trial:        &license.Trial{DurationDays: 14, UsageCap: 100},
 			},
 			"pdf_export": {
 				ID:           "pdf_export",
@@ -77,6 +112,7 @@ func initializeTiers() {
 				Name:         "Custom Dashboard",
 				Enabled:      false,
 				Description:  "Build custom dashboards",
+				Requires:     []string{"basic_reports"},
 				RequiredTier: "enterprise",
 				Reason:       "requires_enterprise",
 			},
@@ -88,6 +124,26 @@ func initializeTiers() {
 				RequiredTier: "professional",
 				Reason:       "requires_professional",
 			},
+			"cloud_export": {
+				ID:            "cloud_export",
+				Name:          "Cloud Export",
+				Enabled:       false,
+				Description:   "Export reports to managed cloud storage",
+				MinVersion:    "2.0.0",
+				RequiredTier:  "enterprise",
+				Reason:        "requires_enterprise",
+				ConflictsWith: []string{"onprem_export"},
+			},
+			"onprem_export": {
+				ID:            "onprem_export",
+				Name:          "On-Prem Export",
+				Enabled:       false,
+				Description:   "Export reports to self-hosted storage",
+				MaxVersion:    "3.0.0",
+				RequiredTier:  "professional",
+				Reason:        "requires_professional",
+				ConflictsWith: []string{"cloud_export"},
+			},
 		},
 	}
 
@@ -130,6 +186,7 @@ func initializeTiers() {
 				Name:         "Custom Dashboard",
 				Enabled:      false,
 				Description:  "Build custom dashboards",
+				Requires:     []string{"basic_reports"},
 				RequiredTier: "enterprise",
 				Reason:       "requires_enterprise",
 			},
@@ -139,6 +196,24 @@ func initializeTiers() {
 				Enabled:     true,
 				Description: "REST API access",
 			},
+			"cloud_export": {
+				ID:            "cloud_export",
+				Name:          "Cloud Export",
+				Enabled:       false,
+				Description:   "Export reports to managed cloud storage",
+				MinVersion:    "2.0.0",
+				RequiredTier:  "enterprise",
+				Reason:        "requires_enterprise",
+				ConflictsWith: []string{"onprem_export"},
+			},
+			"onprem_export": {
+				ID:            "onprem_export",
+				Name:          "On-Prem Export",
+				Enabled:       true,
+				Description:   "Export reports to self-hosted storage",
+				MaxVersion:    "3.0.0",
+				ConflictsWith: []string{"cloud_export"},
+			},
 		},
 	}
 
@@ -179,6 +254,7 @@ func initializeTiers() {
 				Name:        "Custom Dashboard",
 				Enabled:     true,
 				Description: "Build custom dashboards",
+				Requires:    []string{"basic_reports"},
 			},
 			"api_access": {
 				ID:          "api_access",
@@ -186,12 +262,68 @@ func initializeTiers() {
 				Enabled:     true,
 				Description: "REST API access",
 			},
+			// Enterprise is the cloud-hosted edition, so it exports to the
+			// cloud; the on-prem variant is mutually exclusive.
+			"cloud_export": {
+				ID:            "cloud_export",
+				Name:          "Cloud Export",
+				Enabled:       true,
+				Description:   "Export reports to managed cloud storage",
+				MinVersion:    "2.0.0",
+				ConflictsWith: []string{"onprem_export"},
+			},
+			"onprem_export": {
+				ID:            "onprem_export",
+				Name:          "On-Prem Export",
+				Enabled:       false,
+				Description:   "Export reports to self-hosted storage",
+				MaxVersion:    "3.0.0",
+				Reason:        "conflicts_with_cloud_export",
+				ConflictsWith: []string{"cloud_export"},
+			},
 		},
 	}
 
 	AllTiers = []*TierDefinition{BasicTier, ProfessionalTier, EnterpriseTier}
 }
 
+// ValidateTier checks the feature relations of a tier: references must be
+// known and acyclic, and conflicting features may not both be enabled.
+func ValidateTier(tier *TierDefinition) error {
+	nodes := tierDependencyNodes(tier)
+	errs := deps.Validate(nodes)
+	errs = append(errs, deps.ValidateEnabled(nodes, func(id string) bool { return tier.Features[id].Enabled })...)
+	for _, id := range sortedFeatureIDs(tier) {
+		if err := tier.Features[id].Versions().Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("feature %s: %w", id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// TierDependencyGraph returns the requires/conflicts_with graph of a tier.
+func TierDependencyGraph(tier *TierDefinition) deps.Graph {
+	return deps.Build(tierDependencyNodes(tier))
+}
+
+func tierDependencyNodes(tier *TierDefinition) []deps.Node {
+	nodes := make([]deps.Node, 0, len(tier.Features))
+	for _, id := range sortedFeatureIDs(tier) {
+		f := tier.Features[id]
+		nodes = append(nodes, deps.Node{ID: id, Requires: f.Requires, ConflictsWith: f.ConflictsWith})
+	}
+	return nodes
+}
+
+func sortedFeatureIDs(tier *TierDefinition) []string {
+	ids := make([]string, 0, len(tier.Features))
+	for id := range tier.Features {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
 // GetTierByID returns a tier definition by its ID
 func GetTierByID(tierID string) *TierDefinition {
 	switch tierID {
@@ -206,28 +338,66 @@ func GetTierByID(tierID string) *TierDefinition {
 	}
 }
 
-// GetLicenseJSON returns the license JSON for a tier
+// GetLicenseJSON returns the license JSON for a tier, issued now for
+// DefaultLicenseValidity.
 func GetLicenseJSON(tier *TierDefinition) map[string]interface{} {
 	features := make(map[string]interface{})
-	limits := make(map[string]interface{})
 	
 	// Features only contain enabled/disabled status
 	for id, feature := range tier.Features {
-		features[id] = map[string]interface{}{
+		entry := map[string]interface{}{
 			"enabled": feature.Enabled,
 		}
+		if feature.Trial != nil {
+			entry["trial"] = feature.Trial
+		}
+		features[id] = entry
 	}
 	
+	issuedAt := time.Now().UTC().Truncate(time.Second)
+	return map[string]interface{}{
+		"product_id":   tier.ProductID,
+		"product_name": tier.Name,
+		"tier":         tier.Tier,
+		"version":      "1.0.0",
+		"issued_at":    issuedAt.Format(time.RFC3339),
+		"expires_at":   issuedAt.Add(DefaultLicenseValidity).Format(time.RFC3339),
+		"features":     features,
+		"limits":       tierLimits(tier),
+	}
+}
+
+// NewLicenseDocument builds the canonical, signable license document for a
+// tier issued to customer. It carries the same features and limits as
+// GetLicenseJSON but with a real validity window.
+func NewLicenseDocument(tier *TierDefinition, customer string, issuedAt time.Time, validity time.Duration) license.Document {
+	features := make(map[string]license.Feature, len(tier.Features))
+	for id, feature := range tier.Features {
+		features[id] = license.Feature{Enabled: feature.Enabled, Trial: feature.Trial}
+	}
+	issuedAt = issuedAt.UTC().Truncate(time.Second)
+	return license.Document{
+		LicenseID:   license.NewLicenseID(),
+		ProductID:   tier.ProductID,
+		ProductName: tier.Name,
+		Tier:        tier.Tier,
+		Customer:    customer,
+		Version:     "1.0.0",
+		IssuedAt:    issuedAt,
+		ExpiresAt:   issuedAt.Add(validity),
+		Features:    features,
+		Limits:      tierLimits(tier),
+	}
+}
+
+// tierLimits returns the product-level limits granted by a tier.
+func tierLimits(tier *TierDefinition) map[string]interface{} {
 	// Limits are product-level configurations
 	// Multiple limits can exist at product level
 	switch tier.ID {
-	case "basic":
-		// Basic tier has no limits
-		limits = map[string]interface{}{}
-		
 	case "professional":
 		// Product-level limits: all features share these limits
-		limits = map[string]interface{}{
+		return map[string]interface{}{
 			"quota": map[string]interface{}{
 				"max":      50000,
 				"used":     0,
@@ -237,11 +407,24 @@ func GetLicenseJSON(tier *TierDefinition) map[string]interface{} {
 			},
 			"max_tps": 100.0,
 			"max_concurrency": 10,
+			"seats": map[string]interface{}{
+				"max":                    10,
+				"reassign_cooldown_days": 7,
+			},
+			"floating": map[string]interface{}{
+				"max":               5,
+				"lease_ttl_seconds": 60,
+			},
+			"volume": map[string]interface{}{
+				"max":    int64(10 << 30), // 10 GiB processed per month
+				"unit":   "bytes",
+				"window": "monthly",
+			},
 		}
 		
 	case "enterprise":
 		// Product-level limits: all features share these limits
-		limits = map[string]interface{}{
+		return map[string]interface{}{
 			"quota": map[string]interface{}{
 				"max":      500000,
 				"used":     0,
@@ -252,18 +435,24 @@ func GetLicenseJSON(tier *TierDefinition) map[string]interface{} {
 			"max_tps": 500.0,
 			"max_capacity": 100,
 			"max_concurrency": 50,
+			"seats": map[string]interface{}{
+				"max":                    100,
+				"reassign_cooldown_days": 30,
+			},
+			"floating": map[string]interface{}{
+				"max":               25,
+				"lease_ttl_seconds": 120,
+			},
+			"volume": map[string]interface{}{
+				"max":    int64(1 << 40), // 1 TiB processed per month
+				"unit":   "bytes",
+				"window": "monthly",
+			},
 		}
-	}
-	
-	return map[string]interface{}{
-		"product_id":   tier.ProductID,
-		"product_name": tier.Name,
-		"tier":         tier.Tier,
-		"version":      "1.0.0",
-		"issued_at":    "2025-01-21T00:00:00Z",
-		"expires_at":   "2026-01-21T00:00:00Z",
-		"features":     features,
-		"limits":       limits,
+
+	default:
+		// Basic tier has no limits
+		return map[string]interface{}{}
 	}
 }
 
@@ -289,6 +478,20 @@ limits:
   
   max_concurrency: 10
 
+  seats:
+    max: 10
+    reassign_cooldown_days: 7
+
+  floating:
+    max: 5
+    lease_ttl_seconds: 60
+
+  volume:
+    max: 10737418240   # 10 GiB per window
+    unit: bytes        # or: records
+    window: monthly
+    # Processed volume is reported in code via analytics.SetVolumeConsumer
+
 # Features (only define interception points)
 features:
   - id: basic_reports
@@ -346,8 +549,39 @@ features:
       message: "API access requires Professional tier or higher"`
 }
 
-// CheckFeatureForTier simulates checking a feature for a specific tier
-func CheckFeatureForTier(tier *TierDefinition, featureID string) map[string]interface{} {
+// checkFeatureForVersion is checkFeature for an instance registered at
+// version: a feature whose version range excludes it is denied with
+// version_unsupported. An empty version skips version gating.
+func (t *trialTracker) checkFeatureForVersion(tier *TierDefinition, featureID, version string, now time.Time) map[string]interface{} {
+	if feature, ok := tier.Features[featureID]; ok && version != "" && !feature.Versions().IsZero() {
+		in, err := feature.Versions().Contains(version)
+		if err != nil {
+			return map[string]interface{}{
+				"enabled": false,
+				"reason":  "invalid_version",
+				"error":   err.Error(),
+			}
+		}
+		if !in {
+			return map[string]interface{}{
+				"enabled":            false,
+				"reason":             semver.ReasonVersionUnsupported,
+				"version":            version,
+				"supported_versions": feature.Versions().String(),
+			}
+		}
+	}
+	result := t.checkFeature(tier, featureID, now)
+	if version != "" {
+		result["version"] = version
+	}
+	return result
+}
+
+// checkFeature simulates checking a feature for a tier at now against the
+// trial usage in t. A disabled feature with a trial is enabled while the
+// trial is running; checking peeks at the trial without using it.
+func (t *trialTracker) checkFeature(tier *TierDefinition, featureID string, now time.Time) map[string]interface{} {
 	feature, exists := tier.Features[featureID]
 	if !exists {
 		return map[string]interface{}{
@@ -359,6 +593,27 @@ func CheckFeatureForTier(tier *TierDefinition, featureID string) map[string]inte
 	result := map[string]interface{}{
 		"enabled": feature.Enabled,
 	}
+
+	if !feature.Enabled && feature.Trial != nil {
+		st := t.evaluate(tier, featureID, *feature.Trial, now, false)
+		result["trial"] = st
+		if st.Active {
+			if missing := t.missingDependencies(tier, featureID, now); len(missing) > 0 {
+				result["reason"] = deps.ReasonDependencyMissing
+				result["missing"] = missing
+				return result
+			}
+			result["enabled"] = true
+			result["reason"] = license.ReasonTrial
+			return result
+		}
+		result["reason"] = st.Reason
+		if feature.RequiredTier != "" {
+			result["required_tier"] = feature.RequiredTier
+			result["current_tier"] = tier.Tier
+		}
+		return result
+	}
 	
 	if !feature.Enabled {
 		result["reason"] = feature.Reason
@@ -366,9 +621,31 @@ func CheckFeatureForTier(tier *TierDefinition, featureID string) map[string]inte
 			result["required_tier"] = feature.RequiredTier
 			result["current_tier"] = tier.Tier
 		}
+		if len(feature.ConflictsWith) > 0 {
+			result["conflicts_with"] = feature.ConflictsWith
+		}
+	} else if missing := t.missingDependencies(tier, featureID, now); len(missing) > 0 {
+		result["enabled"] = false
+		result["reason"] = deps.ReasonDependencyMissing
+		result["missing"] = missing
 	} else {
 		result["reason"] = "ok"
 	}
 	
 	return result
 }
+
+// missingDependencies lists the features featureID requires that are neither
+// enabled in the tier nor available through an active trial.
+func (t *trialTracker) missingDependencies(tier *TierDefinition, featureID string, now time.Time) []string {
+	return deps.Missing(featureID, tierDependencyNodes(tier), func(id string) bool {
+		f, ok := tier.Features[id]
+		if !ok {
+			return false
+		}
+		if f.Enabled {
+			return true
+		}
+		return f.Trial != nil && t.evaluate(tier, id, *f.Trial, now, false).Active
+	})
+}
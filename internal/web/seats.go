@@ -0,0 +1,131 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"demo-app/internal/offline"
+)
+
+// seatPool returns the named-user seat pool of a product instance, or nil if
+// its tier grants no seats. Offline products use their evaluator's pool (an
+// offline product has one instance); registered instances get a local pool
+// each, sized from the tier limits, since the SDK client has no seat API.
+func (s *Server) seatPool(productID, instanceID string) *offline.SeatPool {
+	if s.offline {
+		ev, err := s.offlineEvaluator(productID)
+		if err != nil {
+			return nil
+		}
+		return ev.Seats()
+	}
+
+	key := seatPoolKey(productID, instanceID)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if pool, ok := s.seatPools[key]; ok {
+		return pool
+	}
+	cfg := tierLimitConfig(productID, "seats")
+	if cfg == nil {
+		return nil
+	}
+	max, _ := cfg["max"].(int)
+	days, _ := cfg["reassign_cooldown_days"].(int)
+	pool := offline.NewSeatPool(max, time.Duration(days)*24*time.Hour, s.now)
+	s.seatPools[key] = pool
+	return pool
+}
+
+func seatPoolKey(productID, instanceID string) string {
+	return productID + "/" + instanceID
+}
+
+// seatInstance resolves the instance of a seats request: the instance_id
+// query parameter, which must name an instance registered for productID, or
+// else the instance behind the product's backend.
+func (s *Server) seatInstance(cli LicenseBackend, productID string, r *http.Request) (string, error) {
+	current := cli.GetInstanceID()
+	id := r.URL.Query().Get("instance_id")
+	if id == "" || id == current {
+		return current, nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, inst := range s.instances {
+		if inst.InstanceID == id && inst.ProductID == productID {
+			return id, nil
+		}
+	}
+	return "", fmt.Errorf("instance %s is not registered for product %s", id, productID)
+}
+
+// tierLimitConfig returns a nested limit (e.g. "seats") from the tier of a
+// product, or nil if the tier does not grant it.
+func tierLimitConfig(productID, key string) map[string]interface{} {
+	tier := tierForProduct(productID)
+	if tier == nil {
+		return nil
+	}
+	cfg, _ := tierLimits(tier)[key].(map[string]interface{})
+	return cfg
+}
+
+type seatsReq struct {
+	Op     string `json:"op"` // assign | revoke
+	UserID string `json:"user_id"`
+}
+
+type seatsResp struct {
+	InstanceID string             `json:"instance_id"`
+	Allowed    bool               `json:"allowed"`
+	Reason     string             `json:"reason"`
+	Seats      offline.SeatStatus `json:"seats"`
+	Warning    string             `json:"warning,omitempty"`
+}
+
+// handleSeats lists (GET) or assigns/revokes (POST) the named-user seats of
+// a product instance: /api/sim/{product}/seats[?instance_id=]
+func (s *Server) handleSeats(cli LicenseBackend, productID, warning string, w http.ResponseWriter, r *http.Request) {
+	instanceID, err := s.seatInstance(cli, productID, r)
+	if err != nil {
+		writeErr(w, http.StatusNotFound, err)
+		return
+	}
+	pool := s.seatPool(productID, instanceID)
+	if pool == nil {
+		writeErr(w, http.StatusNotFound, fmt.Errorf("product %s has no seat limit", productID))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		_ = json.NewEncoder(w).Encode(&seatsResp{InstanceID: instanceID, Allowed: true, Reason: offline.ReasonOK, Seats: pool.Status(), Warning: warning})
+	case http.MethodPost:
+		var req seatsReq
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeErr(w, http.StatusBadRequest, fmt.Errorf("invalid json: %w", err))
+			return
+		}
+		if req.UserID == "" {
+			writeErr(w, http.StatusBadRequest, fmt.Errorf("user_id is required"))
+			return
+		}
+		var allowed bool
+		var reason string
+		switch req.Op {
+		case "assign":
+			allowed, reason = pool.Assign(req.UserID)
+		case "revoke":
+			allowed, reason = pool.Revoke(req.UserID)
+		default:
+			writeErr(w, http.StatusBadRequest, fmt.Errorf("op must be assign or revoke"))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(&seatsResp{InstanceID: instanceID, Allowed: allowed, Reason: reason, Seats: pool.Status(), Warning: warning})
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
@@ -10,8 +10,13 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"demo-app/internal/license"
+	"demo-app/internal/offline"
+	"demo-app/internal/semver"
+
 	"github.com/yourorg/lcc-sdk/pkg/auth"
 	lccclient "github.com/yourorg/lcc-sdk/pkg/client"
 	lccconfig "github.com/yourorg/lcc-sdk/pkg/config"
@@ -36,31 +41,95 @@ type Server struct {
 	lastProducts  []PublicProduct              // cached latest products listing
 	instances     map[string]*Instance        // instanceID -> Instance (multi-instance support)
 	instanceKeys  map[string]*auth.KeyPair    // instanceID -> KeyPair
+
+	offline      bool                           // serve /api/sim/* from offline evaluators
+	offlineEvals map[string]*offline.Evaluator // productID -> evaluator
+
+	terms       map[string]*licenseTerm         // productID -> modeled license term
+	seatPools   map[string]*offline.SeatPool    // productID/instanceID -> seats (registered products)
+	leasePools  map[string]*offline.LeasePool   // productID -> floating leases (registered products)
+	volumes     map[string]*offline.VolumeMeter // productID -> data volume (registered products)
+	versions    map[string]string               // instanceID -> registered product version
+	trials      *trialTracker                   // trial usage per tier/feature
+	sims        *SimulationManager              // simulations started through /api/simulation
+
+	manifests          *manifestStore // loaded manifests on the search path
+	manifestPaths      []string       // manifest search path from WithManifestPaths
+	savedManifestPaths []string       // manifest search path from the persisted config
+	manifestPoll       time.Duration  // manifest change polling interval (0: off)
+	dataDir            string         // config file and keys (WithDataDir; default DefaultDataDir)
+	configFile         string         // persisted config (WithConfigFile; default <data dir>/config.json)
+	staticDir          string         // SPA assets on disk (WithStaticDir; "" for the embedded ones)
+	assets             *assets
+	clock       func() time.Time                // wall clock (time.Now)
+	clockOffset atomic.Int64                    // simulated time offset (ns)
+
+	closing   atomic.Bool   // set by Drain and Close; fails readiness
+	closeOnce sync.Once
+	stop      chan struct{} // closed by Close; stops background work
 }
 
-func NewServer() *Server {
+func NewServer(opts ...ServerOption) *Server {
 	s := &Server{
 		mux:           http.NewServeMux(),
 		clients:       make(map[string]*lccclient.Client),
 		instances:     make(map[string]*Instance),
 		instanceKeys:  make(map[string]*auth.KeyPair),
+		offlineEvals:  make(map[string]*offline.Evaluator),
+		terms:         make(map[string]*licenseTerm),
+		seatPools:     make(map[string]*offline.SeatPool),
+		leasePools:    make(map[string]*offline.LeasePool),
+		volumes:       make(map[string]*offline.VolumeMeter),
+		versions:      make(map[string]string),
+		trials:        newTrialTracker(),
+		sims:          NewSimulationManager(),
+		clock:         time.Now,
+		dataDir:       DefaultDataDir(),
+		manifests:     newManifestStore(),
+		stop:          make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	s.assets = newAssets(s.staticDir)
 	s.routes()
 	s.loadConfig()
+	s.applyManifestPaths()
+	if s.manifestPoll > 0 {
+		go s.manifests.watch(s.manifestPoll, s.stop)
+	}
 	return s
 }
 
+// applyManifestPaths points the manifest store at the configured search path.
+func (s *Server) applyManifestPaths() {
+	s.mu.RLock()
+	paths, source := resolveManifestPaths(s.manifestPaths, s.savedManifestPaths)
+	s.mu.RUnlock()
+	s.manifests.setPaths(paths, source)
+	log.Printf("Manifest search path: %s", s.manifests.describe())
+}
+
 func (s *Server) Router() http.Handler { return s.mux }
 
 func (s *Server) routes() {
 	// New SPA UI
-	s.mux.Handle("/static/", http.StripPrefix("/static/", http.FileServer(http.Dir("static"))))
+	s.mux.Handle("/static/", s.assets.handler())
 	s.mux.HandleFunc("/", s.handleSPA)
 	
+	// Guided demo: discover -> configure -> runtime
+	s.mux.HandleFunc("/webdemo/", s.handleWebdemo)
+	s.mux.HandleFunc("/api/webdemo/catalog", s.handleWebdemoCatalog)
+
 	// Old HTML pages (kept for backwards compatibility)
 	s.mux.HandleFunc("/old/", s.handleIndex)
 	s.mux.HandleFunc("/product/", s.handleProductPage)
 	
+	// Prometheus metrics and probes
+	s.mux.Handle("/metrics", s.handleMetrics())
+	s.mux.Handle("/healthz", s.handleHealthz())
+	s.mux.Handle("/readyz", s.handleReadyz())
+
 	// API - Configuration
 	s.mux.HandleFunc("/api/config", s.handleConfig)
 	s.mux.HandleFunc("/api/config/validate", s.handleConfigValidate)
@@ -76,17 +145,33 @@ func (s *Server) routes() {
 	s.mux.HandleFunc("/api/tiers/basic/check-feature", s.handleCheckTierFeature)
 	s.mux.HandleFunc("/api/tiers/professional/check-feature", s.handleCheckTierFeature)
 	s.mux.HandleFunc("/api/tiers/enterprise/check-feature", s.handleCheckTierFeature)
-	
+	s.mux.HandleFunc("/api/tiers/basic/dependencies", s.handleTierDependencies)
+	s.mux.HandleFunc("/api/tiers/professional/dependencies", s.handleTierDependencies)
+	s.mux.HandleFunc("/api/tiers/enterprise/dependencies", s.handleTierDependencies)
+	s.mux.HandleFunc("/api/manifests", s.handleManifests)
+	s.mux.HandleFunc("/api/manifests/dependencies", s.handleManifestDependencies)
+	s.mux.HandleFunc("/api/manifests/validate", s.handleManifestValidate)
+	s.mux.HandleFunc("/api/manifests/", s.handleManifestsRoot)
+
+	// API - Signed licenses
+	s.mux.HandleFunc("/api/licenses/issue", s.handleLicenseIssue)
+	s.mux.HandleFunc("/api/licenses/verify", s.handleLicenseVerify)
+	s.mux.HandleFunc("/api/licenses/public-key", s.handleLicensePublicKey)
+
 	// API - Limits (Week 3)
 	s.mux.HandleFunc("/api/limits/types", s.handleGetLimitTypes)
 	s.mux.HandleFunc("/api/limits/quota/example", s.handleGetLimitExample)
 	s.mux.HandleFunc("/api/limits/tps/example", s.handleGetLimitExample)
 	s.mux.HandleFunc("/api/limits/capacity/example", s.handleGetLimitExample)
 	s.mux.HandleFunc("/api/limits/concurrency/example", s.handleGetLimitExample)
+	s.mux.HandleFunc("/api/limits/seats/example", s.handleGetLimitExample)
+	s.mux.HandleFunc("/api/limits/volume/example", s.handleGetLimitExample)
 	s.mux.HandleFunc("/api/limits/quota/simulate", s.handleSimulateLimitType)
 	s.mux.HandleFunc("/api/limits/tps/simulate", s.handleSimulateLimitType)
 	s.mux.HandleFunc("/api/limits/capacity/simulate", s.handleSimulateLimitType)
 	s.mux.HandleFunc("/api/limits/concurrency/simulate", s.handleSimulateLimitType)
+	s.mux.HandleFunc("/api/limits/seats/simulate", s.handleSimulateLimitType)
+	s.mux.HandleFunc("/api/limits/volume/simulate", s.handleSimulateLimitType)
 	
 	// API - Instance (Week 4)
 	s.mux.HandleFunc("/api/instance/register", s.handleInstanceRegister)
@@ -103,9 +188,13 @@ func (s *Server) routes() {
 	
 	// API - Products & Simulation
 	s.mux.HandleFunc("/api/products", s.handleProducts)
+	s.mux.HandleFunc("/api/products/", s.handleProductsRoot)
 	s.mux.HandleFunc("/api/features", s.handleFeatures)
 	s.mux.HandleFunc("/api/sim/products", s.handleSimSelectProducts)
 	s.mux.HandleFunc("/api/sim/registered", s.handleSimRegistered)
+	s.mux.HandleFunc("/api/sim/clock", s.handleSimClock)
+	s.mux.HandleFunc("/api/leases/simulate-crash", s.handleLeaseCrashSimulation)
+	s.mux.HandleFunc("/api/versions/simulate", s.handleVersionSimulation)
 	// dynamic action routes: /api/sim/{product}/{action}
 	s.mux.HandleFunc("/api/sim/", s.handleSimRoot)
 }
@@ -113,12 +202,15 @@ func (s *Server) routes() {
 // --- Handlers ---
 
 type setConfigReq struct {
-	LCCURL string `json:"lcc_url"`
+	LCCURL        string   `json:"lcc_url"`
+	ManifestPaths []string `json:"manifest_paths,omitempty"`
 }
 
 type setConfigResp struct {
-	OK     bool   `json:"ok"`
-	LCCURL string `json:"lcc_url"`
+	OK                 bool     `json:"ok"`
+	LCCURL             string   `json:"lcc_url"`
+	ManifestPaths      []string `json:"manifest_paths,omitempty"`
+	ManifestPathSource string   `json:"manifest_path_source,omitempty"`
 }
 
 func (s *Server) handleSPA(w http.ResponseWriter, r *http.Request) {
@@ -126,7 +218,7 @@ func (s *Server) handleSPA(w http.ResponseWriter, r *http.Request) {
 		http.NotFound(w, r)
 		return
 	}
-	http.ServeFile(w, r, "static/index.html")
+	s.assets.serveIndex(w, r)
 }
 
 func (s *Server) handleConfig(w http.ResponseWriter, r *http.Request) {
@@ -139,10 +231,13 @@ func (s *Server) handleConfig(w http.ResponseWriter, r *http.Request) {
 			url = "http://localhost:7086"
 		}
 		s.mu.RUnlock()
+		paths, source := s.manifests.paths()
 		_ = json.NewEncoder(w).Encode(map[string]any{
 			"lcc_url":    url,
 			"saved_at":   time.Now().Format(time.RFC3339),
 			"is_default": s.lccURL == "",
+			"manifest_paths":       paths,
+			"manifest_path_source": source,
 		})
 	case http.MethodPost:
 		var req setConfigReq
@@ -150,21 +245,33 @@ func (s *Server) handleConfig(w http.ResponseWriter, r *http.Request) {
 			writeErr(w, http.StatusBadRequest, fmt.Errorf("invalid json: %w", err))
 			return
 		}
-		if req.LCCURL == "" {
+		if req.LCCURL == "" && len(req.ManifestPaths) == 0 {
 			writeErr(w, http.StatusBadRequest, fmt.Errorf("lcc_url is required"))
 			return
 		}
 
 		s.mu.Lock()
-		s.lccURL = req.LCCURL
-		s.publicBase = "/api/v1/public"
+		if req.LCCURL != "" {
+			s.lccURL = req.LCCURL
+			s.publicBase = "/api/v1/public"
+		}
+		if len(req.ManifestPaths) > 0 {
+			s.savedManifestPaths = req.ManifestPaths
+		}
+		lccURL := s.lccURL
+		paths, source := resolveManifestPaths(s.manifestPaths, s.savedManifestPaths)
 		s.mu.Unlock()
+		if len(req.ManifestPaths) > 0 && source == "config" {
+			// A flag or env path wins over the saved one, now and on restart
+			s.manifests.setPaths(paths, source)
+		}
 
 		if err := s.saveConfig(); err != nil {
 			log.Printf("Failed to save config: %v", err)
 		}
 
-		_ = json.NewEncoder(w).Encode(&setConfigResp{OK: true, LCCURL: req.LCCURL})
+		paths, source = s.manifests.paths()
+		_ = json.NewEncoder(w).Encode(&setConfigResp{OK: true, LCCURL: lccURL, ManifestPaths: paths, ManifestPathSource: source})
 	default:
 		w.WriteHeader(http.StatusMethodNotAllowed)
 	}
@@ -184,16 +291,10 @@ func (s *Server) handleConfigValidate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	s.mu.RLock()
-	lccURL := s.lccURL
-	s.mu.RUnlock()
-	
-	if lccURL == "" {
-		lccURL = "http://localhost:7086"
+	pc, err := s.publicClient()
+	if err != nil {
+		pc = lccPublicClient("http://localhost:7086")
 	}
-
-	base := strings.TrimRight(lccURL, "/") + "/api/v1/public"
-	pc := NewPublicServiceClient(base)
 	
 	products, err := pc.ListProducts(r.Context())
 	if err != nil {
@@ -221,16 +322,11 @@ func (s *Server) handleProducts(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	s.mu.RLock()
-	lccURL := s.lccURL
-	s.mu.RUnlock()
-	if lccURL == "" {
-		writeErr(w, http.StatusBadRequest, fmt.Errorf("lcc_url not configured; POST /api/config first"))
+	pc, err := s.publicClient()
+	if err != nil {
+		writeErr(w, http.StatusBadRequest, err)
 		return
 	}
-	base := strings.TrimRight(lccURL, "/") + "/api/v1/public"
-
-	pc := NewPublicServiceClient(base)
 	products, err := pc.ListProducts(r.Context())
 	if err != nil {
 		writeErr(w, http.StatusBadGateway, fmt.Errorf("failed to fetch products: %w", err))
@@ -285,13 +381,19 @@ func (s *Server) handleSimSelectProducts(w http.ResponseWriter, r *http.Request)
 	errs := map[string]string{}
 	instanceIDs := map[string]string{}
 
-	ks, _ := NewKeyStore()
+	ks, _ := NewKeyStore(s.dataDir)
+
+	version := nonEmpty(req.DefaultVersion, "1.0.0")
+	if _, err := semver.Parse(version); err != nil {
+		writeErr(w, http.StatusBadRequest, err)
+		return
+	}
 
 	for _, pid := range req.ProductIDs {
 		cfg := &lccconfig.SDKConfig{
 			LCCURL:         lccURL,
 			ProductID:      pid,
-			ProductVersion: nonEmpty(req.DefaultVersion, "1.0.0"),
+			ProductVersion: version,
 			Timeout:        10 * time.Second,
 			CacheTTL:       5 * time.Second,
 		}
@@ -323,9 +425,7 @@ func (s *Server) handleSimSelectProducts(w http.ResponseWriter, r *http.Request)
 			continue
 		}
 
-		s.mu.Lock()
-		s.clients[pid] = cli
-		s.mu.Unlock()
+		s.addClient(pid, cli, version)
 
 		registered = append(registered, pid)
 		instanceIDs[pid] = cli.GetInstanceID()
@@ -352,6 +452,14 @@ func (s *Server) handleSimRegistered(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusMethodNotAllowed)
 		return
 	}
+	if s.offline {
+		ids := make([]string, 0, len(AllTiers))
+		for _, t := range AllTiers {
+			ids = append(ids, t.ProductID)
+		}
+		_ = json.NewEncoder(w).Encode(ids)
+		return
+	}
 	s.mu.RLock()
 	ids := make([]string, 0, len(s.clients))
 	for k := range s.clients {
@@ -380,28 +488,66 @@ func (s *Server) handleSimRoot(w http.ResponseWriter, r *http.Request) {
 	productID := parts[0]
 	action := parts[1]
 
-	cli, err := s.getClient(productID)
+	cli, err := s.simBackend(productID)
 	if err != nil {
 		writeErr(w, http.StatusBadRequest, err)
 		return
 	}
 
+	if action == "renew" {
+		s.handleRenew(productID, w, r)
+		return
+	}
+
+	// License lifetime: expired licenses deny every action; expiring-soon
+	// and grace-period licenses still work but responses carry a warning.
+	lic := s.licenseStatus(productID)
+	if !lic.Allowed() && action != "status" {
+		writeLicenseExpired(w, lic)
+		return
+	}
+	if lic.Warning != "" {
+		w.Header().Set("X-License-Warning", lic.Warning)
+	}
+
 	switch action {
 	case "consume":
-		s.handleConsume(cli, w, r)
+		s.handleConsume(cli, lic.Warning, w, r)
 	case "tps-check":
-		s.handleTPSCheck(cli, w, r)
+		s.handleTPSCheck(cli, lic.Warning, w, r)
 	case "capacity-check":
-		s.handleCapacityCheck(cli, w, r)
+		s.handleCapacityCheck(cli, lic.Warning, w, r)
 	case "concurrency":
-		s.handleConcurrency(cli, w, r)
+		s.handleConcurrency(cli, lic.Warning, w, r)
+	case "seats":
+		s.handleSeats(cli, productID, lic.Warning, w, r)
+	case "leases":
+		op := ""
+		if len(parts) > 2 {
+			op = parts[2]
+		}
+		s.handleLeases(productID, op, lic.Warning, w, r)
+	case "volume":
+		s.handleVolume(productID, lic.Warning, w, r)
 	case "status":
-		s.handleStatus(cli, productID, w, r)
+		// Status peeks at trials (applyTrial) instead of using them up.
+		raw, _ := s.licenseBackend(productID)
+		s.handleStatus(meteredBackend{raw, productID}, productID, lic, w, r)
 	default:
 		w.WriteHeader(http.StatusNotFound)
 	}
 }
 
+// addClient makes cli, registered at version, the client of productID and
+// starts the product's license term.
+func (s *Server) addClient(productID string, cli *lccclient.Client, version string) {
+	s.mu.Lock()
+	s.clients[productID] = cli
+	s.versions[cli.GetInstanceID()] = version
+	s.mu.Unlock()
+	s.licenseTermFor(productID)
+}
+
 func (s *Server) getClient(productID string) (*lccclient.Client, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -415,43 +561,43 @@ func (s *Server) getClient(productID string) (*lccclient.Client, error) {
 // --- Simulation handlers ---
 
 type consumeReq struct { Amount int `json:"amount"` }
-type consumeResp struct { Allowed bool `json:"allowed"`; Remaining int `json:"remaining"` }
+type consumeResp struct { Allowed bool `json:"allowed"`; Remaining int `json:"remaining"`; Warning string `json:"warning,omitempty"` }
 
-func (s *Server) handleConsume(cli *lccclient.Client, w http.ResponseWriter, r *http.Request) {
+func (s *Server) handleConsume(cli LicenseBackend, warning string, w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost { w.WriteHeader(http.StatusMethodNotAllowed); return }
 	var req consumeReq
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil { writeErr(w, http.StatusBadRequest, fmt.Errorf("invalid json: %w", err)); return }
 	if req.Amount <= 0 { writeErr(w, http.StatusBadRequest, fmt.Errorf("positive amount required")); return }
 	allowed, remaining, err := cli.Consume(req.Amount)
 	if err != nil { writeErr(w, http.StatusBadGateway, err); return }
-	_ = json.NewEncoder(w).Encode(&consumeResp{Allowed: allowed, Remaining: remaining})
+	_ = json.NewEncoder(w).Encode(&consumeResp{Allowed: allowed, Remaining: remaining, Warning: warning})
 }
 
-type tpsResp struct { Allowed bool `json:"allowed"`; Max float64 `json:"max"` }
+type tpsResp struct { Allowed bool `json:"allowed"`; Max float64 `json:"max"`; Warning string `json:"warning,omitempty"` }
 
-func (s *Server) handleTPSCheck(cli *lccclient.Client, w http.ResponseWriter, r *http.Request) {
+func (s *Server) handleTPSCheck(cli LicenseBackend, warning string, w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost { w.WriteHeader(http.StatusMethodNotAllowed); return }
 	allowed, max, err := cli.CheckTPS()
 	if err != nil { writeErr(w, http.StatusBadGateway, err); return }
-	_ = json.NewEncoder(w).Encode(&tpsResp{Allowed: allowed, Max: max})
+	_ = json.NewEncoder(w).Encode(&tpsResp{Allowed: allowed, Max: max, Warning: warning})
 }
 
 type capacityReq struct { Current int `json:"current"` }
-type capacityResp struct { Allowed bool `json:"allowed"`; Max int `json:"max"` }
+type capacityResp struct { Allowed bool `json:"allowed"`; Max int `json:"max"`; Warning string `json:"warning,omitempty"` }
 
-func (s *Server) handleCapacityCheck(cli *lccclient.Client, w http.ResponseWriter, r *http.Request) {
+func (s *Server) handleCapacityCheck(cli LicenseBackend, warning string, w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost { w.WriteHeader(http.StatusMethodNotAllowed); return }
 	var req capacityReq
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil { writeErr(w, http.StatusBadRequest, fmt.Errorf("invalid json: %w", err)); return }
 	allowed, max, err := cli.CheckCapacity(req.Current)
 	if err != nil { writeErr(w, http.StatusBadGateway, err); return }
-	_ = json.NewEncoder(w).Encode(&capacityResp{Allowed: allowed, Max: max})
+	_ = json.NewEncoder(w).Encode(&capacityResp{Allowed: allowed, Max: max, Warning: warning})
 }
 
 type concurrencyReq struct { Slots int `json:"slots"`; HoldMS int `json:"hold_ms"`; Mode string `json:"mode"` }
-type concurrencyResp struct { Accepted int `json:"accepted"`; Denied int `json:"denied"`; ReasonStats map[string]int `json:"reason_stats,omitempty"` }
+type concurrencyResp struct { Accepted int `json:"accepted"`; Denied int `json:"denied"`; ReasonStats map[string]int `json:"reason_stats,omitempty"`; Warning string `json:"warning,omitempty"` }
 
-func (s *Server) handleConcurrency(cli *lccclient.Client, w http.ResponseWriter, r *http.Request) {
+func (s *Server) handleConcurrency(cli LicenseBackend, warning string, w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost { w.WriteHeader(http.StatusMethodNotAllowed); return }
 	var req concurrencyReq
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil { writeErr(w, http.StatusBadRequest, fmt.Errorf("invalid json: %w", err)); return }
@@ -494,7 +640,7 @@ func (s *Server) handleConcurrency(cli *lccclient.Client, w http.ResponseWriter,
 		writeErr(w, http.StatusBadRequest, fmt.Errorf("invalid mode"))
 		return
 	}
-	_ = json.NewEncoder(w).Encode(&concurrencyResp{Accepted: accepted, Denied: denied, ReasonStats: ifNilStats(reasonStats)})
+	_ = json.NewEncoder(w).Encode(&concurrencyResp{Accepted: accepted, Denied: denied, ReasonStats: ifNilStats(reasonStats), Warning: warning})
 }
 
 func ifNilStats(m map[string]int) map[string]int { if len(m) == 0 { return nil }; return m }
@@ -510,35 +656,72 @@ type featureStatusDTO struct {
 	MaxCapacity    int                        `json:"max_capacity,omitempty"`
 	MaxTPS         float64                    `json:"max_tps,omitempty"`
 	MaxConcurrency int                        `json:"max_concurrency,omitempty"`
+	Trial          *license.TrialStatus       `json:"trial,omitempty"`
+	Volume         *offline.VolumeInfo        `json:"volume,omitempty"`
+	SupportedVersions string                  `json:"supported_versions,omitempty"`
 }
 
 type productStatusResp struct {
-	ProductID  string              `json:"product_id"`
-	InstanceID string              `json:"instance_id"`
-	Features   []featureStatusDTO  `json:"features"`
+	ProductID  string               `json:"product_id"`
+	InstanceID string               `json:"instance_id"`
+	Version    string               `json:"version,omitempty"`
+	FeatureSource string            `json:"feature_source"`
+	License    *licenseStatusDTO    `json:"license,omitempty"`
+	Seats      *offline.SeatStatus  `json:"seats,omitempty"`
+	Leases     *offline.LeaseStatus `json:"leases,omitempty"`
+	Features   []featureStatusDTO   `json:"features"`
 }
 
-func (s *Server) handleStatus(cli *lccclient.Client, productID string, w http.ResponseWriter, r *http.Request) {
+func (s *Server) handleStatus(cli LicenseBackend, productID string, lic licenseStatusDTO, w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet && r.Method != http.MethodPost { w.WriteHeader(http.StatusMethodNotAllowed); return }
 	s.mu.RLock(); lccURL := s.lccURL; s.mu.RUnlock()
-	if lccURL == "" { writeErr(w, http.StatusBadRequest, fmt.Errorf("lcc_url not configured")); return }
-features, _ := LoadFeaturesForProduct(productID)
+	if lccURL == "" && !s.offline { writeErr(w, http.StatusBadRequest, fmt.Errorf("lcc_url not configured")); return }
+	features, _ := s.manifests.loadFeaturesForProduct(productID)
+	source := featureSourceManifest
+	if len(features) == 0 && s.offline {
+		features, source = tierFeatures(tierForProduct(productID)), featureSourceTier
+	}
 	if len(features) == 0 {
-		features, _ = LoadFeatureUnion()
+		// No manifest for this product: the union of all manifests is a
+		// guess, so say so (see /api/products/{id}/drift).
+		features, _ = s.manifests.loadFeatureUnion()
+		source = featureSourceUnion
+		log.Printf("status %s: no local manifest, using feature union", productID)
 	}
+	version := s.productVersion(productID)
 	out := make([]featureStatusDTO, 0, len(features))
 	for _, f := range features {
+		if !lic.Allowed() {
+			out = append(out, featureStatusDTO{ ID: f.ID, Name: f.Name, Enabled: false, Reason: license.ReasonLicenseExpired })
+			continue
+		}
 		st, err := cli.CheckFeature(f.ID)
 		if err != nil {
 			out = append(out, featureStatusDTO{ ID: f.ID, Name: f.Name, Enabled: false, Reason: "check_error" })
 			continue
 		}
-		out = append(out, featureStatusDTO{
+		dto := featureStatusDTO{
 			ID: f.ID, Name: f.Name, Enabled: st.Enabled, Reason: st.Reason,
 			Quota: st.Quota, MaxCapacity: st.MaxCapacity, MaxTPS: st.MaxTPS, MaxConcurrency: st.MaxConcurrency,
-		})
+		}
+		s.applyTrial(&dto, productID)
+		s.applyVersionGate(&dto, productID, version)
+		if meter := s.volumeMeter(productID); meter != nil && dto.Enabled {
+			info := meter.Info()
+			dto.Volume = &info
+		}
+		out = append(out, dto)
+	}
+	resp := &productStatusResp{ ProductID: productID, InstanceID: cli.GetInstanceID(), Version: version, FeatureSource: source, License: &lic, Features: out }
+	if pool := s.seatPool(productID, cli.GetInstanceID()); pool != nil {
+		st := pool.Status()
+		resp.Seats = &st
 	}
-	_ = json.NewEncoder(w).Encode(&productStatusResp{ ProductID: productID, InstanceID: cli.GetInstanceID(), Features: out })
+	if pool := s.leasePool(productID); pool != nil {
+		st := pool.Status()
+		resp.Leases = &st
+	}
+	_ = json.NewEncoder(w).Encode(resp)
 }
 
 func (s *Server) handleProductPage(w http.ResponseWriter, r *http.Request) {
@@ -563,40 +746,80 @@ const productHTML = `<!DOCTYPE html>
     th, td { border: 1px solid #1f2937; padding: 6px 8px; }
     .ok { color: #86efac; }
     .no { color: #fca5a5; }
+    .warn { color: #fcd34d; }
   </style>
 </head>
 <body>
   <h2>Product: {{.ProductID}}</h2>
   <button onclick="loadStatus()">Refresh</button>
+  <button onclick="renew()">Renew License</button>
+  <button onclick="advanceClock(7)">+7 days</button>
+  <button onclick="advanceClock(0, true)">Reset clock</button>
   <div id="meta"></div>
+  <div id="license" style="margin-top:8px"></div>
   <table style="margin-top:10px">
     <thead>
-      <tr><th>Feature</th><th>Enabled</th><th>Reason</th><th>Quota</th><th>Capacity</th><th>TPS</th><th>Concurrency</th></tr>
+      <tr><th>Feature</th><th>Enabled</th><th>Reason</th><th>Quota</th><th>Capacity</th><th>TPS</th><th>Concurrency</th><th>Volume</th><th>Code</th></tr>
     </thead>
     <tbody id="rows"></tbody>
   </table>
+  <div id="preview-title" style="margin-top:10px"></div>
+  <pre id="preview" style="background:#111827; padding:10px; overflow:auto; display:none"></pre>
 <script>
 async function loadStatus(){
   const r = await fetch(window.location.origin + '/api/sim/' + encodeURIComponent('{{.ProductID}}') + '/status');
   const j = await r.json();
   if (j && !j.error){
     document.getElementById('meta').textContent = 'InstanceID: '+(j.instance_id||'');
+    renderLicense(j.license);
     const tb = document.getElementById('rows'); tb.innerHTML='';
     (j.features||[]).forEach(function(f){
       var tr = document.createElement('tr');
       function td(t){ var d=document.createElement('td'); d.textContent=t; return d; }
       tr.appendChild(td((f.name||f.id)));
       var en = document.createElement('td'); en.textContent = f.enabled ? '✓' : '✗'; en.className = f.enabled ? 'ok' : 'no'; tr.appendChild(en);
-      tr.appendChild(td(f.reason||''));
+      var reason = f.reason||'';
+      if (f.trial && f.trial.active) reason += ' (' + f.trial.days_remaining + ' trial day(s) left)';
+      tr.appendChild(td(reason));
       var q=''; if (f.quota){ q = 'lim='+f.quota.limit+', used='+f.quota.used+', rem='+f.quota.remaining; }
       tr.appendChild(td(q));
       tr.appendChild(td(f.max_capacity>0?String(f.max_capacity):''));
       tr.appendChild(td(f.max_tps>0?String(f.max_tps):''));
       tr.appendChild(td(f.max_concurrency>0?String(f.max_concurrency):''));
+      tr.appendChild(td(f.volume ? (f.volume.used + '/' + f.volume.limit + ' ' + f.volume.unit) : ''));
+      var code = td('');
+      if (j.feature_source === 'manifest'){
+        var b = document.createElement('button'); b.textContent = 'Preview'; b.onclick = function(){ preview(f.id); }; code.appendChild(b);
+      }
+      tr.appendChild(code);
       tb.appendChild(tr);
     });
   }
 }
+function renderLicense(l){
+  var el = document.getElementById('license');
+  if (!l){ el.textContent=''; return; }
+  var txt = 'License: ' + l.state + ' (expires ' + (l.expires_at||'').slice(0,10) + ', ' + l.days_remaining + ' day(s) left, simulated now ' + (l.now||'').slice(0,10) + ')';
+  if (l.warning) txt += ' - ' + l.warning;
+  el.textContent = txt;
+  el.className = (l.state === 'active') ? 'ok' : (l.state === 'expired' ? 'no' : 'warn');
+}
+async function preview(featureID){
+  const r = await fetch(window.location.origin + '/api/manifests/' + encodeURIComponent('{{.ProductID}}') + '/features/' + encodeURIComponent(featureID) + '/preview');
+  const j = await r.json();
+  var pre = document.getElementById('preview');
+  document.getElementById('preview-title').textContent = j.error ? '' : 'Generated wrapper for ' + featureID + (j.file ? ' (' + j.file + ')' : '');
+  pre.textContent = j.error ? j.error : j.code;
+  pre.style.display = 'block';
+}
+async function renew(){
+  await fetch(window.location.origin + '/api/sim/' + encodeURIComponent('{{.ProductID}}') + '/renew', {method:'POST'});
+  loadStatus();
+}
+async function advanceClock(days, reset){
+  await fetch(window.location.origin + '/api/sim/clock', {method:'POST', headers:{'Content-Type':'application/json'}, body: JSON.stringify({advance_days: days, reset: !!reset})});
+  loadStatus();
+}
 loadStatus();
 </script>
 </body>
@@ -616,10 +839,10 @@ func (s *Server) handleFeatures(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet { w.WriteHeader(http.StatusMethodNotAllowed); return }
 	pid := r.URL.Query().Get("product_id")
 	if pid == "" { writeErr(w, http.StatusBadRequest, fmt.Errorf("product_id required")); return }
-	features, _ := LoadFeaturesForProduct(pid)
+	features, _ := s.manifests.loadFeaturesForProduct(pid)
 	if len(features) == 0 {
 		// fallback: union of all known features in local manifests
-		features, _ = LoadFeatureUnion()
+		features, _ = s.manifests.loadFeatureUnion()
 	}
 	_ = json.NewEncoder(w).Encode(features)
 }
@@ -648,15 +871,40 @@ func ifNilMapStr(m map[string]string) map[string]string {
 // --- Config persistence ---
 
 type persistedConfig struct {
-	LCCURL string `json:"lcc_url"`
+	LCCURL        string   `json:"lcc_url"`
+	ManifestPaths []string `json:"manifest_paths,omitempty"`
+}
+
+// ConfigFileName is the name of the persisted config in the data directory.
+const ConfigFileName = "config.json"
+
+// WithConfigFile reads and saves the persisted config at path instead of
+// <data dir>/config.json.
+func WithConfigFile(path string) ServerOption {
+	return func(s *Server) { s.configFile = path }
+}
+
+// WithLCCURL sets the LCC server URL, overriding the persisted config.
+func WithLCCURL(url string) ServerOption {
+	return func(s *Server) {
+		s.lccURL = url
+		s.publicBase = "/api/v1/public"
+	}
+}
+
+// WithStaticDir serves the SPA from dir instead of the embedded assets,
+// uncached, for frontend development.
+func WithStaticDir(dir string) ServerOption {
+	return func(s *Server) { s.staticDir = dir }
 }
 
 func (s *Server) configPath() (string, error) {
-	h, err := os.UserHomeDir()
-	if err != nil { return "", err }
-	root := filepath.Join(h, ".lcc-demo")
-	if err := os.MkdirAll(root, 0700); err != nil { return "", err }
-	return filepath.Join(root, "config.json"), nil
+	p := s.configFile
+	if p == "" {
+		p = filepath.Join(s.dataDir, ConfigFileName)
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0700); err != nil { return "", err }
+	return p, nil
 }
 
 func (s *Server) loadConfig() {
@@ -667,18 +915,31 @@ func (s *Server) loadConfig() {
 	var cfg persistedConfig
 	if err := json.Unmarshal(data, &cfg); err != nil { return }
 	s.mu.Lock()
-	s.lccURL = cfg.LCCURL
+	if s.lccURL == "" { // WithLCCURL wins
+		s.lccURL = cfg.LCCURL
+	}
 	s.publicBase = "/api/v1/public"
+	s.savedManifestPaths = cfg.ManifestPaths
 	s.mu.Unlock()
 }
 
 func (s *Server) saveConfig() error {
 	p, err := s.configPath()
 	if err != nil { return err }
+	// Keep the settings the server does not own (listen address, TLS, ...)
+	fields := map[string]json.RawMessage{}
+	if data, err := os.ReadFile(p); err == nil {
+		_ = json.Unmarshal(data, &fields)
+	}
 	s.mu.RLock()
-	cfg := persistedConfig{LCCURL: s.lccURL}
+	fields["lcc_url"], _ = json.Marshal(s.lccURL)
+	if len(s.savedManifestPaths) > 0 {
+		fields["manifest_paths"], _ = json.Marshal(s.savedManifestPaths)
+	} else {
+		delete(fields, "manifest_paths")
+	}
 	s.mu.RUnlock()
-	data, err := json.MarshalIndent(&cfg, "", "  ")
+	data, err := json.MarshalIndent(fields, "", "  ")
 	if err != nil { return err }
 	return os.WriteFile(p, data, 0600)
 }
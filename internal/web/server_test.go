@@ -0,0 +1,22 @@
+package web
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// newTestServer returns a server keeping its data dir, and so its config file
+// and keys, in a temp dir, with a manifest search path that matches nothing
+// there. opts apply after these, e.g. to point WithManifestPaths at test
+// manifests. The server is closed when the test ends.
+func newTestServer(t *testing.T, opts ...ServerOption) *Server {
+	t.Helper()
+	dir := t.TempDir()
+	opts = append([]ServerOption{
+		WithDataDir(dir),
+		WithManifestPaths([]string{filepath.Join(dir, "none.yaml")}),
+	}, opts...)
+	s := NewServer(opts...)
+	t.Cleanup(s.Close)
+	return s
+}
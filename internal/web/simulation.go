@@ -6,8 +6,6 @@ import (
 	"log"
 	"sync"
 	"time"
-
-	lccclient "github.com/yourorg/lcc-sdk/pkg/client"
 )
 
 type SimulationStatus string
@@ -31,6 +29,7 @@ const (
 	EventTypeComplete    EventType = "simulation_complete"
 	EventTypeIterationStart EventType = "iteration_start"
 	EventTypeFeatureCall EventType = "feature_call"
+	EventTypeLimitCheck  EventType = "limit_check"
 	EventTypeError       EventType = "error"
 )
 
@@ -39,6 +38,7 @@ type SimulationEvent struct {
 	Type        EventType `json:"type"`
 	Iteration   int       `json:"iteration,omitempty"`
 	FeatureID   string    `json:"feature_id,omitempty"`
+	Control     string    `json:"control,omitempty"`
 	Allowed     bool      `json:"allowed"`
 	Reason      string    `json:"reason"`
 	Details     string    `json:"details"`
@@ -56,6 +56,7 @@ type SimulationMetrics struct {
 	CurrentTPS         map[string]float64 `json:"current_tps"`
 	QuotaRemaining     map[string]int `json:"quota_remaining"`
 	FeatureCalls       map[string]int `json:"feature_calls"`
+	LimitHits          map[string]int `json:"limit_hits"` // control -> denied calls
 }
 
 type SimulationConfig struct {
@@ -65,17 +66,33 @@ type SimulationConfig struct {
 	IntervalMS       int    `json:"interval_ms"`
 	FeaturesToCall   []string `json:"features_to_call"`
 	CallPattern      map[string]int `json:"call_pattern"`
+	Controls         []string `json:"controls,omitempty"` // limit controls run every iteration
+}
+
+// Limit controls a simulation runs every iteration after the feature checks.
+const (
+	ControlQuota     = "quota"      // Consume(1)
+	ControlRateLimit = "rate_limit" // CheckTPS()
+	ControlCapacity  = "capacity"   // CheckCapacity(iteration-1): iteration N creates resource N
+)
+
+// controlDenyReasons is the event reason of a denied call per control.
+var controlDenyReasons = map[string]string{
+	ControlQuota:     "quota_exceeded",
+	ControlRateLimit: "rate_limited",
+	ControlCapacity:  "capacity_exceeded",
 }
 
 type SimulationEngine struct {
 	mu              sync.RWMutex
 	config          SimulationConfig
-	client          *lccclient.Client
+	client          LicenseBackend
 	status          SimulationStatus
 	metrics         SimulationMetrics
 	events          []SimulationEvent
 	eventsChan      chan SimulationEvent
 	stopChan        chan struct{}
+	done            chan struct{} // closed when simulationLoop returns
 	pauseChan       chan struct{}
 	resumeChan      chan struct{}
 	paused          bool
@@ -84,7 +101,7 @@ type SimulationEngine struct {
 	lastPauseStart  time.Time
 }
 
-func NewSimulationEngine(config SimulationConfig, client *lccclient.Client) *SimulationEngine {
+func NewSimulationEngine(config SimulationConfig, client LicenseBackend) *SimulationEngine {
 	return &SimulationEngine{
 		config:     config,
 		client:     client,
@@ -92,6 +109,7 @@ func NewSimulationEngine(config SimulationConfig, client *lccclient.Client) *Sim
 		events:     make([]SimulationEvent, 0, 1000),
 		eventsChan: make(chan SimulationEvent, 100),
 		stopChan:   make(chan struct{}),
+		done:       make(chan struct{}),
 		pauseChan:  make(chan struct{}),
 		resumeChan: make(chan struct{}),
 		metrics: SimulationMetrics{
@@ -99,6 +117,7 @@ func NewSimulationEngine(config SimulationConfig, client *lccclient.Client) *Sim
 			CurrentTPS:      make(map[string]float64),
 			QuotaRemaining:  make(map[string]int),
 			FeatureCalls:    make(map[string]int),
+			LimitHits:       make(map[string]int),
 		},
 	}
 }
@@ -216,6 +235,7 @@ func (e *SimulationEngine) GetEvents(limit int) []SimulationEvent {
 }
 
 func (e *SimulationEngine) simulationLoop(ctx context.Context) {
+	defer close(e.done)
 	interval := time.Duration(e.config.IntervalMS) * time.Millisecond
 
 	for i := 1; i <= e.config.Iterations; i++ {
@@ -237,7 +257,11 @@ func (e *SimulationEngine) simulationLoop(ctx context.Context) {
 			if !paused {
 				break
 			}
-			time.Sleep(100 * time.Millisecond)
+			select {
+			case <-e.stopChan:
+				return
+			case <-time.After(100 * time.Millisecond):
+			}
 		}
 
 		e.runIteration(i)
@@ -247,7 +271,11 @@ func (e *SimulationEngine) simulationLoop(ctx context.Context) {
 		e.mu.Unlock()
 
 		if i < e.config.Iterations {
-			time.Sleep(interval)
+			select {
+			case <-e.stopChan:
+				return
+			case <-time.After(interval):
+			}
 		}
 	}
 
@@ -285,6 +313,10 @@ func (e *SimulationEngine) runIteration(iteration int) {
 
 		e.callFeature(iteration, featureID)
 	}
+
+	for _, control := range e.config.Controls {
+		e.callControl(iteration, control)
+	}
 }
 
 func (e *SimulationEngine) callFeature(iteration int, featureID string) {
@@ -351,6 +383,73 @@ func (e *SimulationEngine) callFeature(iteration int, featureID string) {
 	})
 }
 
+// callControl runs the product-level limit check of control.
+func (e *SimulationEngine) callControl(iteration int, control string) {
+	if e.client == nil {
+		e.recordEvent(SimulationEvent{
+			Timestamp: time.Now(),
+			Type:      EventTypeError,
+			Iteration: iteration,
+			Control:   control,
+			Allowed:   false,
+			Error:     "client is nil",
+		})
+		return
+	}
+
+	var (
+		allowed   bool
+		err       error
+		call      string
+		remaining int
+		result    = map[string]interface{}{}
+	)
+	switch control {
+	case ControlQuota:
+		allowed, remaining, err = e.client.Consume(1)
+		call, result["remaining"] = "Consume(1)", remaining
+	case ControlRateLimit:
+		var max float64
+		allowed, max, err = e.client.CheckTPS()
+		call, result["max_tps"] = "CheckTPS()", max
+	case ControlCapacity:
+		var max int
+		allowed, max, err = e.client.CheckCapacity(iteration - 1)
+		call, result["current"], result["max_capacity"] = fmt.Sprintf("CheckCapacity(%d)", iteration-1), iteration-1, max
+	default:
+		err = fmt.Errorf("unknown control %q", control)
+	}
+
+	event := SimulationEvent{
+		Timestamp:  time.Now(),
+		Type:       EventTypeLimitCheck,
+		Iteration:  iteration,
+		Control:    control,
+		Allowed:    allowed,
+		CallResult: result,
+		Details:    fmt.Sprintf("%s -> %v", call, allowed),
+	}
+	e.mu.Lock()
+	switch {
+	case err != nil:
+		e.metrics.FailureCount++
+		event.Allowed, event.Reason, event.Error = false, "error", err.Error()
+		event.Details = fmt.Sprintf("%s error: %v", call, err)
+	case allowed:
+		e.metrics.SuccessCount++
+	default:
+		e.metrics.FailureCount++
+		e.metrics.LimitHits[control]++
+		event.Reason = controlDenyReasons[control]
+	}
+	if control == ControlQuota && err == nil {
+		e.metrics.QuotaRemaining[control] = remaining
+	}
+	e.mu.Unlock()
+
+	e.recordEvent(event)
+}
+
 func (e *SimulationEngine) recordEvent(event SimulationEvent) {
 	if event.Timestamp.IsZero() {
 		event.Timestamp = time.Now()
@@ -400,7 +499,7 @@ func NewSimulationManager() *SimulationManager {
 	}
 }
 
-func (m *SimulationManager) Create(config SimulationConfig, client *lccclient.Client) *SimulationEngine {
+func (m *SimulationManager) Create(config SimulationConfig, client LicenseBackend) *SimulationEngine {
 	engine := NewSimulationEngine(config, client)
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -419,3 +518,43 @@ func (m *SimulationManager) Delete(instanceID string) {
 	defer m.mu.Unlock()
 	delete(m.simulations, instanceID)
 }
+
+// countByStatus counts the simulations by status.
+func (m *SimulationManager) countByStatus() map[SimulationStatus]int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := make(map[SimulationStatus]int)
+	for _, e := range m.simulations {
+		e.mu.RLock()
+		out[e.status]++
+		e.mu.RUnlock()
+	}
+	return out
+}
+
+// stopAll stops the running and paused simulations, waits up to timeout
+// for their loops to return and returns how many it stopped.
+func (m *SimulationManager) stopAll(timeout time.Duration) int {
+	m.mu.RLock()
+	engines := make([]*SimulationEngine, 0, len(m.simulations))
+	for _, e := range m.simulations {
+		engines = append(engines, e)
+	}
+	m.mu.RUnlock()
+	var stopped []*SimulationEngine
+	for _, e := range engines {
+		if e.Stop() == nil {
+			stopped = append(stopped, e)
+		}
+	}
+	deadline := time.After(timeout)
+	for _, e := range stopped {
+		select {
+		case <-e.done:
+		case <-deadline:
+			log.Printf("Simulation %s did not stop within %s", e.config.InstanceID, timeout)
+			return len(stopped)
+		}
+	}
+	return len(stopped)
+}
@@ -15,6 +15,7 @@ type StartSimulationRequest struct {
 	IntervalMS   int               `json:"interval_ms"`
 	FeaturesToCall []string        `json:"features_to_call"`
 	CallPattern  map[string]int    `json:"call_pattern"`
+	Controls     []string          `json:"controls"` // quota, rate_limit, capacity
 }
 
 type StartSimulationResponse struct {
@@ -47,8 +48,6 @@ type ExportResponse struct {
 	Error    string                  `json:"error,omitempty"`
 }
 
-var simManager = NewSimulationManager()
-
 func (s *Server) handleSimulationStart(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	if r.Method != http.MethodPost {
@@ -80,7 +79,27 @@ func (s *Server) handleSimulationStart(w http.ResponseWriter, r *http.Request) {
 		req.IntervalMS = 500
 	}
 
-	cli, err := s.getClient(req.InstanceID)
+	for _, c := range req.Controls {
+		if _, ok := controlDenyReasons[c]; !ok {
+			_ = json.NewEncoder(w).Encode(&StartSimulationResponse{
+				Success: false,
+				Error:   fmt.Sprintf("unknown control %q", c),
+			})
+			return
+		}
+	}
+
+	if prev := s.sims.Get(req.InstanceID); prev != nil {
+		if status, _ := prev.GetStatus(); status == StatusRunning || status == StatusPaused {
+			_ = json.NewEncoder(w).Encode(&StartSimulationResponse{
+				Success: false,
+				Error:   "simulation already running",
+			})
+			return
+		}
+	}
+
+	cli, err := s.simBackend(req.InstanceID)
 	if err != nil {
 		_ = json.NewEncoder(w).Encode(&StartSimulationResponse{
 			Success: false,
@@ -96,9 +115,10 @@ func (s *Server) handleSimulationStart(w http.ResponseWriter, r *http.Request) {
 		IntervalMS:     req.IntervalMS,
 		FeaturesToCall: req.FeaturesToCall,
 		CallPattern:    req.CallPattern,
+		Controls:       req.Controls,
 	}
 
-	engine := simManager.Create(config, cli)
+	engine := s.sims.Create(config, cli)
 	if engine == nil {
 		_ = json.NewEncoder(w).Encode(&StartSimulationResponse{
 			Success: false,
@@ -107,10 +127,8 @@ func (s *Server) handleSimulationStart(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ctx, cancel := context.WithCancel(r.Context())
-	defer cancel()
-
-	if err := engine.Start(ctx); err != nil {
+	// The run outlives the request; Stop and Close end it
+	if err := engine.Start(context.Background()); err != nil {
 		_ = json.NewEncoder(w).Encode(&StartSimulationResponse{
 			Success: false,
 			Error:   fmt.Sprintf("failed to start simulation: %v", err),
@@ -139,7 +157,7 @@ func (s *Server) handleSimulationStop(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	engine := simManager.Get(instanceID)
+	engine := s.sims.Get(instanceID)
 	if engine == nil {
 		_ = json.NewEncoder(w).Encode(&StartSimulationResponse{
 			Success: false,
@@ -177,7 +195,7 @@ func (s *Server) handleSimulationPause(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	engine := simManager.Get(instanceID)
+	engine := s.sims.Get(instanceID)
 	if engine == nil {
 		_ = json.NewEncoder(w).Encode(&StartSimulationResponse{
 			Success: false,
@@ -215,7 +233,7 @@ func (s *Server) handleSimulationResume(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	engine := simManager.Get(instanceID)
+	engine := s.sims.Get(instanceID)
 	if engine == nil {
 		_ = json.NewEncoder(w).Encode(&StartSimulationResponse{
 			Success: false,
@@ -253,7 +271,7 @@ func (s *Server) handleSimulationStatus(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	engine := simManager.Get(instanceID)
+	engine := s.sims.Get(instanceID)
 	if engine == nil {
 		_ = json.NewEncoder(w).Encode(&StatusResponse{
 			Success: false,
@@ -294,7 +312,7 @@ func (s *Server) handleSimulationEvents(w http.ResponseWriter, r *http.Request)
 
 	typeFilter := r.URL.Query().Get("type")
 
-	engine := simManager.Get(instanceID)
+	engine := s.sims.Get(instanceID)
 	if engine == nil {
 		_ = json.NewEncoder(w).Encode(&EventsResponse{
 			Success: false,
@@ -312,7 +330,7 @@ func (s *Server) handleSimulationEvents(w http.ResponseWriter, r *http.Request)
 		for _, e := range events {
 			switch typeFilter {
 			case "success":
-				if e.Allowed && e.Type == EventTypeFeatureCall {
+				if e.Allowed && (e.Type == EventTypeFeatureCall || e.Type == EventTypeLimitCheck) {
 					filtered = append(filtered, e)
 				}
 			case "error":
@@ -350,7 +368,7 @@ func (s *Server) handleSimulationExport(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	engine := simManager.Get(instanceID)
+	engine := s.sims.Get(instanceID)
 	if engine == nil {
 		_ = json.NewEncoder(w).Encode(&ExportResponse{
 			Success: false,
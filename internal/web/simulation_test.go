@@ -0,0 +1,47 @@
+package web
+
+import "testing"
+
+func TestCallControl(t *testing.T) {
+	controls := []string{ControlQuota, ControlRateLimit, ControlCapacity}
+	for _, tc := range []struct {
+		name    string
+		backend *fakeBackend
+		hits    int
+	}{
+		{"allowed", &fakeBackend{consume: true, tps: true, capacity: true}, 0},
+		{"denied", &fakeBackend{}, 1},
+	} {
+		e := NewSimulationEngine(SimulationConfig{InstanceID: "control-test", Iterations: 1}, tc.backend)
+		for _, control := range controls {
+			e.callControl(1, control)
+		}
+
+		_, m := e.GetStatus()
+		for _, control := range controls {
+			if m.LimitHits[control] != tc.hits {
+				t.Errorf("%s: %s limit hits = %d, want %d", tc.name, control, m.LimitHits[control], tc.hits)
+			}
+		}
+		if want := len(controls) - tc.hits*len(controls); m.SuccessCount != want {
+			t.Errorf("%s: success count = %d, want %d", tc.name, m.SuccessCount, want)
+		}
+		checks := 0
+		for _, ev := range e.GetEvents(0) {
+			if ev.Type != EventTypeLimitCheck {
+				continue
+			}
+			checks++
+			want := ""
+			if tc.hits > 0 {
+				want = controlDenyReasons[ev.Control]
+			}
+			if ev.Allowed != (tc.hits == 0) || ev.Reason != want {
+				t.Errorf("%s: %s event = allowed %v, reason %q; want reason %q", tc.name, ev.Control, ev.Allowed, ev.Reason, want)
+			}
+		}
+		if checks != len(controls) {
+			t.Errorf("%s: %d limit check events, want %d", tc.name, checks, len(controls))
+		}
+	}
+}
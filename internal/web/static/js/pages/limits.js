@@ -12,7 +12,7 @@ const LimitsPage = {
                     </h1>
                     <p style="font-size: var(--text-lg); color: var(--text-muted); max-width: 800px; margin: 0 auto;">
                         Beyond ON/OFF feature gating, limits control HOW MUCH customers can consume.
-                        Four types serve different use cases.
+                        Six types serve different use cases.
                     </p>
                 </div>
 
@@ -22,6 +22,8 @@ const LimitsPage = {
                         <button class="limit-type-tab" data-type="tps">2️⃣ TPS</button>
                         <button class="limit-type-tab" data-type="capacity">3️⃣ Capacity</button>
                         <button class="limit-type-tab" data-type="concurrency">4️⃣ Concurrency</button>
+                        <button class="limit-type-tab" data-type="seats">5️⃣ Seats</button>
+                        <button class="limit-type-tab" data-type="volume">6️⃣ Volume</button>
                     </div>
 
                     <div id="limit-type-content" style="min-height: 600px; transition: opacity 0.2s ease-in-out;"></div>
@@ -287,6 +289,10 @@ const LimitsPage = {
                 return { max_capacity: 50 };
             case 'concurrency':
                 return { max_concurrency: 10 };
+            case 'seats':
+                return { max_seats: 5, cooldown_days: 3 };
+            case 'volume':
+                return { max_mb: 1024, avg_mb: 150 };
             default:
                 return {};
         }
@@ -368,6 +374,18 @@ const LimitsPage = {
                 config: 'max_concurrency: 10',
                 api: 'AcquireSlot(id)',
                 developer: 'Call + release()'
+            },
+            {
+                control: 'Seats',
+                config: 'seats: {max, reassign_cooldown_days}',
+                api: 'AssignSeat(user) / RevokeSeat(user)',
+                developer: 'Manage named users'
+            },
+            {
+                control: 'Volume',
+                config: 'volume: {max, unit, window}',
+                api: 'ConsumeVolume(bytes, records)',
+                developer: 'Report data processed'
             }
         ];
 
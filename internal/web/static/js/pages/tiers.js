@@ -202,7 +202,9 @@ func ExportToExcel(reportID string) error {
             { id: 'pdf_export', name: 'PDF Export' },
             { id: 'excel_export', name: 'Excel Export' },
             { id: 'custom_dashboard', name: 'Custom Dashboard' },
-            { id: 'api_access', name: 'API Access' }
+            { id: 'api_access', name: 'API Access' },
+            { id: 'cloud_export', name: 'Cloud Export' },
+            { id: 'onprem_export', name: 'On-Prem Export' }
         ];
 
         let html = '<table style="width: 100%; border-collapse: collapse;">';
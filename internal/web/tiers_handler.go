@@ -89,6 +89,7 @@ func (s *Server) handleCheckTierFeature(w http.ResponseWriter, r *http.Request)
 
 	var req struct {
 		FeatureID string `json:"feature_id"`
+		Version   string `json:"version,omitempty"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeErr(w, http.StatusBadRequest, err)
@@ -100,7 +101,7 @@ func (s *Server) handleCheckTierFeature(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	result := CheckFeatureForTier(tier, req.FeatureID)
+	result := s.trials.checkFeatureForVersion(tier, req.FeatureID, req.Version, s.now())
 	_ = json.NewEncoder(w).Encode(result)
 }
 
@@ -0,0 +1,134 @@
+package web
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"demo-app/internal/deps"
+	"demo-app/internal/license"
+	"demo-app/internal/offline"
+
+	lccclient "github.com/yourorg/lcc-sdk/pkg/client"
+)
+
+// trialUsage records when a tier's trial of a feature started and how often
+// it has been used.
+type trialUsage struct {
+	start time.Time
+	used  int
+}
+
+// trialTracker keeps trial usage per tier/feature. Trials without a fixed
+// start begin on their first evaluation.
+type trialTracker struct {
+	mu    sync.Mutex
+	usage map[string]*trialUsage // tierID/featureID -> usage
+}
+
+func newTrialTracker() *trialTracker {
+	return &trialTracker{usage: make(map[string]*trialUsage)}
+}
+
+func trialKey(tier *TierDefinition, featureID string) string {
+	return tier.ID + "/" + featureID
+}
+
+// evaluate returns the trial status of a feature at now. When use is true and
+// the trial is active, one use is counted against the usage cap.
+func (t *trialTracker) evaluate(tier *TierDefinition, featureID string, trial license.Trial, now time.Time, use bool) license.TrialStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	key := trialKey(tier, featureID)
+	u, ok := t.usage[key]
+	if !ok {
+		u = &trialUsage{start: now}
+		if !use {
+			// Peeking at an unused trial must not start its clock.
+			return trial.Evaluate(u.start, 0, now)
+		}
+		t.usage[key] = u
+	}
+	st := trial.Evaluate(u.start, u.used, now)
+	if use && st.Active {
+		u.used++
+		st = trial.Evaluate(u.start, u.used, now)
+		// The use just counted was allowed even if it exhausted the cap.
+		st.Active, st.Reason = true, license.ReasonTrial
+	}
+	return st
+}
+
+// reset forgets all trial usage.
+func (t *trialTracker) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.usage = make(map[string]*trialUsage)
+}
+
+// tierDenial reports whether reason says the tier does not include a
+// feature. A trial unlocks only that: quota, version and other denials stand.
+func tierDenial(reason string) bool {
+	switch reason {
+	case offline.ReasonInsufficientTier, "tier_insufficient":
+		return true
+	}
+	return strings.HasPrefix(reason, "requires_")
+}
+
+// applyTrial overlays the tier trial of a feature onto a status entry without
+// counting a use, so status pages show the remaining trial days. It grants
+// what trialBackend would grant.
+func (s *Server) applyTrial(dto *featureStatusDTO, productID string) {
+	tier := tierForProduct(productID)
+	if tier == nil || dto.Enabled || !tierDenial(dto.Reason) {
+		return
+	}
+	feature, ok := tier.Features[dto.ID]
+	if !ok || feature.Trial == nil {
+		return
+	}
+	now := s.now()
+	st := s.trials.evaluate(tier, dto.ID, *feature.Trial, now, false)
+	dto.Trial = &st
+	dto.Reason = st.Reason
+	dto.Enabled = st.Active
+	if st.Active && len(s.trials.missingDependencies(tier, dto.ID, now)) > 0 {
+		dto.Enabled, dto.Reason = false, deps.ReasonDependencyMissing
+	}
+}
+
+// trialBackend grants a feature its backend denies for the tier while the
+// tier trial of the feature runs: the LCC server and the offline evaluator do
+// not know about trials. Each granted check counts as one trial use.
+type trialBackend struct {
+	LicenseBackend
+	s       *Server
+	product string
+}
+
+func (b trialBackend) CheckFeature(featureID string) (*lccclient.FeatureStatus, error) {
+	st, err := b.LicenseBackend.CheckFeature(featureID)
+	if err != nil || st.Enabled || !tierDenial(st.Reason) {
+		return st, err
+	}
+	tier := tierForProduct(b.product)
+	if tier == nil {
+		return st, nil
+	}
+	feature, ok := tier.Features[featureID]
+	if !ok || feature.Trial == nil {
+		return st, nil
+	}
+	now := b.s.now()
+	if !b.s.trials.evaluate(tier, featureID, *feature.Trial, now, false).Active ||
+		len(b.s.trials.missingDependencies(tier, featureID, now)) > 0 {
+		return st, nil
+	}
+	if !b.s.trials.evaluate(tier, featureID, *feature.Trial, now, true).Active {
+		return st, nil
+	}
+	out := *st
+	out.Enabled, out.Reason = true, license.ReasonTrial
+	return &out, nil
+}
@@ -0,0 +1,157 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"demo-app/internal/license"
+	"demo-app/internal/offline"
+
+	lccclient "github.com/yourorg/lcc-sdk/pkg/client"
+)
+
+// fakeBackend is a LicenseBackend with canned answers. Features missing from
+// features are denied for the tier.
+type fakeBackend struct {
+	features map[string]*lccclient.FeatureStatus
+	consume  bool // Consume result
+	tps      bool // CheckTPS result
+	capacity bool // CheckCapacity result
+}
+
+func (b *fakeBackend) GetInstanceID() string { return "fake-instance" }
+
+func (b *fakeBackend) CheckFeature(featureID string) (*lccclient.FeatureStatus, error) {
+	if st, ok := b.features[featureID]; ok {
+		out := *st
+		return &out, nil
+	}
+	return &lccclient.FeatureStatus{Enabled: false, Reason: offline.ReasonInsufficientTier}, nil
+}
+
+func (b *fakeBackend) Consume(amount int) (bool, int, error) { return b.consume, 0, nil }
+func (b *fakeBackend) CheckTPS() (bool, float64, error)      { return b.tps, 10, nil }
+func (b *fakeBackend) CheckCapacity(current int) (bool, int, error) {
+	return b.capacity, 5, nil
+}
+func (b *fakeBackend) AcquireSlot() (func(), bool, error) { return func() {}, true, nil }
+
+func checkTrialFeature(t *testing.T, s *Server) *lccclient.FeatureStatus {
+	t.Helper()
+	b, err := s.simBackend(BasicTier.ProductID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err := b.CheckFeature("ml_analytics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return st
+}
+
+func TestTrialGrantAndExpiry(t *testing.T) {
+	s := newTestServer(t, WithOfflineEvaluator())
+	start := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	s.clock = func() time.Time { return start }
+
+	for i := 0; i < 3; i++ {
+		if st := checkTrialFeature(t, s); !st.Enabled || st.Reason != license.ReasonTrial {
+			t.Fatalf("check %d = %v %q, want enabled by trial", i, st.Enabled, st.Reason)
+		}
+	}
+
+	// Status shows the trial without using it.
+	for i := 0; i < 2; i++ {
+		rec := httptest.NewRecorder()
+		s.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/sim/"+BasicTier.ProductID+"/status", nil))
+		var resp productStatusResp
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("status %d: %v", rec.Code, err)
+		}
+		var trial *license.TrialStatus
+		for _, f := range resp.Features {
+			if f.ID == "ml_analytics" {
+				trial = f.Trial
+			}
+		}
+		if trial == nil || !trial.Active || trial.Used != 3 || trial.DaysRemaining != 14 {
+			t.Fatalf("status trial = %+v, want active with 3 uses and 14 days left", trial)
+		}
+	}
+
+	// A second server keeps its own trial usage.
+	other := newTestServer(t, WithOfflineEvaluator())
+	other.clock = s.clock
+	if st := other.trials.evaluate(BasicTier, "ml_analytics", *BasicTier.Features["ml_analytics"].Trial, start, false); st.Used != 0 {
+		t.Fatalf("other server trial used = %d, want 0", st.Used)
+	}
+
+	s.clock = func() time.Time { return start.Add(15 * 24 * time.Hour) }
+	if st := checkTrialFeature(t, s); st.Enabled || st.Reason != offline.ReasonInsufficientTier {
+		t.Fatalf("after trial = %v %q, want the tier denial", st.Enabled, st.Reason)
+	}
+}
+
+func TestCheckTierFeaturePeeksAtTrial(t *testing.T) {
+	s := newTestServer(t, WithOfflineEvaluator())
+	for i := 0; i < 2; i++ {
+		rec := httptest.NewRecorder()
+		s.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/tiers/basic/check-feature", strings.NewReader(`{"feature_id":"ml_analytics"}`)))
+		var resp struct {
+			Enabled bool                `json:"enabled"`
+			Reason  string              `json:"reason"`
+			Trial   license.TrialStatus `json:"trial"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("check-feature %d: %v", rec.Code, err)
+		}
+		if !resp.Enabled || resp.Reason != license.ReasonTrial || resp.Trial.Used != 0 {
+			t.Fatalf("check %d = %+v, want enabled by an unused trial", i, resp)
+		}
+	}
+	if checkTrialFeature(t, s); s.trials.evaluate(BasicTier, "ml_analytics", *BasicTier.Features["ml_analytics"].Trial, s.now(), false).Used != 1 {
+		t.Fatal("check-feature used up the trial")
+	}
+}
+
+func TestTrialOnlyLiftsTierDenials(t *testing.T) {
+	s := newTestServer(t, WithOfflineEvaluator())
+	fake := &fakeBackend{features: map[string]*lccclient.FeatureStatus{
+		"ml_analytics": {Enabled: false, Reason: offline.ReasonQuotaExceeded},
+	}}
+	b := trialBackend{fake, s, BasicTier.ProductID}
+
+	st, err := b.CheckFeature("ml_analytics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Enabled || st.Reason != offline.ReasonQuotaExceeded {
+		t.Fatalf("quota denial = %v %q, want it passed through", st.Enabled, st.Reason)
+	}
+	if st := s.trials.evaluate(BasicTier, "ml_analytics", *BasicTier.Features["ml_analytics"].Trial, s.now(), false); st.Used != 0 {
+		t.Fatalf("trial used = %d, want 0", st.Used)
+	}
+
+	delete(fake.features, "ml_analytics")
+	if st, _ := b.CheckFeature("ml_analytics"); !st.Enabled || st.Reason != license.ReasonTrial {
+		t.Fatalf("tier denial = %v %q, want enabled by trial", st.Enabled, st.Reason)
+	}
+}
+
+func TestTierDenial(t *testing.T) {
+	for reason, want := range map[string]bool{
+		offline.ReasonInsufficientTier: true,
+		"requires_professional":        true,
+		offline.ReasonQuotaExceeded:    false,
+		offline.ReasonFeatureNotFound:  false,
+		"":                             false,
+	} {
+		if got := tierDenial(reason); got != want {
+			t.Errorf("tierDenial(%q) = %v, want %v", reason, got, want)
+		}
+	}
+}
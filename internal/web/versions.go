@@ -0,0 +1,169 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"demo-app/internal/offline"
+	"demo-app/internal/semver"
+)
+
+// defaultSimVersions are the versions /api/versions/simulate registers when
+// the request names none. They straddle the demo tier ranges: cloud_export
+// needs 2.0.0 and onprem_export ends at 3.0.0.
+var defaultSimVersions = []string{"1.0.0", "2.0.0", "3.0.0", "3.1.0"}
+
+// instanceVersion returns the version instanceID was registered at, or ""
+// if it is unknown (offline mode), which skips version gating.
+func (s *Server) instanceVersion(instanceID string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.versions[instanceID]
+}
+
+// productVersion returns the version of the instance currently serving
+// productID, or "" if the product was not registered.
+func (s *Server) productVersion(productID string) string {
+	cli, err := s.getClient(productID)
+	if err != nil {
+		return ""
+	}
+	return s.instanceVersion(cli.GetInstanceID())
+}
+
+// requestVersion returns the version to gate a request for productID at:
+// that of instanceID if it names an instance of the product, else that of
+// the product's current instance.
+func (s *Server) requestVersion(productID, instanceID string) string {
+	if instanceID != "" {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		for _, inst := range s.instances {
+			if inst.InstanceID == instanceID && inst.ProductID == productID {
+				return inst.Version
+			}
+		}
+		return ""
+	}
+	return s.productVersion(productID)
+}
+
+// featureVersions returns the version range of a feature, preferring the
+// local manifest of productID and falling back to its tier definition.
+func (m *manifestStore) featureVersions(productID, featureID string) semver.Range {
+	if e, ok := m.forProduct(productID); ok {
+		if f, ok := e.Manifest.Feature(featureID); ok {
+			return f.Versions()
+		}
+	}
+	if tier := tierForProduct(productID); tier != nil {
+		if f, ok := tier.Features[featureID]; ok {
+			return f.Versions()
+		}
+	}
+	return semver.Range{}
+}
+
+// applyVersionGate denies a status entry whose feature is not available in
+// version.
+func (s *Server) applyVersionGate(dto *featureStatusDTO, productID, version string) {
+	if version == "" {
+		return
+	}
+	rng := s.manifests.featureVersions(productID, dto.ID)
+	if rng.IsZero() {
+		return
+	}
+	dto.SupportedVersions = rng.String()
+	if in, err := rng.Contains(version); err == nil && !in {
+		dto.Enabled = false
+		dto.Reason = semver.ReasonVersionUnsupported
+		dto.Quota = nil
+		dto.Trial = nil
+	}
+}
+
+type versionSimReq struct {
+	ProductID string   `json:"product_id"`
+	Versions  []string `json:"versions"`
+}
+
+type versionSimInstance struct {
+	Version    string             `json:"version"`
+	InstanceID string             `json:"instance_id"`
+	Enabled    int                `json:"enabled"`
+	Features   []featureStatusDTO `json:"features"`
+}
+
+type versionSimResp struct {
+	ProductID string               `json:"product_id"`
+	Instances []versionSimInstance `json:"instances"`
+}
+
+// handleVersionSimulation registers the same tier product at several
+// versions, each against its own offline evaluator, and reports which
+// features every version gets.
+func (s *Server) handleVersionSimulation(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req versionSimReq
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeErr(w, http.StatusBadRequest, fmt.Errorf("invalid json: %w", err))
+			return
+		}
+	}
+	if req.ProductID == "" {
+		req.ProductID = EnterpriseTier.ProductID
+	}
+	if len(req.Versions) == 0 {
+		req.Versions = defaultSimVersions
+	}
+	tier := tierForProduct(req.ProductID)
+	if tier == nil {
+		writeErr(w, http.StatusNotFound, fmt.Errorf("no tier license for product: %s", req.ProductID))
+		return
+	}
+	for _, v := range req.Versions {
+		if _, err := semver.Parse(v); err != nil {
+			writeErr(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	features, _ := s.manifests.loadFeaturesForProduct(req.ProductID)
+	if len(features) == 0 {
+		features = tierFeatures(tier)
+	}
+
+	resp := versionSimResp{ProductID: req.ProductID, Instances: make([]versionSimInstance, 0, len(req.Versions))}
+	for _, v := range req.Versions {
+		doc := NewLicenseDocument(tier, "version-sim", s.now(), DefaultLicenseValidity)
+		ev := offline.New(doc, offline.WithClock(s.now))
+		inst := versionSimInstance{
+			Version:    v,
+			InstanceID: ev.GetInstanceID() + "@" + v,
+			Features:   make([]featureStatusDTO, 0, len(features)),
+		}
+		for _, f := range features {
+			st, err := offlineBackend{ev}.CheckFeature(f.ID)
+			if err != nil {
+				inst.Features = append(inst.Features, featureStatusDTO{ID: f.ID, Name: f.Name, Reason: "check_error"})
+				continue
+			}
+			dto := featureStatusDTO{ID: f.ID, Name: f.Name, Enabled: st.Enabled, Reason: st.Reason}
+			s.applyVersionGate(&dto, req.ProductID, v)
+			if dto.Enabled {
+				inst.Enabled++
+			}
+			inst.Features = append(inst.Features, dto)
+		}
+		resp.Instances = append(resp.Instances, inst)
+	}
+	_ = json.NewEncoder(w).Encode(&resp)
+}
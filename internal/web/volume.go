@@ -0,0 +1,83 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"demo-app/internal/offline"
+)
+
+// volumeMeter returns the data-volume meter of a product, or nil if its tier
+// has no volume limit. Registered products get a local meter sized from the
+// tier limits.
+func (s *Server) volumeMeter(productID string) *offline.VolumeMeter {
+	if s.offline {
+		ev, err := s.offlineEvaluator(productID)
+		if err != nil {
+			return nil
+		}
+		return ev.Volume()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if meter, ok := s.volumes[productID]; ok {
+		return meter
+	}
+	cfg := tierLimitConfig(productID, "volume")
+	if cfg == nil {
+		return nil
+	}
+	max, _ := cfg["max"].(int64)
+	unit, _ := cfg["unit"].(string)
+	window, _ := cfg["window"].(string)
+	meter := offline.NewVolumeMeter(unit, max, window, s.now)
+	s.volumes[productID] = meter
+	return meter
+}
+
+type volumeReq struct {
+	Bytes   int64 `json:"bytes"`
+	Records int64 `json:"records"`
+}
+
+type volumeResp struct {
+	Allowed bool               `json:"allowed"`
+	Reason  string             `json:"reason"`
+	Volume  offline.VolumeInfo `json:"volume"`
+	Warning string             `json:"warning,omitempty"`
+}
+
+// handleVolume reports (GET) or charges (POST) processed data volume:
+// /api/sim/{product}/volume
+func (s *Server) handleVolume(productID, warning string, w http.ResponseWriter, r *http.Request) {
+	meter := s.volumeMeter(productID)
+	if meter == nil {
+		writeErr(w, http.StatusNotFound, fmt.Errorf("product %s has no volume limit", productID))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		_ = json.NewEncoder(w).Encode(&volumeResp{Allowed: true, Reason: offline.ReasonOK, Volume: meter.Info(), Warning: warning})
+	case http.MethodPost:
+		var req volumeReq
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeErr(w, http.StatusBadRequest, fmt.Errorf("invalid json: %w", err))
+			return
+		}
+		if req.Bytes < 0 || req.Records < 0 || (req.Bytes == 0 && req.Records == 0) {
+			writeErr(w, http.StatusBadRequest, fmt.Errorf("bytes or records must be positive"))
+			return
+		}
+		allowed, info := meter.Consume(req.Bytes, req.Records)
+		reason := offline.ReasonOK
+		if !allowed {
+			reason = offline.ReasonVolumeExceeded
+		}
+		_ = json.NewEncoder(w).Encode(&volumeResp{Allowed: allowed, Reason: reason, Volume: info, Warning: warning})
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
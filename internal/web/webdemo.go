@@ -0,0 +1,316 @@
+package web
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"demo-app/internal/manifest"
+)
+
+// webdemoFiles holds the pages of the guided demo: pick a product
+// (discover), choose the limit controls to exercise (configure) and watch
+// the simulation (runtime). The pages run on /api/webdemo/catalog,
+// /api/sim/products and /api/simulation/*.
+//
+//go:embed webdemo/*.html
+var webdemoFiles embed.FS
+
+// webdemoPages maps the page names under /webdemo/ to their files.
+var webdemoPages = func() map[string]*asset {
+	pages := map[string]*asset{}
+	for _, name := range []string{"discover", "configure", "runtime"} {
+		data, err := fs.ReadFile(webdemoFiles, "webdemo/"+name+".html")
+		if err != nil {
+			panic("web: webdemo page: " + err.Error())
+		}
+		pages[name] = newAsset(data)
+	}
+	return pages
+}()
+
+// handleWebdemo serves the guided demo pages; /webdemo/ starts at discover.
+func (s *Server) handleWebdemo(w http.ResponseWriter, r *http.Request) {
+	name := strings.Trim(strings.TrimPrefix(r.URL.Path, "/webdemo"), "/")
+	if name == "" {
+		http.Redirect(w, r, "/webdemo/discover", http.StatusFound)
+		return
+	}
+	page, ok := webdemoPages[name]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Cache-Control", cacheRevalidate)
+	serveAsset(w, r, name+".html", page)
+}
+
+// Where the webdemo catalog came from.
+const (
+	catalogSourceLCC   = "lcc"   // the LCC server's public product list
+	catalogSourceTiers = "tiers" // AllTiers: offline, or the LCC list failed
+)
+
+// Where the availability of a catalog feature came from.
+const (
+	availabilitySDK     = "sdk"     // CheckFeature of the product's backend
+	availabilityTier    = "tier"    // the tier definition
+	availabilityUnknown = "unknown" // product neither registered nor a known tier
+)
+
+// catalogResp is the webdemo discover catalog.
+type catalogResp struct {
+	Source   string           `json:"source"`
+	LCCURL   string           `json:"lcc_url,omitempty"`
+	Error    string           `json:"error,omitempty"` // why the LCC list is not used
+	Products []CatalogProduct `json:"products"`
+}
+
+// CatalogProduct is a product of the webdemo discover page. Features come
+// from the LCC server where possible; availability from the product's
+// backend if it is registered (or offline), else from its tier; limits
+// from the tier license plus the quotas of the product's local manifest.
+// So the page shows what the SDK enforces.
+type CatalogProduct struct {
+	ID            string                 `json:"id"`
+	Name          string                 `json:"name"`
+	Tier          string                 `json:"tier,omitempty"`
+	Description   string                 `json:"description,omitempty"`
+	PricePoint    string                 `json:"price_point,omitempty"`
+	Registered    bool                   `json:"registered"`     // ready for simulations without /api/sim/products
+	FeatureSource string                 `json:"feature_source"` // lcc, manifest or tier
+	Manifest      string                 `json:"manifest,omitempty"`
+	Features      []CatalogFeature       `json:"features"`
+	Limitations   []CatalogLimit         `json:"limitations"`
+	License       map[string]interface{} `json:"license,omitempty"` // as issued by /api/tiers/{tier}/license
+}
+
+// CatalogFeature is a feature of a catalog product.
+type CatalogFeature struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Available    bool   `json:"available"`
+	Availability string `json:"availability"` // sdk, tier or unknown
+	Reason       string `json:"reason,omitempty"`
+	Description  string `json:"description,omitempty"`
+	RequiredTier string `json:"required_tier,omitempty"`
+	Quota        string `json:"quota,omitempty"` // from the local manifest
+}
+
+// CatalogLimit is a product-level limit, formatted for display.
+type CatalogLimit struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+func (s *Server) handleWebdemoCatalog(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(s.webdemoCatalog(r.Context()))
+}
+
+// webdemoCatalog lists the products of the LCC server. In offline mode, or
+// if the server cannot be asked, it lists AllTiers instead and says why.
+func (s *Server) webdemoCatalog(ctx context.Context) catalogResp {
+	resp := catalogResp{Source: catalogSourceTiers}
+	if !s.offline {
+		products, err := s.liveCatalog(ctx)
+		if err == nil {
+			s.mu.RLock()
+			resp.LCCURL = s.lccURL
+			s.mu.RUnlock()
+			resp.Source, resp.Products = catalogSourceLCC, products
+			return resp
+		}
+		resp.Error = err.Error()
+	}
+	resp.Products = make([]CatalogProduct, 0, len(AllTiers))
+	for _, tier := range AllTiers {
+		resp.Products = append(resp.Products, s.catalogProduct(tier.ProductID, tier.Name, nil))
+	}
+	return resp
+}
+
+// liveCatalog lists the products and features of the LCC server.
+func (s *Server) liveCatalog(ctx context.Context) ([]CatalogProduct, error) {
+	pc, err := s.publicClient()
+	if err != nil {
+		return nil, err
+	}
+	products, err := pc.ListProducts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch products: %w", err)
+	}
+	s.mu.Lock()
+	s.lastProducts = products
+	s.mu.Unlock()
+
+	out := make([]CatalogProduct, 0, len(products))
+	for _, p := range products {
+		features, err := pc.ListFeatures(ctx, p.ID)
+		if err != nil {
+			log.Printf("catalog %s: failed to fetch features: %v", p.ID, err)
+			features = nil
+		}
+		out = append(out, s.catalogProduct(p.ID, p.Name, features))
+	}
+	return out, nil
+}
+
+// catalogProduct merges what is known about productID: the server's
+// features (nil: the manifest's, else the tier's), the tier definition, the
+// local manifest and, if the product has a backend, its feature checks.
+func (s *Server) catalogProduct(productID, name string, features []PublicFeature) CatalogProduct {
+	tier := tierForProduct(productID)
+	var m *manifest.Manifest
+	p := CatalogProduct{
+		ID:            productID,
+		Name:          name,
+		FeatureSource: featureSourceLCC,
+		Limitations:   []CatalogLimit{},
+	}
+	if tier != nil {
+		p.Name = nonEmpty(p.Name, tier.Name)
+		p.Tier, p.Description, p.PricePoint = tier.Tier, tier.Description, tier.PricePoint
+		p.Limitations = catalogLimits(tierLimits(tier))
+		p.License = GetLicenseJSON(tier)
+	}
+	p.Name = nonEmpty(p.Name, productID)
+	if e, ok := s.manifests.forProduct(productID); ok {
+		m, p.Manifest = e.Manifest, e.Path
+	}
+	if features == nil {
+		features, p.FeatureSource = tierFeatures(tier), featureSourceTier
+		if m != nil {
+			features, _ = s.manifests.loadFeaturesForProduct(productID)
+			p.FeatureSource = featureSourceManifest
+		}
+	}
+	// The catalog is not a use of the product: no SDK call metrics, and
+	// trials are peeked at (applyTrial) instead of used up.
+	backend, err := s.licenseBackend(productID)
+	p.Registered = err == nil
+
+	sorted := append([]PublicFeature(nil), features...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+	p.Features = make([]CatalogFeature, 0, len(sorted))
+	for _, f := range sorted {
+		cf := CatalogFeature{ID: f.ID, Name: f.Name, Availability: availabilityUnknown}
+		if tier != nil {
+			if tf, ok := tier.Features[f.ID]; ok {
+				cf.Name = nonEmpty(cf.Name, tf.Name)
+				cf.Description, cf.RequiredTier = tf.Description, tf.RequiredTier
+				cf.Available, cf.Availability = tf.Enabled, availabilityTier
+			}
+		}
+		if m != nil {
+			if mf, ok := m.Feature(f.ID); ok {
+				cf.Name = nonEmpty(cf.Name, mf.Name)
+				cf.RequiredTier = nonEmpty(cf.RequiredTier, mf.Tier)
+				if mf.Quota != nil && mf.Quota.Limit > 0 {
+					cf.Quota = perWindow(formatCount(float64(mf.Quota.Limit)), mf.Quota.Period)
+					p.Limitations = append(p.Limitations, CatalogLimit{Name: nonEmpty(cf.Name, f.ID) + " Quota", Value: cf.Quota})
+				}
+			}
+		}
+		if backend != nil {
+			if st, err := backend.CheckFeature(f.ID); err == nil {
+				dto := featureStatusDTO{ID: f.ID, Enabled: st.Enabled, Reason: st.Reason}
+				s.applyTrial(&dto, productID)
+				cf.Available, cf.Reason, cf.Availability = dto.Enabled, dto.Reason, availabilitySDK
+			}
+		}
+		cf.Name = nonEmpty(cf.Name, f.ID)
+		p.Features = append(p.Features, cf)
+	}
+	return p
+}
+
+// catalogLimits formats the product-level limits of a tier license, in
+// a fixed order.
+func catalogLimits(limits map[string]interface{}) []CatalogLimit {
+	out := []CatalogLimit{}
+	add := func(name, value string) { out = append(out, CatalogLimit{Name: name, Value: value}) }
+	sub := func(key string) map[string]interface{} {
+		m, _ := limits[key].(map[string]interface{})
+		return m
+	}
+	if q := sub("quota"); q != nil {
+		add("Quota", perWindow(formatCount(numberValue(q["max"]))+" calls", q["window"]))
+	}
+	if v := numberValue(limits["max_tps"]); v > 0 {
+		add("Rate Limit", strconv.FormatFloat(v, 'f', -1, 64)+" TPS")
+	}
+	if v := numberValue(limits["max_capacity"]); v > 0 {
+		add("Capacity", formatCount(v)+" max")
+	}
+	if v := numberValue(limits["max_concurrency"]); v > 0 {
+		add("Concurrency", formatCount(v)+" slots")
+	}
+	if st := sub("seats"); st != nil {
+		add("Seats", formatCount(numberValue(st["max"])))
+	}
+	if fl := sub("floating"); fl != nil {
+		add("Floating Leases", formatCount(numberValue(fl["max"])))
+	}
+	if v := sub("volume"); v != nil {
+		add("Data Volume", perWindow(formatBytes(numberValue(v["max"])), v["window"]))
+	}
+	return out
+}
+
+// perWindow appends the window or period of a limit: "200 / daily".
+func perWindow(value string, window interface{}) string {
+	if w, _ := window.(string); w != "" {
+		return value + " / " + w
+	}
+	return value
+}
+
+// numberValue reads a JSON-ish number of a limits map.
+func numberValue(v interface{}) float64 {
+	switch n := v.(type) {
+	case int:
+		return float64(n)
+	case int64:
+		return float64(n)
+	case float64:
+		return n
+	case json.Number:
+		f, _ := n.Float64()
+		return f
+	default:
+		return 0
+	}
+}
+
+// formatCount writes n with thousands separators: 50000 -> 50,000.
+func formatCount(n float64) string {
+	s := strconv.FormatInt(int64(n), 10)
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
+
+// formatBytes writes n in the largest binary unit dividing it: 10 GiB.
+func formatBytes(n float64) string {
+	b := int64(n)
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB"}
+	i := 0
+	for i < len(units)-1 && b >= 1024 && b%1024 == 0 {
+		b /= 1024
+		i++
+	}
+	return fmt.Sprintf("%s %s", formatCount(float64(b)), units[i])
+}
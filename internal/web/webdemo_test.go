@@ -0,0 +1,196 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"demo-app/internal/license"
+)
+
+const testCatalogManifest = `sdk:
+  product_id: "data-insight-basic"
+
+features:
+  - id: pdf_export
+    name: "PDF Export"
+    tier: professional
+    quota:
+      limit: 1200
+      period: daily
+
+  - id: local_only
+    name: "Local Only"
+    tier: enterprise
+`
+
+// testCatalogServer returns a server with a manifest for data-insight-basic
+// and none for the other tiers.
+func testCatalogServer(t *testing.T, opts ...ServerOption) *Server {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "lcc-features.yaml")
+	if err := os.WriteFile(path, []byte(testCatalogManifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return newTestServer(t, append([]ServerOption{WithManifestPaths([]string{path})}, opts...)...)
+}
+
+func catalogFeature(p CatalogProduct, id string) (CatalogFeature, bool) {
+	for _, f := range p.Features {
+		if f.ID == id {
+			return f, true
+		}
+	}
+	return CatalogFeature{}, false
+}
+
+func featureIDs(p CatalogProduct) string {
+	ids := make([]string, len(p.Features))
+	for i, f := range p.Features {
+		ids[i] = f.ID
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestCatalogProductSources(t *testing.T) {
+	s := testCatalogServer(t, WithOfflineEvaluator())
+
+	// The server's feature list wins; the tier and the manifest fill in.
+	p := s.catalogProduct("data-insight-basic", "Insight (LCC)", []PublicFeature{{ID: "pdf_export"}, {ID: "server_only", Name: "Server Only"}})
+	if p.FeatureSource != featureSourceLCC || p.Name != "Insight (LCC)" || featureIDs(p) != "pdf_export,server_only" {
+		t.Errorf("server features: source %s, name %q, features %s", p.FeatureSource, p.Name, featureIDs(p))
+	}
+	if f, _ := catalogFeature(p, "pdf_export"); f.Name != "PDF Export" || f.RequiredTier != "professional" || f.Description == "" {
+		t.Errorf("pdf_export = %+v, want the tier's name, required tier and description", f)
+	}
+	if f, _ := catalogFeature(p, "server_only"); f.Name != "Server Only" || f.RequiredTier != "" {
+		t.Errorf("server_only = %+v", f)
+	}
+
+	// Without a server list, the product's manifest decides the features.
+	p = s.catalogProduct("data-insight-basic", "", nil)
+	if p.FeatureSource != featureSourceManifest || !strings.HasSuffix(p.Manifest, "lcc-features.yaml") || featureIDs(p) != "local_only,pdf_export" {
+		t.Errorf("manifest features: source %s, manifest %q, features %s", p.FeatureSource, p.Manifest, featureIDs(p))
+	}
+	if p.Name != BasicTier.Name || p.Tier != "basic" {
+		t.Errorf("manifest product = %q (%s), want the tier's name", p.Name, p.Tier)
+	}
+	if f, _ := catalogFeature(p, "local_only"); f.Name != "Local Only" || f.RequiredTier != "enterprise" {
+		t.Errorf("local_only = %+v, want the manifest's name and tier", f)
+	}
+
+	// Without either, the tier definition does.
+	p = s.catalogProduct("data-insight-pro", "", nil)
+	if p.FeatureSource != featureSourceTier || p.Manifest != "" || len(p.Features) != len(ProfessionalTier.Features) {
+		t.Errorf("tier features: source %s, manifest %q, %d features", p.FeatureSource, p.Manifest, len(p.Features))
+	}
+}
+
+func TestCatalogProductManifestQuota(t *testing.T) {
+	s := testCatalogServer(t, WithOfflineEvaluator())
+	p := s.catalogProduct("data-insight-basic", "", nil)
+
+	f, _ := catalogFeature(p, "pdf_export")
+	if f.Quota != "1,200 / daily" {
+		t.Errorf("pdf_export quota = %q", f.Quota)
+	}
+	var limits []string
+	for _, l := range p.Limitations {
+		limits = append(limits, l.Name+"="+l.Value)
+	}
+	if !strings.Contains(strings.Join(limits, ";"), "PDF Export Quota=1,200 / daily") {
+		t.Errorf("limitations %v lack the manifest quota", limits)
+	}
+}
+
+func TestCatalogProductAvailability(t *testing.T) {
+	s := testCatalogServer(t, WithOfflineEvaluator())
+	var before bytes.Buffer
+	_ = sdkMetrics.WriteText(&before)
+
+	p := s.catalogProduct("data-insight-basic", "", tierFeatures(BasicTier))
+	if !p.Registered {
+		t.Fatal("offline product not registered")
+	}
+	if f, _ := catalogFeature(p, "basic_reports"); !f.Available || f.Availability != availabilitySDK {
+		t.Errorf("basic_reports = %+v", f)
+	}
+	if f, _ := catalogFeature(p, "pdf_export"); f.Available || f.Availability != availabilitySDK {
+		t.Errorf("pdf_export = %+v", f)
+	}
+	// ml_analytics has a trial: shown as available, but not started.
+	if f, _ := catalogFeature(p, "ml_analytics"); !f.Available || f.Reason != license.ReasonTrial {
+		t.Errorf("ml_analytics = %+v, want the trial", f)
+	}
+	if len(s.trials.usage) != 0 {
+		t.Errorf("catalog started trials: %v", s.trials.usage)
+	}
+
+	// Loading the catalog is not an SDK call of the product.
+	var after bytes.Buffer
+	_ = sdkMetrics.WriteText(&after)
+	if before.String() != after.String() {
+		t.Errorf("catalog changed the SDK metrics:\n%s", after.String())
+	}
+}
+
+func TestWebdemoCatalogFallback(t *testing.T) {
+	lcc := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "down for maintenance", http.StatusServiceUnavailable)
+	}))
+	defer lcc.Close()
+	s := testCatalogServer(t, WithLCCURL(lcc.URL))
+
+	resp := s.webdemoCatalog(context.Background())
+	if resp.Source != catalogSourceTiers || resp.Error == "" {
+		t.Errorf("source %s, error %q; want the tiers and the LCC error", resp.Source, resp.Error)
+	}
+	if len(resp.Products) != len(AllTiers) {
+		t.Fatalf("%d products, want one per tier (%d)", len(resp.Products), len(AllTiers))
+	}
+	for i, p := range resp.Products {
+		if p.ID != AllTiers[i].ProductID || p.Registered {
+			t.Errorf("product %d = %s (registered %v)", i, p.ID, p.Registered)
+		}
+		// Nothing is registered, so availability comes from the tier, if
+		// it defines the feature.
+		for _, f := range p.Features {
+			want := availabilityUnknown
+			if _, ok := AllTiers[i].Features[f.ID]; ok {
+				want = availabilityTier
+			}
+			if f.Availability != want {
+				t.Errorf("%s/%s availability = %s, want %s", p.ID, f.ID, f.Availability, want)
+			}
+		}
+	}
+}
+
+func TestWebdemoPages(t *testing.T) {
+	s := testCatalogServer(t, WithOfflineEvaluator())
+	for _, tc := range []struct {
+		path, location string
+		code           int
+	}{
+		{"/webdemo/", "/webdemo/discover", http.StatusFound},
+		{"/webdemo/discover", "", http.StatusOK},
+		{"/webdemo/runtime/", "", http.StatusOK},
+		{"/webdemo/nope", "", http.StatusNotFound},
+		{"/webdemo/discover.html", "", http.StatusNotFound},
+	} {
+		rec := httptest.NewRecorder()
+		s.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.path, nil))
+		if rec.Code != tc.code || rec.Header().Get("Location") != tc.location {
+			t.Errorf("%s = %d to %q, want %d to %q", tc.path, rec.Code, rec.Header().Get("Location"), tc.code, tc.location)
+		}
+		if tc.code == http.StatusOK && !strings.Contains(rec.Header().Get("Content-Type"), "text/html") {
+			t.Errorf("%s content type = %q", tc.path, rec.Header().Get("Content-Type"))
+		}
+	}
+}